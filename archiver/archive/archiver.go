@@ -0,0 +1,462 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+)
+
+const (
+	fileSize   = 1000
+	deleteSize = 25
+)
+
+var terminalStatuses = []string{"SUCCEEDED", "FAILED", "EXPIRED", "CANCELED"}
+
+// blobRefs maps the attributes pointing to an offloaded blob to the one its
+// content is exported as.
+var blobRefs = map[string]string{"bodyRef": "body", "resultRef": "result"}
+
+type Archiver struct {
+	dynamodb dynamodbiface.DynamoDBAPI
+	store    blob.Store
+	clock    clock.Clock
+	blobs    blob.Store
+}
+
+// NewArchiver archives to store, blobs is where the bodies and results of
+// the schedules were offloaded to, nil when they never are.
+func NewArchiver(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	store blob.Store,
+	clock clock.Clock,
+	blobs blob.Store) *Archiver {
+
+	return &Archiver{dynamodb, store, clock, blobs}
+}
+
+type runIDGenerate func() (string, error)
+
+func randomRunID() (string, error) {
+	b := make([]byte, 4)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// generateRunID tells the files of runs in the same second apart.
+var generateRunID runIDGenerate = randomRunID
+
+// ArchiveRecords exports the schedules the time to live of the table
+// deleted, the old images of the REMOVE records of its stream, as gzipped
+// JSON Lines files to the store and then deletes their blobs, it returns how
+// many were archived. The removes of anyone else are skipped.
+func (a *Archiver) ArchiveRecords(
+	ctx context.Context,
+	records []events.DynamoDBEventRecord) (int, error) {
+
+	var items []map[string]*dynamodb.AttributeValue
+
+	for _, record := range records {
+		if expired(record) {
+			items = append(items, toItem(record.Change.OldImage))
+		}
+	}
+
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	prefix, err := a.prefix()
+
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+
+	for start := 0; start < len(items); start += fileSize {
+		end := start + fileSize
+
+		if end > len(items) {
+			end = len(items)
+		}
+
+		key := fileKey(prefix, start/fileSize+1)
+
+		if err = a.export(ctx, key, items[start:end]); err != nil {
+			return archived, err
+		}
+
+		if err = a.deleteBlobs(ctx, items[start:end]); err != nil {
+			return archived, err
+		}
+
+		archived += end - start
+	}
+
+	return archived, nil
+}
+
+// Archive exports the terminal schedules whose ttl has passed as gzipped
+// JSON Lines files to the store and then deletes them from the table along
+// with their blobs, it returns how many were archived. It is for tables
+// without a time to live, ArchiveRecords archives what it deletes otherwise.
+func (a *Archiver) Archive(ctx context.Context) (int, error) {
+	prefix, err := a.prefix()
+
+	if err != nil {
+		return 0, err
+	}
+
+	until := strconv.FormatInt(a.clock.Now().Unix(), 10)
+
+	archived := 0
+	files := 0
+	var items []map[string]*dynamodb.AttributeValue
+
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+
+		files++
+		key := fileKey(prefix, files)
+
+		if err := a.export(ctx, key, items); err != nil {
+			return err
+		}
+
+		if err := a.delete(ctx, items); err != nil {
+			return err
+		}
+
+		if err := a.deleteBlobs(ctx, items); err != nil {
+			return err
+		}
+
+		archived += len(items)
+		items = nil
+
+		return nil
+	}
+
+	add := func(item map[string]*dynamodb.AttributeValue) error {
+		items = append(items, item)
+
+		if len(items) < fileSize {
+			return nil
+		}
+
+		return flush()
+	}
+
+	shards := sharding.Count()
+
+	for _, status := range terminalStatuses {
+		for shard := 0; shard < shards; shard++ {
+			err := a.query(ctx, sharding.Key(status, shard, shards), until, add)
+
+			if err != nil {
+				return archived, err
+			}
+		}
+	}
+
+	if err = flush(); err != nil {
+		return archived, err
+	}
+
+	return archived, nil
+}
+
+// prefix names the files of a run by its time and run id.
+func (a *Archiver) prefix() (string, error) {
+	run, err := generateRunID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return a.clock.Now().UTC().Format("2006/01/02/150405") + "-" + run, nil
+}
+
+func fileKey(prefix string, file int) string {
+	return fmt.Sprintf("schedules/%s-%03d.jsonl.gz", prefix, file)
+}
+
+// query passes every item of the status partition whose ttl is not after
+// until to add. A schedule reaches its status once due, so it is not due
+// after its ttl either, apart from a canceled one that waits for its due
+// time.
+func (a *Archiver) query(
+	ctx context.Context,
+	status string,
+	until string,
+	add func(map[string]*dynamodb.AttributeValue) error) error {
+
+	index, attr := sharding.StatusIndex(sharding.Count())
+
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName()),
+		IndexName:              aws.String(index),
+		KeyConditionExpression: aws.String("#s = :s AND #d <= :t"),
+		FilterExpression:       aws.String("#t <= :t"),
+		ExpressionAttributeNames: map[string]*string{
+			"#s": aws.String(attr),
+			"#d": aws.String("dueAt"),
+			"#t": aws.String("ttl"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":s": {S: aws.String(status)},
+			":t": {N: aws.String(until)},
+		},
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+	}
+
+	for {
+		res, err := a.dynamodb.QueryWithContext(ctx, params)
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range res.Items {
+			if err = add(item); err != nil {
+				return err
+			}
+		}
+
+		if len(res.LastEvaluatedKey) == 0 {
+			return nil
+		}
+
+		params.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+func (a *Archiver) export(
+	ctx context.Context,
+	key string,
+	items []map[string]*dynamodb.AttributeValue) error {
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+
+	for _, item := range items {
+		var line map[string]interface{}
+
+		if err := dynamodbattribute.UnmarshalMap(item, &line); err != nil {
+			return err
+		}
+
+		if err := a.inline(ctx, line); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return a.store.Put(ctx, key, buf.Bytes())
+}
+
+// inline replaces the blob references of an exported schedule with their
+// content, a blob that is already gone stays referenced.
+func (a *Archiver) inline(
+	ctx context.Context,
+	line map[string]interface{}) error {
+
+	if a.blobs == nil {
+		return nil
+	}
+
+	for ref, attr := range blobRefs {
+		key, ok := line[ref].(string)
+
+		if !ok {
+			continue
+		}
+
+		body, err := a.blobs.Get(ctx, key)
+
+		if errors.Is(err, blob.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		line[attr] = string(body)
+		delete(line, ref)
+	}
+
+	return nil
+}
+
+func (a *Archiver) deleteBlobs(
+	ctx context.Context,
+	items []map[string]*dynamodb.AttributeValue) error {
+
+	if a.blobs == nil {
+		return nil
+	}
+
+	for _, item := range items {
+		for ref := range blobRefs {
+			if key, ok := item[ref]; ok && key.S != nil {
+				if err := a.blobs.Delete(ctx, *key.S); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Archiver) delete(
+	ctx context.Context,
+	items []map[string]*dynamodb.AttributeValue) error {
+
+	for start := 0; start < len(items); start += deleteSize {
+		end := start + deleteSize
+
+		if end > len(items) {
+			end = len(items)
+		}
+
+		writes := make([]*dynamodb.WriteRequest, end-start)
+
+		for i, item := range items[start:end] {
+			writes[i] = &dynamodb.WriteRequest{
+				DeleteRequest: &dynamodb.DeleteRequest{
+					Key: map[string]*dynamodb.AttributeValue{
+						"id": item["id"],
+					},
+				},
+			}
+		}
+
+		if err := a.write(ctx, writes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Archiver) write(
+	ctx context.Context,
+	writes []*dynamodb.WriteRequest) error {
+
+	table := tableName()
+
+	res, err := a.dynamodb.BatchWriteItemWithContext(
+		ctx,
+		&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{
+				table: writes,
+			},
+			ReturnItemCollectionMetrics: aws.String(
+				dynamodb.ReturnItemCollectionMetricsNone),
+			ReturnConsumedCapacity: aws.String(
+				dynamodb.ReturnConsumedCapacityNone),
+		})
+
+	if err != nil {
+		return err
+	}
+
+	if unprocessed := res.UnprocessedItems[table]; len(unprocessed) > 0 {
+		return a.write(ctx, unprocessed)
+	}
+
+	return nil
+}
+
+// expired tells the records of the items the time to live deleted, which
+// are removed by the DynamoDB service itself.
+func expired(record events.DynamoDBEventRecord) bool {
+	return record.EventName == "REMOVE" &&
+		record.UserIdentity != nil &&
+		record.UserIdentity.Type == "Service" &&
+		record.UserIdentity.PrincipalID == "dynamodb.amazonaws.com"
+}
+
+func toItem(
+	image map[string]events.DynamoDBAttributeValue,
+) map[string]*dynamodb.AttributeValue {
+
+	item := make(map[string]*dynamodb.AttributeValue, len(image))
+
+	for k, v := range image {
+		item[k] = toAttribute(v)
+	}
+
+	return item
+}
+
+func toAttribute(v events.DynamoDBAttributeValue) *dynamodb.AttributeValue {
+	switch v.DataType() {
+	case events.DataTypeString:
+		return &dynamodb.AttributeValue{S: aws.String(v.String())}
+	case events.DataTypeNumber:
+		return &dynamodb.AttributeValue{N: aws.String(v.Number())}
+	case events.DataTypeBinary:
+		return &dynamodb.AttributeValue{B: v.Binary()}
+	case events.DataTypeBoolean:
+		return &dynamodb.AttributeValue{BOOL: aws.Bool(v.Boolean())}
+	case events.DataTypeStringSet:
+		return &dynamodb.AttributeValue{SS: aws.StringSlice(v.StringSet())}
+	case events.DataTypeNumberSet:
+		return &dynamodb.AttributeValue{NS: aws.StringSlice(v.NumberSet())}
+	case events.DataTypeBinarySet:
+		return &dynamodb.AttributeValue{BS: v.BinarySet()}
+	case events.DataTypeMap:
+		return &dynamodb.AttributeValue{M: toItem(v.Map())}
+	case events.DataTypeList:
+		list := make([]*dynamodb.AttributeValue, len(v.List()))
+
+		for i, e := range v.List() {
+			list[i] = toAttribute(e)
+		}
+
+		return &dynamodb.AttributeValue{L: list}
+	default:
+		return &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	}
+}
+
+func tableName() string {
+	return os.Getenv("SCHEDULER_TABLE_NAME")
+}
@@ -0,0 +1,507 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Archiver", func() {
+	const table = "scheduler_v1"
+
+	var (
+		dynamo *dynamotest.DynamoDB
+		store  *fakeStore
+		blobs  *fakeStore
+		now    time.Time
+	)
+
+	put := func(id, status string, ttl int64) {
+		item := map[string]*dynamodb.AttributeValue{
+			"id":     {S: aws.String(id)},
+			"status": {S: aws.String(status)},
+			"dueAt":  {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+			"url":    {S: aws.String("https://foo.bar/do")},
+		}
+
+		if ttl > 0 {
+			item["ttl"] = &dynamodb.AttributeValue{
+				N: aws.String(strconv.FormatInt(ttl, 10)),
+			}
+		}
+
+		_, err := dynamo.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(table),
+			Item:      item,
+		})
+
+		Expect(err).To(BeNil())
+	}
+
+	exists := func(id string) bool {
+		res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(id)},
+			},
+		})
+
+		Expect(err).To(BeNil())
+
+		return len(res.Item) > 0
+	}
+
+	lines := func(body []byte) []map[string]interface{} {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		Expect(err).To(BeNil())
+
+		var all []map[string]interface{}
+		scanner := bufio.NewScanner(zr)
+
+		for scanner.Scan() {
+			var line map[string]interface{}
+			Expect(json.Unmarshal(scanner.Bytes(), &line)).To(Succeed())
+
+			all = append(all, line)
+		}
+
+		return all
+	}
+
+	archive := func() (int, error) {
+		return NewArchiver(
+			dynamo,
+			store,
+			clock.NewFake(now),
+			blobs).Archive(context.TODO())
+	}
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+		dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+		store = &fakeStore{Files: map[string][]byte{}}
+		blobs = &fakeStore{Files: map[string][]byte{}}
+		now = time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+		generateRunID = func() (string, error) {
+			return "run", nil
+		}
+
+		put("succeeded", "SUCCEEDED", now.Unix())
+		put("canceled", "CANCELED", now.Unix()-60)
+		put("later", "FAILED", now.Unix()+1)
+		put("forever", "SUCCEEDED", 0)
+		put("idle", "IDLE", now.Unix())
+	})
+
+	AfterEach(func() {
+		generateRunID = randomRunID
+	})
+
+	It("exports expired schedules", func() {
+		count, err := archive()
+
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(2))
+
+		key := "schedules/2024/06/01/103000-run-001.jsonl.gz"
+		Expect(store.Files).To(HaveKey(key))
+
+		exported := lines(store.Files[key])
+		Expect(exported).To(HaveLen(2))
+
+		var ids []interface{}
+
+		for _, line := range exported {
+			ids = append(ids, line["id"])
+			Expect(line["url"]).To(Equal("https://foo.bar/do"))
+		}
+
+		Expect(ids).To(ConsistOf("succeeded", "canceled"))
+	})
+
+	It("deletes exported schedules", func() {
+		_, err := archive()
+		Expect(err).To(BeNil())
+
+		Expect(exists("succeeded")).To(BeFalse())
+		Expect(exists("canceled")).To(BeFalse())
+	})
+
+	It("keeps other schedules", func() {
+		_, err := archive()
+		Expect(err).To(BeNil())
+
+		Expect(exists("later")).To(BeTrue())
+		Expect(exists("forever")).To(BeTrue())
+		Expect(exists("idle")).To(BeTrue())
+	})
+
+	It("splits large exports into files", func() {
+		for i := 0; i < fileSize+10; i++ {
+			put(fmt.Sprintf("bulk-%d", i), "FAILED", now.Unix())
+		}
+
+		count, err := archive()
+
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(fileSize + 12))
+		Expect(store.Files).To(HaveLen(2))
+	})
+
+	It("keeps files of runs in the same second apart", func() {
+		generateRunID = randomRunID
+
+		_, err := archive()
+		Expect(err).To(BeNil())
+
+		put("other", "FAILED", now.Unix())
+
+		_, err = archive()
+		Expect(err).To(BeNil())
+
+		Expect(store.Files).To(HaveLen(2))
+	})
+
+	It("returns error of run id", func() {
+		generateRunID = func() (string, error) {
+			return "", fmt.Errorf("rand error")
+		}
+
+		count, err := archive()
+
+		Expect(err).To(MatchError("rand error"))
+		Expect(count).To(BeZero())
+		Expect(exists("succeeded")).To(BeTrue())
+	})
+
+	Context("with blobs", func() {
+		BeforeEach(func() {
+			_, err := dynamo.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(table),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("succeeded")},
+				},
+				UpdateExpression: aws.String("SET bodyRef = :b, resultRef = :r"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":b": {S: aws.String("bodies/succeeded")},
+					":r": {S: aws.String("results/succeeded")},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			blobs.Files["bodies/succeeded"] = []byte("large body")
+			blobs.Files["results/succeeded"] = []byte("large result")
+		})
+
+		It("exports content of blobs", func() {
+			_, err := archive()
+			Expect(err).To(BeNil())
+
+			key := "schedules/2024/06/01/103000-run-001.jsonl.gz"
+
+			for _, line := range lines(store.Files[key]) {
+				if line["id"] != "succeeded" {
+					continue
+				}
+
+				Expect(line["body"]).To(Equal("large body"))
+				Expect(line["result"]).To(Equal("large result"))
+				Expect(line).NotTo(HaveKey("bodyRef"))
+				Expect(line).NotTo(HaveKey("resultRef"))
+			}
+		})
+
+		It("deletes blobs of archived schedules", func() {
+			_, err := archive()
+			Expect(err).To(BeNil())
+
+			Expect(blobs.Files).To(BeEmpty())
+		})
+
+		It("keeps reference of missing blob", func() {
+			delete(blobs.Files, "results/succeeded")
+
+			_, err := archive()
+			Expect(err).To(BeNil())
+
+			key := "schedules/2024/06/01/103000-run-001.jsonl.gz"
+
+			for _, line := range lines(store.Files[key]) {
+				if line["id"] == "succeeded" {
+					Expect(line["body"]).To(Equal("large body"))
+					Expect(line["resultRef"]).To(Equal("results/succeeded"))
+				}
+			}
+		})
+
+		It("keeps references without blob store", func() {
+			_, err := NewArchiver(
+				dynamo,
+				store,
+				clock.NewFake(now),
+				nil).Archive(context.TODO())
+			Expect(err).To(BeNil())
+
+			key := "schedules/2024/06/01/103000-run-001.jsonl.gz"
+
+			for _, line := range lines(store.Files[key]) {
+				if line["id"] == "succeeded" {
+					Expect(line["bodyRef"]).To(Equal("bodies/succeeded"))
+				}
+			}
+
+			Expect(blobs.Files).To(HaveLen(2))
+		})
+
+		It("keeps schedules whose blobs could not be read", func() {
+			blobs.Error = fmt.Errorf("get error")
+
+			_, err := archive()
+
+			Expect(err).NotTo(BeNil())
+			Expect(exists("succeeded")).To(BeTrue())
+		})
+	})
+
+	Context("with shards", func() {
+		BeforeEach(func() {
+			_ = os.Setenv("SCHEDULER_SHARD_COUNT", "2")
+
+			dynamo = dynamotest.New(dynamotest.ShardedSchedulerTable(table))
+
+			for i, id := range []string{"a", "b", "c"} {
+				put(id, "SUCCEEDED", now.Unix())
+
+				_, err := dynamo.UpdateItem(&dynamodb.UpdateItemInput{
+					TableName: aws.String(table),
+					Key: map[string]*dynamodb.AttributeValue{
+						"id": {S: aws.String(id)},
+					},
+					UpdateExpression: aws.String("SET statusShard = :ss"),
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":ss": {
+							S: aws.String(sharding.Key("SUCCEEDED", i%2, 2)),
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+			}
+		})
+
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+		})
+
+		It("archives schedules of every shard", func() {
+			count, err := archive()
+
+			Expect(err).To(BeNil())
+			Expect(count).To(Equal(3))
+			Expect(exists("a")).To(BeFalse())
+			Expect(exists("b")).To(BeFalse())
+			Expect(exists("c")).To(BeFalse())
+		})
+	})
+
+	It("waits for due time of canceled schedule", func() {
+		_, err := dynamo.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("canceled")},
+			},
+			UpdateExpression: aws.String("SET dueAt = :d"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":d": {N: aws.String(strconv.FormatInt(now.Unix()+1, 10))},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		count, err := archive()
+
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(1))
+		Expect(exists("canceled")).To(BeTrue())
+	})
+
+	It("keeps schedules that could not be exported", func() {
+		store.Error = fmt.Errorf("put error")
+
+		count, err := archive()
+
+		Expect(err).NotTo(BeNil())
+		Expect(count).To(BeZero())
+		Expect(exists("succeeded")).To(BeTrue())
+		Expect(exists("canceled")).To(BeTrue())
+	})
+})
+
+var _ = Describe("ArchiveRecords", func() {
+	var (
+		store *fakeStore
+		blobs *fakeStore
+		now   time.Time
+	)
+
+	ttl := &events.DynamoDBUserIdentity{
+		Type:        "Service",
+		PrincipalID: "dynamodb.amazonaws.com",
+	}
+
+	record := func(
+		name string,
+		identity *events.DynamoDBUserIdentity,
+		id string) events.DynamoDBEventRecord {
+
+		return events.DynamoDBEventRecord{
+			EventName:    name,
+			UserIdentity: identity,
+			Change: events.DynamoDBStreamRecord{
+				OldImage: map[string]events.DynamoDBAttributeValue{
+					"id":      events.NewStringAttribute(id),
+					"status":  events.NewStringAttribute("SUCCEEDED"),
+					"dueAt":   events.NewNumberAttribute("1717237800"),
+					"bodyRef": events.NewStringAttribute("bodies/" + id),
+					"headers": events.NewMapAttribute(
+						map[string]events.DynamoDBAttributeValue{
+							"Accept": events.NewStringAttribute("*/*"),
+						}),
+				},
+			},
+		}
+	}
+
+	archive := func(records ...events.DynamoDBEventRecord) (int, error) {
+		return NewArchiver(
+			nil,
+			store,
+			clock.NewFake(now),
+			blobs).ArchiveRecords(context.TODO(), records)
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{Files: map[string][]byte{}}
+		blobs = &fakeStore{Files: map[string][]byte{
+			"bodies/expired": []byte("large body"),
+			"bodies/deleted": []byte("other body"),
+		}}
+		now = time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+		generateRunID = func() (string, error) {
+			return "run", nil
+		}
+	})
+
+	AfterEach(func() {
+		generateRunID = randomRunID
+	})
+
+	It("exports schedules deleted by time to live", func() {
+		count, err := archive(
+			record("REMOVE", ttl, "expired"),
+			record("REMOVE", nil, "deleted"),
+			record("MODIFY", nil, "modified"))
+
+		Expect(err).To(BeNil())
+		Expect(count).To(Equal(1))
+
+		key := "schedules/2024/06/01/103000-run-001.jsonl.gz"
+		Expect(store.Files).To(HaveKey(key))
+
+		zr, err := gzip.NewReader(bytes.NewReader(store.Files[key]))
+		Expect(err).To(BeNil())
+
+		var line map[string]interface{}
+		Expect(json.NewDecoder(zr).Decode(&line)).To(Succeed())
+
+		Expect(line).To(Equal(map[string]interface{}{
+			"id":      "expired",
+			"status":  "SUCCEEDED",
+			"dueAt":   float64(1717237800),
+			"body":    "large body",
+			"headers": map[string]interface{}{"Accept": "*/*"},
+		}))
+	})
+
+	It("deletes blobs of exported schedules", func() {
+		_, err := archive(
+			record("REMOVE", ttl, "expired"),
+			record("REMOVE", nil, "deleted"))
+
+		Expect(err).To(BeNil())
+		Expect(blobs.Files).To(Equal(map[string][]byte{
+			"bodies/deleted": []byte("other body"),
+		}))
+	})
+
+	It("exports nothing without expired schedules", func() {
+		count, err := archive(record("REMOVE", nil, "deleted"))
+
+		Expect(err).To(BeNil())
+		Expect(count).To(BeZero())
+		Expect(store.Files).To(BeEmpty())
+	})
+
+	It("returns error of export", func() {
+		store.Error = fmt.Errorf("put error")
+
+		_, err := archive(record("REMOVE", ttl, "expired"))
+
+		Expect(err).To(MatchError("put error"))
+		Expect(blobs.Files).To(HaveLen(2))
+	})
+})
+
+type fakeStore struct {
+	Error error
+	Files map[string][]byte
+}
+
+func (fs *fakeStore) Put(_ context.Context, key string, body []byte) error {
+	if fs.Error != nil {
+		return fs.Error
+	}
+
+	fs.Files[key] = body
+
+	return nil
+}
+
+func (fs *fakeStore) Get(_ context.Context, key string) ([]byte, error) {
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	if body, found := fs.Files[key]; found {
+		return body, nil
+	}
+
+	return nil, blob.ErrNotFound
+}
+
+func (fs *fakeStore) Delete(_ context.Context, key string) error {
+	if fs.Error != nil {
+		return fs.Error
+	}
+
+	delete(fs.Files, key)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+module github.com/kazimanzurrashid/aws-scheduler-go/archiver
+
+go 1.20
+
+require (
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/aws/aws-sdk-go v1.53.14
+	github.com/aws/aws-xray-sdk-go v1.8.4
+	github.com/kazimanzurrashid/aws-scheduler-go/blob v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/clock v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/dynamotest v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/sharding v0.0.0
+	github.com/onsi/ginkgo v1.16.5
+	github.com/onsi/gomega v1.33.1
+)
+
+require (
+	github.com/andybalholm/brotli v1.0.6 // indirect
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/klauspost/compress v1.17.2 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasthttp v1.50.0 // indirect
+	golang.org/x/net v0.24.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231106174013-bbf56f31fb17 // indirect
+	google.golang.org/grpc v1.59.0 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/kazimanzurrashid/aws-scheduler-go/blob => ../blob
+	github.com/kazimanzurrashid/aws-scheduler-go/clock => ../clock
+	github.com/kazimanzurrashid/aws-scheduler-go/dynamotest => ../dynamotest
+	github.com/kazimanzurrashid/aws-scheduler-go/sharding => ../sharding
+)
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-xray-sdk-go/xray"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/archiver/archive"
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const defaultDir = "archive"
+
+var archiver interface {
+	Archive(context.Context) (int, error)
+	ArchiveRecords(context.Context, []events.DynamoDBEventRecord) (int, error)
+}
+
+func handler(ctx context.Context) error {
+	count, err := archiver.Archive(ctx)
+
+	log.Printf("archived %d schedule(s)", count)
+
+	return err
+}
+
+// streamHandler archives the schedules the time to live deleted, a failed
+// batch is retried by the stream as they are no longer in the table.
+func streamHandler(ctx context.Context, e events.DynamoDBEvent) error {
+	count, err := archiver.ArchiveRecords(ctx, e.Records)
+
+	log.Printf("archived %d schedule(s)", count)
+
+	return err
+}
+
+func lambdaMode() bool {
+	return os.Getenv("AWS_LAMBDA_RUNTIME_API") != ""
+}
+
+// trace records the calls of the client in the lambda segment, a local run
+// has no segment to record them in.
+func trace(c *client.Client) {
+	if lambdaMode() {
+		xray.AWS(c)
+	}
+}
+
+func init() {
+	ses := session.Must(session.NewSession())
+
+	ddbc := dynamodb.New(ses)
+	trace(ddbc.Client)
+
+	var store blob.Store
+
+	if bucket := os.Getenv("SCHEDULER_ARCHIVE_BUCKET"); bucket != "" {
+		s3c := s3.New(ses)
+		trace(s3c.Client)
+
+		store = blob.NewS3Store(s3c, bucket)
+	} else {
+		dir := os.Getenv("SCHEDULER_ARCHIVE_DIR")
+
+		if dir == "" {
+			dir = defaultDir
+		}
+
+		store = blob.NewFileStore(dir)
+	}
+
+	var blobs blob.Store
+
+	if bucket := os.Getenv("SCHEDULER_BLOB_BUCKET"); bucket != "" {
+		s3c := s3.New(ses)
+		trace(s3c.Client)
+
+		blobs = blob.NewS3Store(s3c, bucket)
+	} else if dir := os.Getenv("SCHEDULER_BLOB_DIR"); dir != "" {
+		blobs = blob.NewFileStore(dir)
+	}
+
+	archiver = archive.NewArchiver(ddbc, store, clock.System, blobs)
+}
+
+func main() {
+	// Outside lambda it archives once, e.g. from cron or by hand.
+	if !lambdaMode() {
+		if err := handler(context.Background()); err != nil {
+			log.Fatalf("archive error: %v", err)
+		}
+
+		return
+	}
+
+	lambda.Start(streamHandler)
+}
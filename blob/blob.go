@@ -0,0 +1,18 @@
+// Package blob keeps what is too large for a table item, S3 in the stack
+// and a directory locally.
+package blob
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get for a key that was never put, Delete of
+// such a key succeeds.
+var ErrNotFound = errors.New("blob not found")
+
+type Store interface {
+	Put(ctx context.Context, key string, body []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
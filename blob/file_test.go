@@ -0,0 +1,76 @@
+package blob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileStore", func() {
+	var (
+		dir   string
+		store *FileStore
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = os.MkdirTemp("", "blob")
+		Expect(err).To(BeNil())
+
+		store = NewFileStore(dir)
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("writes file under directory", func() {
+		Expect(store.Put(
+			context.TODO(),
+			"bodies/1234",
+			[]byte("dummy"))).To(Succeed())
+
+		body, err := os.ReadFile(filepath.Join(dir, "bodies", "1234"))
+
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("dummy"))
+	})
+
+	It("reads what was put", func() {
+		Expect(store.Put(
+			context.TODO(),
+			"bodies/1234",
+			[]byte("dummy"))).To(Succeed())
+
+		body, err := store.Get(context.TODO(), "bodies/1234")
+
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("dummy"))
+	})
+
+	It("returns not found for unknown key", func() {
+		_, err := store.Get(context.TODO(), "bodies/unknown")
+
+		Expect(err).To(Equal(ErrNotFound))
+	})
+	It("deletes what was put", func() {
+		Expect(store.Put(
+			context.TODO(),
+			"bodies/1234",
+			[]byte("dummy"))).To(Succeed())
+
+		Expect(store.Delete(context.TODO(), "bodies/1234")).To(Succeed())
+
+		_, err := store.Get(context.TODO(), "bodies/1234")
+
+		Expect(err).To(Equal(ErrNotFound))
+	})
+
+	It("deletes unknown key", func() {
+		Expect(store.Delete(context.TODO(), "bodies/unknown")).To(Succeed())
+	})
+})
@@ -0,0 +1,21 @@
+module github.com/kazimanzurrashid/aws-scheduler-go/blob
+
+go 1.20
+
+require (
+	github.com/aws/aws-sdk-go v1.53.14
+	github.com/onsi/ginkgo v1.16.5
+	github.com/onsi/gomega v1.33.1
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	golang.org/x/net v0.24.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,157 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("S3Store", func() {
+	var (
+		fs    fakeS3
+		store *S3Store
+	)
+
+	BeforeEach(func() {
+		fs = fakeS3{}
+		store = NewS3Store(&fs, "blobs")
+	})
+
+	Describe("Put", func() {
+		It("puts object to bucket", func() {
+			Expect(store.Put(
+				context.TODO(),
+				"bodies/1234",
+				[]byte("dummy"))).To(Succeed())
+
+			Expect(*fs.PutInput.Bucket).To(Equal("blobs"))
+			Expect(*fs.PutInput.Key).To(Equal("bodies/1234"))
+
+			body, _ := io.ReadAll(fs.PutInput.Body)
+			Expect(string(body)).To(Equal("dummy"))
+		})
+
+		It("returns error", func() {
+			fs.Error = fmt.Errorf("put error")
+
+			Expect(store.Put(
+				context.TODO(),
+				"bodies/1234",
+				[]byte("dummy"))).NotTo(Succeed())
+		})
+	})
+
+	Describe("Get", func() {
+		It("reads object of bucket", func() {
+			fs.Body = "dummy"
+
+			body, err := store.Get(context.TODO(), "bodies/1234")
+
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(Equal("dummy"))
+			Expect(*fs.GetInput.Bucket).To(Equal("blobs"))
+			Expect(*fs.GetInput.Key).To(Equal("bodies/1234"))
+		})
+
+		It("returns not found for missing object", func() {
+			fs.Error = awserr.New(s3.ErrCodeNoSuchKey, "missing", nil)
+
+			_, err := store.Get(context.TODO(), "bodies/1234")
+
+			Expect(err).To(Equal(ErrNotFound))
+		})
+
+		It("returns error", func() {
+			fs.Error = fmt.Errorf("get error")
+
+			_, err := store.Get(context.TODO(), "bodies/1234")
+
+			Expect(err).NotTo(BeNil())
+			Expect(err).NotTo(Equal(ErrNotFound))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes object of bucket", func() {
+			Expect(store.Delete(
+				context.TODO(),
+				"bodies/1234")).To(Succeed())
+
+			Expect(*fs.DeleteInput.Bucket).To(Equal("blobs"))
+			Expect(*fs.DeleteInput.Key).To(Equal("bodies/1234"))
+		})
+
+		It("returns error", func() {
+			fs.Error = fmt.Errorf("delete error")
+
+			Expect(store.Delete(
+				context.TODO(),
+				"bodies/1234")).NotTo(Succeed())
+		})
+	})
+})
+
+type fakeS3 struct {
+	s3iface.S3API
+
+	Error    error
+	Body     string
+	PutInput *s3.PutObjectInput
+	GetInput *s3.GetObjectInput
+
+	DeleteInput *s3.DeleteObjectInput
+}
+
+func (fs *fakeS3) PutObjectWithContext(
+	_ aws.Context,
+	input *s3.PutObjectInput,
+	_ ...request.Option) (*s3.PutObjectOutput, error) {
+
+	fs.PutInput = input
+
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (fs *fakeS3) GetObjectWithContext(
+	_ aws.Context,
+	input *s3.GetObjectInput,
+	_ ...request.Option) (*s3.GetObjectOutput, error) {
+
+	fs.GetInput = input
+
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	return &s3.GetObjectOutput{
+		Body: io.NopCloser(strings.NewReader(fs.Body)),
+	}, nil
+}
+
+func (fs *fakeS3) DeleteObjectWithContext(
+	_ aws.Context,
+	input *s3.DeleteObjectInput,
+	_ ...request.Option) (*s3.DeleteObjectOutput, error) {
+
+	fs.DeleteInput = input
+
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	return &s3.DeleteObjectOutput{}, nil
+}
@@ -0,0 +1,252 @@
+// Package client is a typed client of the scheduler graphql api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 200 * time.Millisecond
+)
+
+type Client struct {
+	endpoint  string
+	http      *http.Client
+	headers   map[string]string
+	retries   int
+	backoff   time.Duration
+	mutations bool
+	persisted bool
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests through the given client instead of the
+// default one.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithHeader sets a header on every request, e.g. the authorization.
+func WithHeader(name string, value string) Option {
+	return func(c *Client) {
+		c.headers[name] = value
+	}
+}
+
+// WithRetries sets how many times a query is retried on a transport error
+// or a gateway answering it is unavailable, waiting backoff before the first
+// retry and twice as long before each next one.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithMutationRetries retries the mutations like the queries. A mutation
+// that got no answer may have run, so a retried create can create twice.
+func WithMutationRetries() Option {
+	return func(c *Client) {
+		c.mutations = true
+	}
+}
+
+// WithPersistedQueries sends the hash of a query instead of the query, the
+// query is only sent when the server does not know its hash yet.
+func WithPersistedQueries() Option {
+	return func(c *Client) {
+		c.persisted = true
+	}
+}
+
+// New creates a client of the graphql endpoint, e.g.
+// http://localhost:8080/graphql.
+func New(endpoint string, options ...Option) *Client {
+	c := &Client{
+		endpoint: endpoint,
+		http:     http.DefaultClient,
+		headers:  make(map[string]string),
+		retries:  defaultRetries,
+		backoff:  defaultBackoff,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+type persistedQuery struct {
+	Version    int    `json:"version"`
+	SHA256Hash string `json:"sha256Hash"`
+}
+
+type extensions struct {
+	PersistedQuery *persistedQuery `json:"persistedQuery"`
+}
+
+type request struct {
+	Query      string                 `json:"query,omitempty"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+	Extensions *extensions            `json:"extensions,omitempty"`
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+// queryNotFound tells the server does not know the hash of a persisted
+// query, it is sent again with the query.
+func (r *response) queryNotFound() bool {
+	for _, e := range r.Errors {
+		if e.Extensions.Code == "PERSISTED_QUERY_NOT_FOUND" ||
+			e.Message == "PersistedQueryNotFound" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// do runs the query and decodes its data into out. A query that did not get
+// an answer is sent again, a mutation only with mutation retries.
+func (c *Client) do(
+	ctx context.Context,
+	query string,
+	variables map[string]interface{},
+	out interface{}) error {
+
+	payload := request{Query: query, Variables: variables}
+
+	if c.persisted {
+		sum := sha256.Sum256([]byte(query))
+		payload.Extensions = &extensions{
+			PersistedQuery: &persistedQuery{
+				Version:    1,
+				SHA256Hash: hex.EncodeToString(sum[:]),
+			},
+		}
+		payload.Query = ""
+	}
+
+	retries := c.retries
+
+	if strings.HasPrefix(query, "mutation") && !c.mutations {
+		retries = 0
+	}
+
+	res, err := c.retry(ctx, payload, retries)
+
+	if err == nil && c.persisted && res.queryNotFound() {
+		payload.Query = query
+		res, err = c.retry(ctx, payload, retries)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(res.Errors) > 0 {
+		return newError(res.Errors)
+	}
+
+	return json.Unmarshal(res.Data, out)
+}
+
+// retry sends the payload until it gets an answer, the error is not worth
+// retrying or it was retried as many times as given.
+func (c *Client) retry(
+	ctx context.Context,
+	payload request,
+	retries int) (*response, error) {
+
+	body, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := c.backoff
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, body)
+
+		if err == nil || !retryable(err) || attempt >= retries {
+			return res, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		backoff *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, body []byte) (*response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.endpoint,
+		bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := c.http.Do(req)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, &TransportError{err}
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	buff, err := io.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, &TransportError{err}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &StatusError{
+			Code: res.StatusCode,
+			Body: strings.TrimSpace(string(buff)),
+		}
+	}
+
+	var ret response
+
+	if err = json.Unmarshal(buff, &ret); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return &ret, nil
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server   *httptest.Server
+		attempts int32
+		status   int
+		header   string
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&attempts, 0)
+		status = http.StatusServiceUnavailable
+
+		server = httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get("Authorization")
+
+				if atomic.AddInt32(&attempts, 1) < 3 {
+					w.WriteHeader(status)
+					return
+				}
+
+				_, _ = w.Write([]byte(
+					`{"data":{"cancel":true,"get":{"id":"1234"}}}`))
+			}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retries unavailable", func() {
+		c := New(server.URL, WithRetries(3, time.Millisecond))
+
+		schedule, err := c.Get(context.TODO(), "1234")
+
+		Expect(err).To(BeNil())
+		Expect(schedule.ID).To(Equal("1234"))
+		Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(3))
+	})
+
+	It("does not retry mutation", func() {
+		c := New(server.URL, WithRetries(3, time.Millisecond))
+
+		_, err := c.Cancel(context.TODO(), "1234")
+
+		var se *StatusError
+
+		Expect(errors.As(err, &se)).To(BeTrue())
+		Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(1))
+	})
+
+	It("retries mutation with mutation retries", func() {
+		c := New(
+			server.URL,
+			WithRetries(3, time.Millisecond),
+			WithMutationRetries())
+
+		Expect(c.Cancel(context.TODO(), "1234")).To(BeTrue())
+		Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(3))
+	})
+
+	It("gives up after retries", func() {
+		c := New(server.URL, WithRetries(1, time.Millisecond))
+
+		_, err := c.Get(context.TODO(), "1234")
+
+		var se *StatusError
+
+		Expect(errors.As(err, &se)).To(BeTrue())
+		Expect(se.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(2))
+	})
+
+	It("does not retry client error", func() {
+		status = http.StatusBadRequest
+		c := New(server.URL, WithRetries(3, time.Millisecond))
+
+		_, err := c.Get(context.TODO(), "1234")
+
+		Expect(err).To(HaveOccurred())
+		Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(1))
+	})
+
+	It("retries transport error", func() {
+		c := New("http://127.0.0.1:1/graphql", WithRetries(2, time.Millisecond))
+
+		_, err := c.Get(context.TODO(), "1234")
+
+		var te *TransportError
+
+		Expect(errors.As(err, &te)).To(BeTrue())
+	})
+
+	It("sets headers", func() {
+		c := New(
+			server.URL,
+			WithRetries(3, time.Millisecond),
+			WithHeader("Authorization", "token 123"))
+
+		_, _ = c.Cancel(context.TODO(), "1234")
+
+		Expect(header).To(Equal("token 123"))
+	})
+
+	It("sends query of unknown hash", func() {
+		var bodies []request
+
+		persisted := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var body request
+
+				Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+				bodies = append(bodies, body)
+
+				if body.Query == "" {
+					_, _ = w.Write([]byte(`{"errors":[{
+						"message":"PersistedQueryNotFound",
+						"extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`))
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"data":{"cancel":true}}`))
+			}))
+		defer persisted.Close()
+
+		c := New(persisted.URL, WithPersistedQueries())
+
+		Expect(c.Cancel(context.TODO(), "1234")).To(BeTrue())
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[0].Query).To(BeEmpty())
+		Expect(bodies[0].Extensions.PersistedQuery.Version).To(Equal(1))
+		Expect(bodies[0].Extensions.PersistedQuery.SHA256Hash).To(HaveLen(64))
+		Expect(bodies[1].Query).To(ContainSubstring("cancel"))
+		Expect(bodies[1].Extensions).To(Equal(bodies[0].Extensions))
+	})
+})
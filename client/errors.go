@@ -0,0 +1,103 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound is returned for a schedule that does not exist.
+var ErrNotFound = errors.New("schedule not found")
+
+// validationCodes are the codes of the errors of an input the api rejects,
+// the ones of its resolvers and the ones of the graphql document.
+var validationCodes = map[string]bool{
+	"BAD_USER_INPUT":            true,
+	"GRAPHQL_VALIDATION_FAILED": true,
+}
+
+// ValidationError is an input the api rejected, sending it again fails the
+// same way.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Error holds the errors of a query that are not about its input.
+type Error struct {
+	Messages []string
+}
+
+func (e *Error) Error() string {
+	return strings.Join(e.Messages, "; ")
+}
+
+// StatusError is a response other than OK, the body is the one of the api.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+}
+
+// TransportError is a request that did not get a response.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// graphqlError is an error of the response.
+type graphqlError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+func newError(errs []graphqlError) error {
+	messages := make([]string, len(errs))
+
+	for i, e := range errs {
+		if validationCodes[e.Extensions.Code] {
+			return &ValidationError{e.Message}
+		}
+
+		messages[i] = e.Message
+	}
+
+	return &Error{messages}
+}
+
+func retryable(err error) bool {
+	var te *TransportError
+
+	if errors.As(err, &te) {
+		return true
+	}
+
+	var se *StatusError
+
+	if errors.As(err, &se) {
+		switch se.Code {
+		case http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
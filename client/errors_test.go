@@ -0,0 +1,40 @@
+package client
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	Describe("newError", func() {
+		coded := func(message, code string) graphqlError {
+			e := graphqlError{Message: message}
+			e.Extensions.Code = code
+
+			return e
+		}
+
+		It("maps bad user input to validation error", func() {
+			Expect(newError([]graphqlError{
+				coded("invalid url", "BAD_USER_INPUT"),
+			})).To(Equal(&ValidationError{"invalid url"}))
+		})
+
+		It("maps validation failed to validation error", func() {
+			err := newError([]graphqlError{
+				coded(
+					`Variable "$method" got invalid value "TRACE".`,
+					"GRAPHQL_VALIDATION_FAILED"),
+			})
+
+			Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+		})
+
+		It("keeps other errors", func() {
+			Expect(newError([]graphqlError{
+				{Message: "a"},
+				coded("b", "INTERNAL"),
+			})).To(MatchError("a; b"))
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package client
+
+import "context"
+
+// Iterator walks the schedules of a list page by page, following the cursor.
+//
+//	it := c.List(client.ListInput{Status: client.StatusIdle})
+//	for it.Next(ctx) {
+//		fmt.Println(it.Schedule().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	client *Client
+	input  ListInput
+	page   []*Schedule
+	index  int
+	done   bool
+	err    error
+}
+
+// Next moves to the next schedule, fetching the next page when the current
+// one is used up, and returns false once there is none or on an error.
+func (it *Iterator) Next(ctx context.Context) bool {
+	it.index++
+
+	for it.index >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		list, err := it.client.ListPage(ctx, it.input)
+
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = list.Schedules
+		it.index = 0
+		it.done = list.Cursor == nil
+
+		if !it.done {
+			it.input.Cursor = *list.Cursor
+		}
+	}
+
+	return true
+}
+
+// Schedule is the current schedule, valid after Next returned true.
+func (it *Iterator) Schedule() *Schedule {
+	return it.page[it.index]
+}
+
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Iterator", func() {
+	var c *Client
+
+	BeforeEach(func() {
+		configure()
+
+		c = New(endpoint)
+	})
+
+	It("walks every page", func() {
+		var ids []string
+
+		for i := 1; i <= 5; i++ {
+			id, err := c.Create(context.TODO(), CreateInput{
+				DueAt:  time.Now().Add(time.Duration(i) * time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+			})
+			Expect(err).To(BeNil())
+
+			ids = append(ids, id)
+		}
+
+		it := c.List(ListInput{Status: StatusIdle, Limit: 2})
+		var seen []string
+
+		for it.Next(context.TODO()) {
+			seen = append(seen, it.Schedule().ID)
+		}
+
+		Expect(it.Err()).To(BeNil())
+		Expect(seen).To(ConsistOf(ids))
+	})
+
+	It("stops without schedules", func() {
+		it := c.List(ListInput{Status: StatusFailed})
+
+		Expect(it.Next(context.TODO())).To(BeFalse())
+		Expect(it.Err()).To(BeNil())
+	})
+
+	It("stops on error", func() {
+		it := c.List(ListInput{Limit: 500})
+
+		Expect(it.Next(context.TODO())).To(BeFalse())
+
+		var ve *ValidationError
+
+		Expect(errors.As(it.Err(), &ve)).To(BeTrue())
+	})
+})
@@ -0,0 +1,69 @@
+package client
+
+import "time"
+
+const (
+	StatusIdle      = "IDLE"
+	StatusQueued    = "QUEUED"
+	StatusSucceeded = "SUCCEEDED"
+	StatusCanceled  = "CANCELED"
+	StatusFailed    = "FAILED"
+	StatusExpired   = "EXPIRED"
+
+	OrderDueAt     = "DUE_AT"
+	OrderCreatedAt = "CREATED_AT"
+
+	DirectionDesc = "DESC"
+	DirectionAsc  = "ASC"
+)
+
+type Schedule struct {
+	ID          string            `json:"id"`
+	DueAt       time.Time         `json:"dueAt"`
+	URL         string            `json:"url"`
+	Method      string            `json:"method"`
+	Headers     map[string]string `json:"headers"`
+	Body        *string           `json:"body"`
+	Status      string            `json:"status"`
+	StartedAt   *time.Time        `json:"startedAt"`
+	CompletedAt *time.Time        `json:"completedAt"`
+	CanceledAt  *time.Time        `json:"canceledAt"`
+	Result      *string           `json:"result"`
+	Reason      *string           `json:"reason"`
+	Lag         *int64            `json:"lag"`
+	Deadline    *time.Time        `json:"deadline"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+type CreateInput struct {
+	DueAt   time.Time         `json:"dueAt"`
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	// Deadline and MaxLateness are exclusive, MaxLateness is in seconds.
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	MaxLateness *int64     `json:"maxLateness,omitempty"`
+}
+
+type DateRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ListInput lists by dueAt descending unless OrderBy and Direction say
+// otherwise.
+type ListInput struct {
+	Status    string     `json:"status,omitempty"`
+	DueAt     *DateRange `json:"dueAt,omitempty"`
+	OrderBy   string     `json:"orderBy,omitempty"`
+	Direction string     `json:"direction,omitempty"`
+	Cursor    string     `json:"cursor,omitempty"`
+	Limit     int        `json:"limit,omitempty"`
+}
+
+type List struct {
+	Schedules []*Schedule `json:"schedules"`
+	Cursor    *string     `json:"cursor"`
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const scheduleFields = `
+	id
+	dueAt
+	url
+	method
+	headers
+	body
+	status
+	startedAt
+	completedAt
+	canceledAt
+	result
+	reason
+	lag
+	deadline
+	createdAt`
+
+const (
+	createQuery = `mutation Create(
+	$dueAt: DateTime!,
+	$url: String!,
+	$method: HTTPMethod!,
+	$headers: StringMap,
+	$body: String,
+	$deadline: DateTime,
+	$maxLateness: Int) {
+	create(
+		dueAt: $dueAt,
+		url: $url,
+		method: $method,
+		headers: $headers,
+		body: $body,
+		deadline: $deadline,
+		maxLateness: $maxLateness)
+}`
+
+	getQuery = `query Get($id: ID!) {
+	get(id: $id) {` + scheduleFields + `
+	}
+}`
+
+	getManyQuery = `query Schedules($ids: [ID!]!) {
+	schedules(ids: $ids) {` + scheduleFields + `
+	}
+}`
+
+	listQuery = `query List(
+	$status: ScheduleStatus,
+	$dueAt: DateRange,
+	$orderBy: ScheduleListOrder,
+	$direction: SortDirection,
+	$cursor: String,
+	$limit: Int) {
+	list(
+		status: $status,
+		dueAt: $dueAt,
+		orderBy: $orderBy,
+		direction: $direction,
+		cursor: $cursor,
+		limit: $limit) {
+		schedules {` + scheduleFields + `
+		}
+		cursor
+	}
+}`
+
+	cancelQuery = `mutation Cancel($id: ID!) {
+	cancel(id: $id)
+}`
+)
+
+// Create schedules the request and returns the id of the schedule.
+func (c *Client) Create(
+	ctx context.Context,
+	input CreateInput) (string, error) {
+
+	variables, err := toVariables(input)
+
+	if err != nil {
+		return "", err
+	}
+
+	var out struct {
+		Create string `json:"create"`
+	}
+
+	if err = c.do(ctx, createQuery, variables, &out); err != nil {
+		return "", err
+	}
+
+	return out.Create, nil
+}
+
+// Get returns ErrNotFound for a schedule that does not exist.
+func (c *Client) Get(ctx context.Context, id string) (*Schedule, error) {
+	var out struct {
+		Get *Schedule `json:"get"`
+	}
+
+	err := c.do(ctx, getQuery, map[string]interface{}{"id": id}, &out)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if out.Get == nil {
+		return nil, ErrNotFound
+	}
+
+	return out.Get, nil
+}
+
+// GetMany returns the schedules of at most 100 ids in the same order, nil
+// for the ones that do not exist.
+func (c *Client) GetMany(
+	ctx context.Context,
+	ids []string) ([]*Schedule, error) {
+
+	var out struct {
+		Schedules []*Schedule `json:"schedules"`
+	}
+
+	err := c.do(ctx, getManyQuery, map[string]interface{}{"ids": ids}, &out)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Schedules, nil
+}
+
+// ListPage returns a single page, the next one is read with its Cursor and
+// the same filters.
+func (c *Client) ListPage(
+	ctx context.Context,
+	input ListInput) (*List, error) {
+
+	variables, err := toVariables(input)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		List *List `json:"list"`
+	}
+
+	if err = c.do(ctx, listQuery, variables, &out); err != nil {
+		return nil, err
+	}
+
+	if out.List == nil {
+		return &List{}, nil
+	}
+
+	return out.List, nil
+}
+
+// List iterates over every schedule of the input, a page of the limit at a
+// time.
+func (c *Client) List(input ListInput) *Iterator {
+	return &Iterator{client: c, input: input}
+}
+
+// Cancel returns false for a schedule that is no longer idle.
+func (c *Client) Cancel(ctx context.Context, id string) (bool, error) {
+	var out struct {
+		Cancel bool `json:"cancel"`
+	}
+
+	err := c.do(ctx, cancelQuery, map[string]interface{}{"id": id}, &out)
+
+	if err != nil {
+		return false, err
+	}
+
+	return out.Cancel, nil
+}
+
+func toVariables(input interface{}) (map[string]interface{}, error) {
+	buff, err := json.Marshal(input)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var variables map[string]interface{}
+
+	if err = json.Unmarshal(buff, &variables); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
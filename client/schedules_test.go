@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedules", func() {
+	var (
+		c       *Client
+		dueAt   time.Time
+		created string
+	)
+
+	BeforeEach(func() {
+		configure()
+
+		c = New(endpoint)
+		dueAt = time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+
+		var err error
+
+		created, err = c.Create(context.TODO(), CreateInput{
+			DueAt:   dueAt,
+			URL:     "https://foo.bar/do",
+			Method:  "POST",
+			Headers: map[string]string{"accept": "application/json"},
+			Body:    "{}",
+		})
+		Expect(err).To(BeNil())
+	})
+
+	Describe("Create", func() {
+		It("returns id", func() {
+			Expect(created).NotTo(BeEmpty())
+		})
+
+		It("returns validation error", func() {
+			_, err := c.Create(context.TODO(), CreateInput{
+				DueAt:  time.Now().Add(-time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+			})
+
+			var ve *ValidationError
+
+			Expect(errors.As(err, &ve)).To(BeTrue())
+			Expect(ve.Message).To(Equal("dueAt must be in future"))
+		})
+
+		It("returns validation error of invalid enum", func() {
+			_, err := c.Create(context.TODO(), CreateInput{
+				DueAt:  dueAt,
+				URL:    "https://foo.bar/do",
+				Method: "TRACE",
+			})
+
+			var ve *ValidationError
+
+			Expect(errors.As(err, &ve)).To(BeTrue())
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns schedule", func() {
+			s, err := c.Get(context.TODO(), created)
+
+			Expect(err).To(BeNil())
+			Expect(s.ID).To(Equal(created))
+			Expect(s.DueAt.Equal(dueAt)).To(BeTrue())
+			Expect(s.Headers).To(HaveKeyWithValue("accept", "application/json"))
+			Expect(*s.Body).To(Equal("{}"))
+			Expect(s.Status).To(Equal(StatusIdle))
+		})
+
+		It("returns not found", func() {
+			_, err := c.Get(context.TODO(), "1234")
+
+			Expect(err).To(Equal(ErrNotFound))
+		})
+
+		It("returns schedule with persisted queries", func() {
+			c = New(endpoint, WithPersistedQueries())
+
+			for i := 0; i < 2; i++ {
+				s, err := c.Get(context.TODO(), created)
+
+				Expect(err).To(BeNil())
+				Expect(s.ID).To(Equal(created))
+			}
+		})
+	})
+
+	Describe("GetMany", func() {
+		It("returns schedules in order", func() {
+			schedules, err := c.GetMany(
+				context.TODO(),
+				[]string{"1234", created})
+
+			Expect(err).To(BeNil())
+			Expect(schedules).To(HaveLen(2))
+			Expect(schedules[0]).To(BeNil())
+			Expect(schedules[1].ID).To(Equal(created))
+			Expect(*schedules[1].Body).To(Equal("{}"))
+		})
+
+		It("returns validation error", func() {
+			_, err := c.GetMany(context.TODO(), nil)
+
+			var ve *ValidationError
+
+			Expect(errors.As(err, &ve)).To(BeTrue())
+		})
+	})
+
+	Describe("Cancel", func() {
+		It("cancels idle schedule once", func() {
+			Expect(c.Cancel(context.TODO(), created)).To(BeTrue())
+			Expect(c.Cancel(context.TODO(), created)).To(BeFalse())
+
+			s, err := c.Get(context.TODO(), created)
+
+			Expect(err).To(BeNil())
+			Expect(s.Status).To(Equal(StatusCanceled))
+			Expect(s.CanceledAt).NotTo(BeNil())
+		})
+	})
+
+	Describe("ListPage", func() {
+		It("returns page of status", func() {
+			list, err := c.ListPage(context.TODO(), ListInput{
+				Status: StatusIdle,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(list.Schedules).To(HaveLen(1))
+			Expect(list.Schedules[0].ID).To(Equal(created))
+			Expect(list.Cursor).To(BeNil())
+		})
+
+		It("returns validation error", func() {
+			_, err := c.ListPage(context.TODO(), ListInput{Limit: 500})
+
+			var ve *ValidationError
+
+			Expect(errors.As(err, &ve)).To(BeTrue())
+			Expect(ve.Message).To(Equal("limit must be between 1-100"))
+		})
+
+		It("returns page in order", func() {
+			later, _ := c.Create(context.TODO(), CreateInput{
+				DueAt:  time.Now().Add(2 * time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+			})
+
+			list, err := c.ListPage(context.TODO(), ListInput{
+				OrderBy:   OrderDueAt,
+				Direction: DirectionAsc,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(list.Schedules).To(HaveLen(2))
+			Expect(list.Schedules[0].ID).To(Equal(created))
+			Expect(list.Schedules[1].ID).To(Equal(later))
+		})
+
+		It("rejects cursor of other filters", func() {
+			c.Create(context.TODO(), CreateInput{
+				DueAt:  time.Now().Add(2 * time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+			})
+
+			list, err := c.ListPage(context.TODO(), ListInput{
+				Status: StatusIdle,
+				Limit:  1,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(list.Cursor).NotTo(BeNil())
+
+			_, err = c.ListPage(context.TODO(), ListInput{
+				Status: StatusQueued,
+				Cursor: *list.Cursor,
+			})
+
+			var ve *ValidationError
+
+			Expect(errors.As(err, &ve)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+// Package clock is the time source of every module, the wall clock in
+// production and a fake one in tests and the local simulation.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+
+	// Wait blocks till the duration has passed on this clock or the context
+	// is done, whichever happens first.
+	Wait(context.Context, time.Duration)
+}
+
+type system struct{}
+
+// System is the wall clock.
+var System Clock = system{}
+
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+func (system) Wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("System", func() {
+	It("tells wall time", func() {
+		Expect(System.Now()).To(BeTemporally("~", time.Now(), time.Second))
+	})
+
+	It("waits for duration", func() {
+		start := time.Now()
+
+		System.Wait(context.TODO(), 20*time.Millisecond)
+
+		Expect(time.Since(start)).To(
+			BeNumerically(">=", 20*time.Millisecond))
+	})
+
+	It("returns when context is done", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		start := time.Now()
+
+		System.Wait(ctx, time.Hour)
+
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
@@ -0,0 +1,82 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Fake only moves when it is told to, waits are released once it is
+// advanced past them.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters map[chan struct{}]time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, waiters: make(map[chan struct{}]time.Time)}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) Wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	done := make(chan struct{})
+	f.waiters[done] = f.now.Add(d)
+	f.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		f.mu.Lock()
+		delete(f.waiters, done)
+		f.mu.Unlock()
+	}
+}
+
+// Advance moves the clock forward and returns the new time.
+func (f *Fake) Advance(d time.Duration) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.set(f.now.Add(d))
+}
+
+// Set moves the clock to the given time, it may go backward.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.set(now)
+}
+
+// Waiters is the number of waits not yet released.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+func (f *Fake) set(now time.Time) time.Time {
+	f.now = now
+
+	for done, at := range f.waiters {
+		if !at.After(now) {
+			close(done)
+			delete(f.waiters, done)
+		}
+	}
+
+	return now
+}
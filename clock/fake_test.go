@@ -0,0 +1,77 @@
+package clock
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fake", func() {
+	var (
+		start time.Time
+		fake  *Fake
+	)
+
+	wait := func(ctx context.Context, d time.Duration) chan struct{} {
+		done := make(chan struct{})
+
+		go func() {
+			fake.Wait(ctx, d)
+			close(done)
+		}()
+
+		Eventually(fake.Waiters).Should(Equal(1))
+
+		return done
+	}
+
+	BeforeEach(func() {
+		start = time.Unix(1600000000, 0)
+		fake = NewFake(start)
+	})
+
+	It("stands still", func() {
+		Expect(fake.Now()).To(Equal(start))
+		Expect(fake.Now()).To(Equal(start))
+	})
+
+	It("advances", func() {
+		Expect(fake.Advance(time.Minute)).To(Equal(start.Add(time.Minute)))
+		Expect(fake.Now()).To(Equal(start.Add(time.Minute)))
+	})
+
+	It("sets time", func() {
+		fake.Set(start.Add(-time.Hour))
+
+		Expect(fake.Now()).To(Equal(start.Add(-time.Hour)))
+	})
+
+	It("does not wait for non positive duration", func() {
+		fake.Wait(context.TODO(), 0)
+
+		Expect(fake.Waiters()).To(BeZero())
+	})
+
+	It("releases wait once advanced past it", func() {
+		done := wait(context.TODO(), time.Minute)
+
+		fake.Advance(59 * time.Second)
+		Consistently(done, "50ms").ShouldNot(BeClosed())
+
+		fake.Advance(time.Second)
+		Eventually(done).Should(BeClosed())
+		Expect(fake.Waiters()).To(BeZero())
+	})
+
+	It("releases wait when context is done", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		done := wait(ctx, time.Minute)
+
+		cancel()
+
+		Eventually(done).Should(BeClosed())
+		Expect(fake.Waiters()).To(BeZero())
+	})
+})
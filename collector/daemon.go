@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
+)
+
+// daemon collects on an interval for as long as it holds the lease, every
+// replica runs one and only the leader collects.
+type daemon struct {
+	storage  storage.Storage
+	lease    storage.Lease
+	clock    clock.Clock
+	interval time.Duration
+
+	mu     sync.RWMutex
+	ready  bool
+	leader bool
+}
+
+type status struct {
+	Ready  bool `json:"ready"`
+	Leader bool `json:"leader"`
+}
+
+// run collects till the context is done and then gives up the lease, so
+// another replica takes over without waiting for it to expire.
+func (d *daemon) run(ctx context.Context) {
+	for {
+		if err := d.tick(ctx); err != nil {
+			log.Printf("collect error: %v", err)
+		}
+
+		d.clock.Wait(ctx, d.interval)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if d.lease == nil {
+		return
+	}
+
+	// The context is already done, the release gets its own.
+	release, cancel := context.WithTimeout(
+		context.Background(),
+		5*time.Second)
+	defer cancel()
+
+	if err := d.lease.Release(release); err != nil {
+		log.Printf("lease release error: %v", err)
+	}
+}
+
+// tick collects once when this replica is the leader, a lease or storage
+// error marks it not ready till a later tick succeeds.
+func (d *daemon) tick(ctx context.Context) error {
+	leader := true
+
+	if d.lease != nil {
+		acquired, err := d.lease.Acquire(ctx)
+
+		if err != nil {
+			d.set(false, false)
+			return err
+		}
+
+		leader = acquired
+	}
+
+	if !leader {
+		d.set(true, false)
+		return nil
+	}
+
+	err := d.storage.Update(ctx)
+	d.set(err == nil, true)
+
+	return err
+}
+
+func (d *daemon) set(ready, leader bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.ready = ready
+	d.leader = leader
+}
+
+func (d *daemon) status() status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return status{Ready: d.ready, Leader: d.leader}
+}
+
+// handleHealth answers the liveness probe, the process serving it is alive.
+func (d *daemon) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// handleReady answers the readiness probe with the outcome of the last tick.
+func (d *daemon) handleReady(w http.ResponseWriter, _ *http.Request) {
+	s := d.status()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !s.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(s)
+}
+
+func (d *daemon) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", d.handleHealth)
+	mux.HandleFunc("/readyz", d.handleReady)
+
+	return mux
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("daemon", func() {
+	var (
+		storage fakeStorage
+		lease   fakeLease
+		fake    *clock.Fake
+		d       *daemon
+	)
+
+	BeforeEach(func() {
+		storage = fakeStorage{}
+		lease = fakeLease{Acquired: true}
+		fake = clock.NewFake(time.Unix(1600000000, 0))
+		d = &daemon{
+			storage:  &storage,
+			lease:    &lease,
+			clock:    fake,
+			interval: time.Minute,
+		}
+	})
+
+	Describe("tick", func() {
+		It("collects as leader", func() {
+			Expect(d.tick(context.TODO())).To(Succeed())
+
+			Expect(storage.Called).To(BeTrue())
+			Expect(d.status()).To(Equal(status{Ready: true, Leader: true}))
+		})
+
+		It("collects without lease", func() {
+			d.lease = nil
+
+			Expect(d.tick(context.TODO())).To(Succeed())
+
+			Expect(storage.Called).To(BeTrue())
+			Expect(d.status()).To(Equal(status{Ready: true, Leader: true}))
+		})
+
+		It("does not collect as follower", func() {
+			lease.Acquired = false
+
+			Expect(d.tick(context.TODO())).To(Succeed())
+
+			Expect(storage.Called).To(BeFalse())
+			Expect(d.status()).To(Equal(status{Ready: true, Leader: false}))
+		})
+
+		It("is not ready when lease fails", func() {
+			lease.AcquireError = fmt.Errorf("lease error")
+
+			Expect(d.tick(context.TODO())).NotTo(Succeed())
+
+			Expect(storage.Called).To(BeFalse())
+			Expect(d.status().Ready).To(BeFalse())
+		})
+
+		It("is not ready when collect fails", func() {
+			storage.Error = fmt.Errorf("update error")
+
+			Expect(d.tick(context.TODO())).NotTo(Succeed())
+
+			Expect(d.status()).To(Equal(status{Ready: false, Leader: true}))
+		})
+	})
+
+	Describe("run", func() {
+		It("collects every interval and releases lease when done", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			done := make(chan struct{})
+
+			go func() {
+				d.run(ctx)
+				close(done)
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+			fake.Advance(time.Minute)
+			Eventually(lease.acquires).Should(Equal(2))
+			Eventually(fake.Waiters).Should(Equal(1))
+
+			cancel()
+
+			Eventually(done).Should(BeClosed())
+			Expect(lease.Released).To(BeTrue())
+		})
+	})
+
+	Describe("routes", func() {
+		serve := func(path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			d.routes().ServeHTTP(
+				rec,
+				httptest.NewRequest(http.MethodGet, path, nil))
+
+			return rec
+		}
+
+		It("answers health", func() {
+			Expect(serve("/healthz").Code).To(Equal(http.StatusOK))
+		})
+
+		It("is not ready before first tick", func() {
+			Expect(serve("/readyz").Code).To(
+				Equal(http.StatusServiceUnavailable))
+		})
+
+		It("is ready after tick", func() {
+			lease.Acquired = false
+			Expect(d.tick(context.TODO())).To(Succeed())
+
+			rec := serve("/readyz")
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(
+				MatchJSON(`{"ready":true,"leader":false}`))
+		})
+	})
+})
+
+type fakeLease struct {
+	mu           sync.Mutex
+	Acquired     bool
+	AcquireError error
+	Acquires     int
+	Released     bool
+}
+
+func (l *fakeLease) Acquire(_ context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Acquires++
+
+	return l.Acquired, l.AcquireError
+}
+
+func (l *fakeLease) Release(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Released = true
+
+	return nil
+}
+
+func (l *fakeLease) acquires() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.Acquires
+}
@@ -2,30 +2,170 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-xray-sdk-go/xray"
+	_ "github.com/lib/pq"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
 )
 
-var database storage.Storage
+const (
+	defaultPort     = "8080"
+	defaultInterval = 60
+)
+
+var (
+	ses      *session.Session
+	database storage.Storage
+)
 
 func handler(ctx context.Context) error {
 	return database.Update(ctx)
 }
 
+func daemonMode() bool {
+	return os.Getenv("SCHEDULER_COLLECTOR_DAEMON") == "true"
+}
+
+// trace records the calls of the client in the lambda segment, a daemon has
+// no segment to record them in.
+func trace(c *client.Client) {
+	if !daemonMode() {
+		xray.AWS(c)
+	}
+}
+
 func init() {
-	ses := session.Must(session.NewSession())
+	ses = session.Must(session.NewSession())
+
+	var queue storage.Queue
+
+	if url := os.Getenv("SCHEDULER_QUEUE_URL"); url != "" {
+		config := aws.NewConfig()
+
+		// A local stand-in such as ElasticMQ takes the place of SQS.
+		if endpoint := os.Getenv("SCHEDULER_QUEUE_ENDPOINT"); endpoint != "" {
+			config = config.WithEndpoint(endpoint)
+		}
+
+		sqsc := sqs.New(ses, config)
+		trace(sqsc.Client)
+
+		queue = storage.NewSQS(sqsc, url, clock.System)
+	}
+
+	if os.Getenv("SCHEDULER_STORAGE") == "postgres" {
+		db, err := sql.Open("postgres", os.Getenv("SCHEDULER_DATABASE_URL"))
+
+		if err != nil {
+			log.Fatalf("storage create error: %v", err)
+			return
+		}
+
+		database = storage.NewPostgres(db, clock.System, queue)
+
+		return
+	}
 
 	ddbc := dynamodb.New(ses)
-	xray.AWS(ddbc.Client)
+	trace(ddbc.Client)
+
+	database = storage.NewDatabase(ddbc, clock.System, queue)
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
 
-	database = storage.NewDatabase(ddbc)
+	return fallback
+}
+
+// serve runs the collector as a long running process instead of a lambda,
+// replicas elect the one collecting through the lease table when it is set.
+func serve() {
+	interval := time.Duration(envInt(
+		"SCHEDULER_COLLECTOR_INTERVAL_SECONDS",
+		defaultInterval)) * time.Second
+
+	d := &daemon{
+		storage:  database,
+		clock:    clock.System,
+		interval: interval,
+	}
+
+	if os.Getenv("SCHEDULER_LEASE_TABLE_NAME") != "" {
+		holder := os.Getenv("SCHEDULER_LEASE_HOLDER")
+
+		if holder == "" {
+			holder, _ = os.Hostname()
+		}
+
+		// The leader renews every interval, the lease outlives a few missed
+		// renewals before another replica takes over.
+		duration := time.Duration(envInt(
+			"SCHEDULER_LEASE_SECONDS",
+			int(3*interval/time.Second))) * time.Second
+
+		ddbc := dynamodb.New(ses)
+		trace(ddbc.Client)
+
+		d.lease = storage.NewLease(ddbc, clock.System, holder, duration)
+	}
+
+	port := os.Getenv("PORT")
+
+	if port == "" {
+		port = defaultPort
+	}
+
+	server := &http.Server{Addr: ":" + port, Handler: d.routes()}
+
+	go func() {
+		err := server.ListenAndServe()
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("http serve error: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM)
+	defer stop()
+
+	d.run(ctx)
+
+	shutdown, cancel := context.WithTimeout(
+		context.Background(),
+		5*time.Second)
+	defer cancel()
+
+	_ = server.Shutdown(shutdown)
 }
 
 func main() {
+	if daemonMode() {
+		serve()
+		return
+	}
+
 	lambda.Start(handler)
 }
@@ -38,10 +38,11 @@ type fakeStorage struct {
 	storage.Storage
 
 	Called bool
+	Error  error
 }
 
 //goland:noinspection GoUnusedParameter
 func (srv *fakeStorage) Update(ctx context.Context) error {
 	srv.Called = true
-	return nil
+	return srv.Error
 }
@@ -2,15 +2,18 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strconv"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
 )
 
 const (
@@ -18,37 +21,76 @@ const (
 	scheduleStatusQueued = "QUEUED"
 )
 
+// defaultLookahead queues the schedules due before the next run of the one
+// minute rule now, the worker holds them until their exact second and they
+// can no longer be canceled once queued. A lookahead of 0 queues only what
+// is already due.
+const defaultLookahead = 60
+
 type Storage interface {
 	Update(context.Context) error
 }
 
 type Database struct {
 	dynamodb dynamodbiface.DynamoDBAPI
+	clock    clock.Clock
+	queue    Queue
 }
 
-func NewDatabase(dynamodb dynamodbiface.DynamoDBAPI) *Database {
-	return &Database{dynamodb}
+// NewDatabase sends what it queues to the queue when there is one, the
+// workers are fed by the table stream otherwise.
+func NewDatabase(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	clock clock.Clock,
+	queue Queue) *Database {
+
+	return &Database{dynamodb, clock, queue}
 }
 
 func (srv *Database) Update(ctx context.Context) error {
 	table := tableName()
-	startKey := make(map[string]*dynamodb.AttributeValue)
-	now := strconv.FormatInt(time.Now().Unix(), 10)
+	until := strconv.FormatInt(srv.clock.Now().Unix()+lookahead(), 10)
+	shards := sharding.Count()
 
 	g, _ := errgroup.WithContext(ctx)
 
+	for shard := 0; shard < shards; shard++ {
+		index := shard
+
+		g.Go(func() error {
+			return srv.collect(ctx, g, table, until, index, shards)
+		})
+	}
+
+	return g.Wait()
+}
+
+func (srv *Database) collect(
+	ctx context.Context,
+	g *errgroup.Group,
+	table string,
+	until string,
+	shard int,
+	shards int) error {
+
+	index, attr := sharding.StatusIndex(shards)
+	startKey := make(map[string]*dynamodb.AttributeValue)
+
 	for {
 		params := &dynamodb.QueryInput{
 			TableName:              aws.String(table),
-			IndexName:              aws.String("ix_status_dueAt"),
+			IndexName:              aws.String(index),
 			KeyConditionExpression: aws.String("#s = :s AND #da <= :da"),
 			ExpressionAttributeNames: map[string]*string{
-				"#s":  aws.String("status"),
+				"#s":  aws.String(attr),
 				"#da": aws.String("dueAt"),
 			},
 			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-				":s":  {S: aws.String(scheduleStatusIdle)},
-				":da": {N: aws.String(now)},
+				":s": {
+					S: aws.String(
+						sharding.Key(scheduleStatusIdle, shard, shards)),
+				},
+				":da": {N: aws.String(until)},
 			},
 			ReturnConsumedCapacity: aws.String(
 				dynamodb.ReturnConsumedCapacityNone),
@@ -72,34 +114,108 @@ func (srv *Database) Update(ctx context.Context) error {
 			localItems := chunk
 
 			g.Go(func() error {
-				writes := make([]*dynamodb.WriteRequest, len(localItems))
-
-				for index, item := range localItems {
-					item["status"] = &dynamodb.AttributeValue{
-						S: aws.String(scheduleStatusQueued),
-					}
-
-					write := &dynamodb.WriteRequest{
-						PutRequest: &dynamodb.PutRequest{
-							Item: item,
-						},
-					}
-
-					writes[index] = write
+				err := srv.update(ctx, table, writes(
+					localItems,
+					scheduleStatusQueued,
+					shard,
+					shards))
+
+				if err != nil || srv.queue == nil {
+					return err
 				}
 
-				return srv.update(ctx, table, writes)
+				return srv.send(ctx, table, localItems, shard, shards)
 			})
 		}
 
 		if len(res.LastEvaluatedKey) > 0 {
 			startKey = res.LastEvaluatedKey
 		} else {
-			break
+			return nil
 		}
 	}
+}
 
-	return g.Wait()
+// send hands the queued items over to the queue, the ones it did not take
+// are put back to idle for the next run.
+func (srv *Database) send(
+	ctx context.Context,
+	table string,
+	items []map[string]*dynamodb.AttributeValue,
+	shard int,
+	shards int) error {
+
+	messages := make([]Message, len(items))
+
+	for index, item := range items {
+		dueAt, _ := strconv.ParseInt(aws.StringValue(item["dueAt"].N), 10, 64)
+
+		messages[index] = Message{
+			ID:    aws.StringValue(item["id"].S),
+			DueAt: dueAt,
+		}
+	}
+
+	unsent, err := srv.queue.Send(ctx, messages)
+
+	if err == nil {
+		return nil
+	}
+
+	if len(unsent) == 0 {
+		return err
+	}
+
+	ids := make(map[string]bool, len(unsent))
+
+	for _, m := range unsent {
+		ids[m.ID] = true
+	}
+
+	var released []map[string]*dynamodb.AttributeValue
+
+	for _, item := range items {
+		if ids[aws.StringValue(item["id"].S)] {
+			released = append(released, item)
+		}
+	}
+
+	if rerr := srv.update(ctx, table, writes(
+		released,
+		scheduleStatusIdle,
+		shard,
+		shards)); rerr != nil {
+		return fmt.Errorf("%w, release failed: %v", err, rerr)
+	}
+
+	return err
+}
+
+func writes(
+	items []map[string]*dynamodb.AttributeValue,
+	status string,
+	shard int,
+	shards int) []*dynamodb.WriteRequest {
+
+	requests := make([]*dynamodb.WriteRequest, len(items))
+
+	for index, item := range items {
+		item["status"] = &dynamodb.AttributeValue{S: aws.String(status)}
+
+		if shards > 1 {
+			item["statusShard"] = &dynamodb.AttributeValue{
+				S: aws.String(sharding.Key(status, shard, shards)),
+			}
+		}
+
+		requests[index] = &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{
+				Item: item,
+			},
+		}
+	}
+
+	return requests
 }
 
 func (srv *Database) update(
@@ -148,3 +264,19 @@ func chunkBy(
 func tableName() string {
 	return os.Getenv("SCHEDULER_TABLE_NAME")
 }
+
+func lookahead() int64 {
+	value := os.Getenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS")
+
+	if value == "" {
+		return defaultLookahead
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil || seconds < 0 {
+		return defaultLookahead
+	}
+
+	return seconds
+}
@@ -5,12 +5,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,11 +31,13 @@ var _ = Describe("Database", func() {
 		db     *Database
 	)
 
+	now := time.Unix(1600000000, 0)
+
 	BeforeEach(func() {
 		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
 
 		dynamo = fakeDynamoDB{}
-		db = NewDatabase(&dynamo)
+		db = NewDatabase(&dynamo, clock.NewFake(now), nil)
 	})
 
 	Describe("Update", func() {
@@ -129,6 +136,17 @@ var _ = Describe("Database", func() {
 					}
 				})
 
+				It("collects up to the lookahead", func() {
+					for _, queryInput := range queryInputs {
+						until, _ := strconv.ParseInt(
+							*queryInput.ExpressionAttributeValues[":da"].N,
+							10,
+							64)
+
+						Expect(until).To(Equal(now.Unix() + defaultLookahead))
+					}
+				})
+
 				It("updates status to queued", func() {
 					for _, batchWriteInput := range batchWriteInputs {
 						for _, r := range batchWriteInput.RequestItems[table] {
@@ -147,6 +165,69 @@ var _ = Describe("Database", func() {
 				})
 			})
 
+			Context("sharded", func() {
+				var (
+					err         error
+					queryInputs []*dynamodb.QueryInput
+					writeInput  *dynamodb.BatchWriteItemInput
+				)
+
+				BeforeEach(func() {
+					_ = os.Setenv("SCHEDULER_SHARD_COUNT", "2")
+
+					dynamo.PushQueryOutput(&dynamodb.QueryOutput{
+						Items: []map[string]*dynamodb.AttributeValue{
+							{
+								"id":     {S: aws.String(id)},
+								"status": {S: aws.String(scheduleStatusIdle)},
+							},
+						},
+					})
+					dynamo.PushQueryOutput(&dynamodb.QueryOutput{})
+					dynamo.PushBatchWriteOutput(&dynamodb.BatchWriteItemOutput{})
+
+					err = db.Update(context.TODO())
+
+					queryInputs = []*dynamodb.QueryInput{
+						dynamo.PullQueryInput(),
+						dynamo.PullQueryInput(),
+					}
+
+					writeInput = dynamo.PullBatchWriteInput()
+				})
+
+				It("queries every shard in ix_statusShard_dueAt", func() {
+					var values []string
+
+					for _, queryInput := range queryInputs {
+						Expect(*queryInput.IndexName).To(
+							Equal("ix_statusShard_dueAt"))
+
+						values = append(
+							values,
+							*queryInput.ExpressionAttributeValues[":s"].S)
+					}
+
+					Expect(values).To(ConsistOf("IDLE#00", "IDLE#01"))
+				})
+
+				It("updates sharded status to queued", func() {
+					for _, r := range writeInput.RequestItems[table] {
+						Expect(*r.PutRequest.Item["statusShard"].S).To(
+							HavePrefix(scheduleStatusQueued + "#0"))
+					}
+				})
+
+				It("does not return error", func() {
+					Expect(err).To(BeNil())
+				})
+
+				AfterEach(func() {
+					_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+					dynamo.ClearState()
+				})
+			})
+
 			Context("no matching schedule", func() {
 				var err error
 
@@ -213,6 +294,34 @@ var _ = Describe("Database", func() {
 		})
 	})
 
+	Describe("lookahead", func() {
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS")
+		})
+
+		It("defaults to a minute", func() {
+			Expect(lookahead()).To(BeEquivalentTo(60))
+		})
+
+		It("turns off with zero", func() {
+			_ = os.Setenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS", "0")
+
+			Expect(lookahead()).To(BeZero())
+		})
+
+		It("reads seconds from env", func() {
+			_ = os.Setenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS", "60")
+
+			Expect(lookahead()).To(BeEquivalentTo(60))
+		})
+
+		It("ignores invalid value", func() {
+			_ = os.Setenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS", "-5")
+
+			Expect(lookahead()).To(BeEquivalentTo(defaultLookahead))
+		})
+	})
+
 	Describe("chunkBy", func() {
 		Context("100 by 25", func() {
 			var items []map[string]*dynamodb.AttributeValue
@@ -289,6 +398,8 @@ type fakeDynamoDB struct {
 
 	batchWriteInputs  list.List
 	batchWriteOutputs list.List
+
+	mutex sync.Mutex
 }
 
 //goland:noinspection GoUnusedParameter
@@ -297,6 +408,9 @@ func (db *fakeDynamoDB) QueryWithContext(
 	input *dynamodb.QueryInput,
 	options ...request.Option) (*dynamodb.QueryOutput, error) {
 
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
 	db.queryInputs.PushBack(input)
 
 	output := db.queryOutputs.Front()
@@ -316,6 +430,9 @@ func (db *fakeDynamoDB) BatchWriteItemWithContext(
 	input *dynamodb.BatchWriteItemInput,
 	options ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
 
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
 	db.batchWriteInputs.PushBack(input)
 
 	output := db.batchWriteOutputs.Front()
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database against dynamotest", func() {
+	const (
+		table  = "scheduler_v1"
+		due    = 30
+		future = 2
+	)
+
+	for _, count := range []int{1, 4} {
+		shards := count
+
+		Context("with "+strconv.Itoa(shards)+" shard(s)", func() {
+			var dynamo *dynamotest.DynamoDB
+
+			status := func(id string) string {
+				res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+					TableName: aws.String(table),
+					Key: map[string]*dynamodb.AttributeValue{
+						"id": {S: aws.String(id)},
+					},
+				})
+
+				Expect(err).To(BeNil())
+
+				return *res.Item["status"].S
+			}
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", strconv.Itoa(shards))
+
+				if shards > 1 {
+					dynamo = dynamotest.New(
+						dynamotest.ShardedSchedulerTable(table))
+				} else {
+					dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+				}
+
+				now := time.Now().Unix()
+
+				for i := 0; i < due+future; i++ {
+					dueAt := now - int64(i)
+
+					if i >= due {
+						dueAt = now + 3600
+					}
+
+					shard := i % shards
+					item := map[string]*dynamodb.AttributeValue{
+						"id":     {S: aws.String(strconv.Itoa(i))},
+						"dueAt":  {N: aws.String(strconv.FormatInt(dueAt, 10))},
+						"status": {S: aws.String(scheduleStatusIdle)},
+						"dummy": {
+							S: aws.String(sharding.Key("-", shard, shards)),
+						},
+					}
+
+					if shards > 1 {
+						item["statusShard"] = &dynamodb.AttributeValue{
+							S: aws.String(sharding.Key(
+								scheduleStatusIdle,
+								shard,
+								shards)),
+						}
+					}
+
+					_, err := dynamo.PutItem(&dynamodb.PutItemInput{
+						TableName: aws.String(table),
+						Item:      item,
+					})
+					Expect(err).To(BeNil())
+				}
+
+				dynamo.Stream(table)
+
+				Expect(NewDatabase(dynamo, clock.System, nil).Update(
+					context.TODO())).To(Succeed())
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+
+			It("queues due schedules", func() {
+				for i := 0; i < due; i++ {
+					Expect(status(strconv.Itoa(i))).To(
+						Equal(scheduleStatusQueued))
+				}
+			})
+
+			It("leaves future schedules idle", func() {
+				for i := due; i < due+future; i++ {
+					Expect(status(strconv.Itoa(i))).To(
+						Equal(scheduleStatusIdle))
+				}
+			})
+
+			It("streams queued schedules to the worker", func() {
+				records := dynamo.Stream(table).Records
+
+				Expect(records).To(HaveLen(due))
+
+				for _, record := range records {
+					Expect(record.EventName).To(Equal("MODIFY"))
+					Expect(record.Change.NewImage["status"].String()).To(
+						Equal(scheduleStatusQueued))
+				}
+			})
+		})
+	}
+
+	Context("with queue", func() {
+		var (
+			dynamo *dynamotest.DynamoDB
+			fq     fakeQueue
+			now    int64
+		)
+
+		status := func(id string) string {
+			res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(table),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(id)},
+				},
+			})
+
+			Expect(err).To(BeNil())
+
+			return *res.Item["status"].S
+		}
+
+		BeforeEach(func() {
+			_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+			dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+			fq = fakeQueue{}
+			now = time.Now().Unix()
+
+			for i := 0; i < 3; i++ {
+				_, err := dynamo.PutItem(&dynamodb.PutItemInput{
+					TableName: aws.String(table),
+					Item: map[string]*dynamodb.AttributeValue{
+						"id": {S: aws.String(strconv.Itoa(i))},
+						"dueAt": {
+							N: aws.String(strconv.FormatInt(now, 10)),
+						},
+						"status": {S: aws.String(scheduleStatusIdle)},
+						"dummy":  {S: aws.String("-")},
+					},
+				})
+				Expect(err).To(BeNil())
+			}
+		})
+
+		It("sends queued schedules", func() {
+			Expect(NewDatabase(dynamo, clock.System, &fq).Update(
+				context.TODO())).To(Succeed())
+
+			Expect(fq.Messages).To(ConsistOf(
+				Message{ID: "0", DueAt: now},
+				Message{ID: "1", DueAt: now},
+				Message{ID: "2", DueAt: now}))
+
+			for i := 0; i < 3; i++ {
+				Expect(status(strconv.Itoa(i))).To(
+					Equal(scheduleStatusQueued))
+			}
+		})
+
+		It("puts schedules back to idle when send fails", func() {
+			fq.Error = fmt.Errorf("send error")
+
+			Expect(NewDatabase(dynamo, clock.System, &fq).Update(
+				context.TODO())).NotTo(Succeed())
+
+			for i := 0; i < 3; i++ {
+				Expect(status(strconv.Itoa(i))).To(
+					Equal(scheduleStatusIdle))
+			}
+		})
+
+		It("keeps sent schedules queued when send fails later", func() {
+			fq.Error = fmt.Errorf("send error")
+			fq.Accepted = 1
+
+			Expect(NewDatabase(dynamo, clock.System, &fq).Update(
+				context.TODO())).NotTo(Succeed())
+
+			Expect(fq.Messages).To(HaveLen(1))
+
+			for i := 0; i < 3; i++ {
+				id := strconv.Itoa(i)
+				expected := scheduleStatusIdle
+
+				if id == fq.Messages[0].ID {
+					expected = scheduleStatusQueued
+				}
+
+				Expect(status(id)).To(Equal(expected))
+			}
+		})
+	})
+})
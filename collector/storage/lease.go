@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const leaseID = "collector"
+
+// Lease elects the single collector of many replicas.
+type Lease interface {
+	// Acquire takes the lease or extends it when already held, it reports
+	// false while another holder has it.
+	Acquire(context.Context) (bool, error)
+	Release(context.Context) error
+}
+
+type DynamoLease struct {
+	dynamodb dynamodbiface.DynamoDBAPI
+	clock    clock.Clock
+	holder   string
+	duration time.Duration
+}
+
+func NewLease(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	clock clock.Clock,
+	holder string,
+	duration time.Duration) *DynamoLease {
+
+	return &DynamoLease{dynamodb, clock, holder, duration}
+}
+
+func (l *DynamoLease) Acquire(ctx context.Context) (bool, error) {
+	now := l.clock.Now()
+
+	params := &dynamodb.PutItemInput{
+		TableName: aws.String(leaseTableName()),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":     {S: aws.String(leaseID)},
+			"holder": {S: aws.String(l.holder)},
+			"expiresAt": {
+				N: aws.String(
+					strconv.FormatInt(now.Add(l.duration).Unix(), 10)),
+			},
+		},
+		ConditionExpression: aws.String(
+			"attribute_not_exists(#id) OR #h = :h OR #e <= :n"),
+		ExpressionAttributeNames: map[string]*string{
+			"#id": aws.String("id"),
+			"#h":  aws.String("holder"),
+			"#e":  aws.String("expiresAt"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":h": {S: aws.String(l.holder)},
+			":n": {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		},
+		ReturnItemCollectionMetrics: aws.String(
+			dynamodb.ReturnItemCollectionMetricsNone),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+		ReturnValues: aws.String(dynamodb.ReturnValueNone),
+	}
+
+	if _, err := l.dynamodb.PutItemWithContext(ctx, params); err != nil {
+		if conditionFailed(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (l *DynamoLease) Release(ctx context.Context) error {
+	params := &dynamodb.DeleteItemInput{
+		TableName: aws.String(leaseTableName()),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(leaseID)},
+		},
+		ConditionExpression: aws.String("#h = :h"),
+		ExpressionAttributeNames: map[string]*string{
+			"#h": aws.String("holder"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":h": {S: aws.String(l.holder)},
+		},
+		ReturnItemCollectionMetrics: aws.String(
+			dynamodb.ReturnItemCollectionMetricsNone),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+		ReturnValues: aws.String(dynamodb.ReturnValueNone),
+	}
+
+	if _, err := l.dynamodb.DeleteItemWithContext(ctx, params); err != nil {
+		if conditionFailed(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func conditionFailed(err error) bool {
+	rf, ok := err.(awserr.RequestFailure)
+
+	return ok && rf.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
+
+func leaseTableName() string {
+	return os.Getenv("SCHEDULER_LEASE_TABLE_NAME")
+}
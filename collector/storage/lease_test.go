@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DynamoLease", func() {
+	const table = "scheduler_lease_v1"
+
+	var (
+		dynamo *dynamotest.DynamoDB
+		fake   *clock.Fake
+		first  *DynamoLease
+		second *DynamoLease
+	)
+
+	acquire := func(l *DynamoLease) bool {
+		acquired, err := l.Acquire(context.TODO())
+
+		Expect(err).To(BeNil())
+
+		return acquired
+	}
+
+	holder := func() string {
+		res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(leaseID)},
+			},
+		})
+
+		Expect(err).To(BeNil())
+
+		if res.Item == nil {
+			return ""
+		}
+
+		return *res.Item["holder"].S
+	}
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_LEASE_TABLE_NAME", table)
+
+		dynamo = dynamotest.New(dynamotest.LeaseTable(table))
+		fake = clock.NewFake(time.Unix(1600000000, 0))
+		first = NewLease(dynamo, fake, "first", 30*time.Second)
+		second = NewLease(dynamo, fake, "second", 30*time.Second)
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_LEASE_TABLE_NAME")
+	})
+
+	Describe("Acquire", func() {
+		It("acquires free lease", func() {
+			Expect(acquire(first)).To(BeTrue())
+			Expect(holder()).To(Equal("first"))
+		})
+
+		It("does not acquire lease of another holder", func() {
+			Expect(acquire(first)).To(BeTrue())
+			Expect(acquire(second)).To(BeFalse())
+			Expect(holder()).To(Equal("first"))
+		})
+
+		It("extends held lease", func() {
+			Expect(acquire(first)).To(BeTrue())
+
+			fake.Advance(20 * time.Second)
+			Expect(acquire(first)).To(BeTrue())
+
+			fake.Advance(20 * time.Second)
+			Expect(acquire(second)).To(BeFalse())
+		})
+
+		It("takes over expired lease", func() {
+			Expect(acquire(first)).To(BeTrue())
+
+			fake.Advance(30 * time.Second)
+
+			Expect(acquire(second)).To(BeTrue())
+			Expect(holder()).To(Equal("second"))
+			Expect(acquire(first)).To(BeFalse())
+		})
+	})
+
+	Describe("Release", func() {
+		It("frees held lease", func() {
+			Expect(acquire(first)).To(BeTrue())
+			Expect(first.Release(context.TODO())).To(Succeed())
+
+			Expect(holder()).To(BeEmpty())
+			Expect(acquire(second)).To(BeTrue())
+		})
+
+		It("keeps lease of another holder", func() {
+			Expect(acquire(first)).To(BeTrue())
+			Expect(second.Release(context.TODO())).To(Succeed())
+
+			Expect(holder()).To(Equal("first"))
+		})
+	})
+})
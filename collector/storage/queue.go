@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const (
+	maxQueueBatch = 10
+
+	// maxQueueDelay is the longest SQS holds a message back, the worker
+	// holds a schedule that is due later for the rest.
+	maxQueueDelay = 900
+
+	queueBackoff  = 50 * time.Millisecond
+	maxQueueRetry = 5
+)
+
+var errUnsent = errors.New("queue send failed: messages left unsent")
+
+// Message is a claimed schedule handed over to the workers.
+type Message struct {
+	ID    string
+	DueAt int64
+}
+
+// Queue feeds the workers instead of the table stream.
+type Queue interface {
+	// Send answers the messages it did not send along with the error, the
+	// rest are on their way to the workers.
+	Send(context.Context, []Message) ([]Message, error)
+}
+
+type SQS struct {
+	sqs   sqsiface.SQSAPI
+	url   string
+	clock clock.Clock
+}
+
+func NewSQS(sqs sqsiface.SQSAPI, url string, clock clock.Clock) *SQS {
+	return &SQS{sqs, url, clock}
+}
+
+// Send delivers the schedule ids in batches, each delayed till it is due.
+func (q *SQS) Send(
+	ctx context.Context,
+	messages []Message) ([]Message, error) {
+
+	now := q.clock.Now().Unix()
+
+	for start := 0; start < len(messages); start += maxQueueBatch {
+		end := start + maxQueueBatch
+
+		if end > len(messages) {
+			end = len(messages)
+		}
+
+		batch := messages[start:end]
+		entries := make([]*sqs.SendMessageBatchRequestEntry, len(batch))
+
+		for i, m := range batch {
+			delay := m.DueAt - now
+
+			if delay < 0 {
+				delay = 0
+			} else if delay > maxQueueDelay {
+				delay = maxQueueDelay
+			}
+
+			entries[i] = &sqs.SendMessageBatchRequestEntry{
+				Id:           aws.String(strconv.Itoa(i)),
+				MessageBody:  aws.String(m.ID),
+				DelaySeconds: aws.Int64(delay),
+			}
+		}
+
+		failed, err := q.send(ctx, entries)
+
+		if err != nil {
+			unsent := make([]Message, 0, len(failed)+len(messages)-end)
+
+			for _, e := range failed {
+				i, _ := strconv.Atoi(aws.StringValue(e.Id))
+				unsent = append(unsent, batch[i])
+			}
+
+			return append(unsent, messages[end:]...), err
+		}
+	}
+
+	return nil, nil
+}
+
+// send retries the entries that failed on the side of SQS with an
+// exponential backoff, it answers the entries it did not send with the
+// error.
+func (q *SQS) send(
+	ctx context.Context,
+	entries []*sqs.SendMessageBatchRequestEntry) (
+	[]*sqs.SendMessageBatchRequestEntry, error) {
+
+	backoff := queueBackoff
+
+	for attempt := 0; ; attempt++ {
+		res, err := q.sqs.SendMessageBatchWithContext(
+			ctx,
+			&sqs.SendMessageBatchInput{
+				QueueUrl: aws.String(q.url),
+				Entries:  entries,
+			})
+
+		if err != nil {
+			return entries, err
+		}
+
+		if len(res.Failed) == 0 {
+			return nil, nil
+		}
+
+		byID := make(map[string]*sqs.SendMessageBatchRequestEntry)
+
+		for _, e := range entries {
+			byID[*e.Id] = e
+		}
+
+		failed := make([]*sqs.SendMessageBatchRequestEntry, len(res.Failed))
+		err = nil
+
+		for i, f := range res.Failed {
+			failed[i] = byID[aws.StringValue(f.Id)]
+
+			// Only failures on the side of SQS are worth another try.
+			if aws.BoolValue(f.SenderFault) && err == nil {
+				err = fmt.Errorf(
+					"queue send failed: %s",
+					aws.StringValue(f.Message))
+			}
+		}
+
+		if err != nil {
+			return failed, err
+		}
+
+		if attempt == maxQueueRetry {
+			return failed, errUnsent
+		}
+
+		entries = failed
+		q.clock.Wait(ctx, backoff)
+		backoff *= 2
+
+		if err = ctx.Err(); err != nil {
+			return entries, err
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQS", func() {
+	const url = "https://sqs.us-east-1.amazonaws.com/123/scheduler"
+
+	var (
+		fs   fakeSQS
+		fake *clock.Fake
+		q    *SQS
+	)
+
+	now := time.Unix(1600000000, 0)
+
+	messages := func(count int) []Message {
+		var ms []Message
+
+		for i := 0; i < count; i++ {
+			ms = append(ms, Message{ID: strconv.Itoa(i), DueAt: now.Unix()})
+		}
+
+		return ms
+	}
+
+	failed := func(
+		senderFault bool,
+		ids ...string) *sqs.SendMessageBatchOutput {
+
+		var entries []*sqs.BatchResultErrorEntry
+
+		for _, id := range ids {
+			entries = append(entries, &sqs.BatchResultErrorEntry{
+				Id:          aws.String(id),
+				SenderFault: aws.Bool(senderFault),
+				Message:     aws.String("invalid"),
+			})
+		}
+
+		return &sqs.SendMessageBatchOutput{Failed: entries}
+	}
+
+	BeforeEach(func() {
+		fs = fakeSQS{}
+		fake = clock.NewFake(now)
+		q = NewSQS(&fs, url, fake)
+	})
+
+	Describe("Send", func() {
+		var (
+			unsent []Message
+			err    error
+		)
+
+		send := func(count int) {
+			unsent, err = q.Send(context.TODO(), messages(count))
+		}
+
+		It("sends in batches of ten", func() {
+			send(23)
+
+			Expect(err).To(BeNil())
+			Expect(unsent).To(BeEmpty())
+			Expect(fs.Inputs).To(HaveLen(3))
+			Expect(fs.Inputs[0].Entries).To(HaveLen(10))
+			Expect(fs.Inputs[2].Entries).To(HaveLen(3))
+			Expect(*fs.Inputs[0].QueueUrl).To(Equal(url))
+			Expect(*fs.Inputs[2].Entries[2].MessageBody).To(Equal("22"))
+		})
+
+		It("delays each message till it is due", func() {
+			_, err = q.Send(context.TODO(), []Message{
+				{ID: "past", DueAt: now.Unix() - 10},
+				{ID: "soon", DueAt: now.Unix() + 45},
+				{ID: "later", DueAt: now.Unix() + 3600},
+			})
+
+			Expect(err).To(BeNil())
+
+			entries := fs.Inputs[0].Entries
+
+			Expect(*entries[0].DelaySeconds).To(BeZero())
+			Expect(*entries[1].DelaySeconds).To(BeEquivalentTo(45))
+			Expect(*entries[2].DelaySeconds).To(BeEquivalentTo(maxQueueDelay))
+		})
+
+		Describe("entries failed on the queue side", func() {
+			var done chan struct{}
+
+			BeforeEach(func() {
+				done = make(chan struct{})
+			})
+
+			sendAsync := func(count int) {
+				go func() {
+					defer close(done)
+
+					send(count)
+				}()
+			}
+
+			It("retries them after a backoff", func() {
+				fs.Outputs = []*sqs.SendMessageBatchOutput{failed(false, "1")}
+				sendAsync(3)
+
+				Eventually(fake.Waiters).Should(Equal(1))
+				Consistently(done).ShouldNot(BeClosed())
+
+				fake.Advance(queueBackoff)
+				Eventually(done).Should(BeClosed())
+
+				Expect(err).To(BeNil())
+				Expect(fs.Inputs).To(HaveLen(2))
+				Expect(fs.Inputs[1].Entries).To(HaveLen(1))
+				Expect(*fs.Inputs[1].Entries[0].MessageBody).To(Equal("1"))
+			})
+
+			It("gives up after the retries", func() {
+				for i := 0; i <= maxQueueRetry; i++ {
+					fs.Outputs = append(fs.Outputs, failed(false, "1"))
+				}
+
+				sendAsync(13)
+
+				for i := 0; i < maxQueueRetry; i++ {
+					Eventually(fake.Waiters).Should(Equal(1))
+					fake.Advance(time.Minute)
+				}
+
+				Eventually(done).Should(BeClosed())
+
+				Expect(err).To(Equal(errUnsent))
+				Expect(fs.Inputs).To(HaveLen(maxQueueRetry + 1))
+				Expect(unsent).To(Equal([]Message{
+					{ID: "1", DueAt: now.Unix()},
+					{ID: "10", DueAt: now.Unix()},
+					{ID: "11", DueAt: now.Unix()},
+					{ID: "12", DueAt: now.Unix()},
+				}))
+			})
+		})
+
+		It("returns error of entries failed on the sender side", func() {
+			fs.Outputs = []*sqs.SendMessageBatchOutput{failed(true, "1")}
+
+			send(3)
+
+			Expect(err).To(MatchError("queue send failed: invalid"))
+			Expect(unsent).To(Equal([]Message{{ID: "1", DueAt: now.Unix()}}))
+			Expect(fs.Inputs).To(HaveLen(1))
+		})
+
+		It("returns messages of failed and later batches", func() {
+			fs.Outputs = []*sqs.SendMessageBatchOutput{
+				{},
+				failed(true, "0", "2"),
+			}
+
+			send(23)
+
+			Expect(err).NotTo(BeNil())
+			Expect(fs.Inputs).To(HaveLen(2))
+			Expect(unsent).To(HaveLen(5))
+			Expect(unsent[0].ID).To(Equal("10"))
+			Expect(unsent[1].ID).To(Equal("12"))
+			Expect(unsent[2].ID).To(Equal("20"))
+		})
+
+		It("returns every message of error", func() {
+			fs.Error = fmt.Errorf("send error")
+
+			send(3)
+
+			Expect(err).NotTo(BeNil())
+			Expect(unsent).To(Equal(messages(3)))
+		})
+	})
+})
+
+type fakeSQS struct {
+	sqsiface.SQSAPI
+
+	Error   error
+	Inputs  []*sqs.SendMessageBatchInput
+	Outputs []*sqs.SendMessageBatchOutput
+}
+
+func (fs *fakeSQS) SendMessageBatchWithContext(
+	_ aws.Context,
+	input *sqs.SendMessageBatchInput,
+	_ ...request.Option) (*sqs.SendMessageBatchOutput, error) {
+
+	fs.Inputs = append(fs.Inputs, input)
+
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	if len(fs.Outputs) == 0 {
+		return &sqs.SendMessageBatchOutput{}, nil
+	}
+
+	output := fs.Outputs[0]
+	fs.Outputs = fs.Outputs[1:]
+
+	return output, nil
+}
+
+// fakeQueue sends the Accepted first messages before it fails with Error.
+type fakeQueue struct {
+	Error    error
+	Accepted int
+	Messages []Message
+}
+
+func (fq *fakeQueue) Send(
+	_ context.Context,
+	messages []Message) ([]Message, error) {
+
+	if fq.Error == nil {
+		fq.Messages = append(fq.Messages, messages...)
+
+		return nil, nil
+	}
+
+	fq.Messages = append(fq.Messages, messages[:fq.Accepted]...)
+
+	return messages[fq.Accepted:], fq.Error
+}
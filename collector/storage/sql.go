@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const (
+	claimBatchSize = 100
+
+	scheduleReasonInterrupted = "INTERRUPTED"
+)
+
+type SQL struct {
+	db    *sql.DB
+	clock clock.Clock
+	queue Queue
+	lock  string
+}
+
+// NewPostgres skips rows locked by a concurrent collector instead of
+// waiting on them, so replicas never queue the same schedule twice. What it
+// claims is sent to the queue when there is one.
+func NewPostgres(db *sql.DB, clock clock.Clock, queue Queue) *SQL {
+	return &SQL{db, clock, queue, "FOR UPDATE SKIP LOCKED"}
+}
+
+// NewSQLite does not lock rows, SQLite already serializes writers.
+func NewSQLite(db *sql.DB, clock clock.Clock) *SQL {
+	return &SQL{db, clock, nil, ""}
+}
+
+func (srv *SQL) Update(ctx context.Context) error {
+	_, err := srv.Claim(ctx)
+
+	return err
+}
+
+// Claim queues due schedules in batches and returns their ids.
+func (srv *SQL) Claim(ctx context.Context) ([]string, error) {
+	until := srv.clock.Now().Unix() + lookahead()
+
+	var ids []string
+
+	for {
+		batch, err := srv.claim(ctx, until)
+
+		if err != nil {
+			return ids, err
+		}
+
+		if srv.queue != nil {
+			if err = srv.send(ctx, batch); err != nil {
+				return ids, err
+			}
+		}
+
+		for _, m := range batch {
+			ids = append(ids, m.ID)
+		}
+
+		if len(batch) < claimBatchSize {
+			return ids, nil
+		}
+	}
+}
+
+func (srv *SQL) claim(ctx context.Context, until int64) ([]Message, error) {
+	rows, err := srv.db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`UPDATE schedules SET status = $1
+			WHERE id IN (
+				SELECT id FROM schedules
+				WHERE status = $2 AND due_at <= $3
+				ORDER BY due_at
+				LIMIT $4
+				%s
+			)
+			RETURNING id, due_at`,
+			srv.lock),
+		scheduleStatusQueued,
+		scheduleStatusIdle,
+		until,
+		claimBatchSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var messages []Message
+
+	for rows.Next() {
+		var m Message
+
+		if err = rows.Scan(&m.ID, &m.DueAt); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+// send hands the claimed schedules over to the queue, the ones it did not
+// take are put back to idle for the next run.
+func (srv *SQL) send(ctx context.Context, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	unsent, err := srv.queue.Send(ctx, messages)
+
+	if err == nil || len(unsent) == 0 {
+		return err
+	}
+
+	args := []interface{}{scheduleStatusIdle, scheduleStatusQueued}
+	placeholders := make([]string, len(unsent))
+
+	for i, m := range unsent {
+		args = append(args, m.ID)
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+	}
+
+	if _, rerr := srv.db.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`UPDATE schedules SET status = $1
+			WHERE status = $2 AND id IN (%s)`,
+			strings.Join(placeholders, ", ")),
+		args...); rerr != nil {
+		return fmt.Errorf("%w, release failed: %v", err, rerr)
+	}
+
+	return err
+}
+
+// Requeue puts schedules that were queued but never completed back to idle,
+// for a process that claims and dispatches in one place and was stopped in
+// between.
+func (srv *SQL) Requeue(ctx context.Context) error {
+	_, err := srv.db.ExecContext(
+		ctx,
+		`UPDATE schedules SET status = $1, reason = $2
+		WHERE status = $3`,
+		scheduleStatusIdle,
+		scheduleReasonInterrupted,
+		scheduleStatusQueued)
+
+	return err
+}
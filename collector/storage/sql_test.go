@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL", func() {
+	var (
+		conn *sql.DB
+		mock sqlmock.Sqlmock
+		db   *SQL
+	)
+
+	now := time.Unix(1600000000, 0)
+
+	BeforeEach(func() {
+		var err error
+
+		conn, mock, err = sqlmock.New()
+		Expect(err).To(BeNil())
+
+		db = NewPostgres(conn, clock.NewFake(now), nil)
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+	})
+
+	claimed := func(count int) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "due_at"})
+
+		for i := 0; i < count; i++ {
+			rows.AddRow(strconv.Itoa(i), now.Unix()+int64(i))
+		}
+
+		return rows
+	}
+
+	Describe("Update", func() {
+		Describe("success", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+					WithArgs(
+						scheduleStatusQueued,
+						scheduleStatusIdle,
+						now.Unix()+defaultLookahead,
+						claimBatchSize).
+					WillReturnRows(claimed(claimBatchSize))
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+					WillReturnRows(claimed(7))
+
+				err = db.Update(context.TODO())
+			})
+
+			It("claims until a partial batch", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+					WillReturnError(fmt.Errorf("update error"))
+
+				err = db.Update(context.TODO())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Claim", func() {
+		Describe("sqlite", func() {
+			var (
+				ids []string
+				err error
+			)
+
+			BeforeEach(func() {
+				db = NewSQLite(conn, clock.NewFake(now))
+
+				mock.ExpectQuery(`LIMIT \$4\s+\)\s+RETURNING id`).
+					WillReturnRows(claimed(3))
+
+				ids, err = db.Claim(context.TODO())
+			})
+
+			It("does not lock rows", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("returns claimed ids", func() {
+				Expect(ids).To(Equal([]string{"0", "1", "2"}))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Claim with queue", func() {
+		var (
+			fq  fakeQueue
+			ids []string
+			err error
+		)
+
+		BeforeEach(func() {
+			fq = fakeQueue{}
+			db = NewPostgres(conn, clock.NewFake(now), &fq)
+
+			mock.ExpectQuery(regexp.QuoteMeta("RETURNING id, due_at")).
+				WillReturnRows(claimed(3))
+		})
+
+		Context("sent", func() {
+			BeforeEach(func() {
+				ids, err = db.Claim(context.TODO())
+			})
+
+			It("sends claimed schedules", func() {
+				Expect(fq.Messages).To(Equal([]Message{
+					{ID: "0", DueAt: now.Unix()},
+					{ID: "1", DueAt: now.Unix() + 1},
+					{ID: "2", DueAt: now.Unix() + 2},
+				}))
+			})
+
+			It("returns claimed ids", func() {
+				Expect(ids).To(Equal([]string{"0", "1", "2"}))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("not sent", func() {
+			BeforeEach(func() {
+				fq.Error = fmt.Errorf("send error")
+
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE schedules SET")).
+					WithArgs(
+						scheduleStatusIdle,
+						scheduleStatusQueued,
+						"0",
+						"1",
+						"2").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+
+				ids, err = db.Claim(context.TODO())
+			})
+
+			It("puts claimed schedules back to idle", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("does not return ids", func() {
+				Expect(ids).To(BeEmpty())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
+		Context("partly sent", func() {
+			BeforeEach(func() {
+				fq.Error = fmt.Errorf("send error")
+				fq.Accepted = 1
+
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE schedules SET")).
+					WithArgs(
+						scheduleStatusIdle,
+						scheduleStatusQueued,
+						"1",
+						"2").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				_, err = db.Claim(context.TODO())
+			})
+
+			It("puts only unsent schedules back to idle", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Requeue", func() {
+		var err error
+
+		BeforeEach(func() {
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE schedules SET")).
+				WithArgs(
+					scheduleStatusIdle,
+					scheduleReasonInterrupted,
+					scheduleStatusQueued).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			err = db.Requeue(context.TODO())
+		})
+
+		It("puts queued schedules back to idle", func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,493 @@
+package dynamotest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type condition interface {
+	match(item) bool
+}
+
+type operand interface {
+	resolve(item) *dynamodb.AttributeValue
+}
+
+type pathOperand string
+
+func (o pathOperand) resolve(it item) *dynamodb.AttributeValue {
+	return it[string(o)]
+}
+
+type valueOperand struct {
+	value *dynamodb.AttributeValue
+}
+
+func (o valueOperand) resolve(_ item) *dynamodb.AttributeValue {
+	return o.value
+}
+
+type sizeOperand string
+
+func (o sizeOperand) resolve(it item) *dynamodb.AttributeValue {
+	value := it[string(o)]
+
+	if value == nil {
+		return nil
+	}
+
+	var size int
+
+	switch {
+	case value.S != nil:
+		size = len(*value.S)
+	case value.B != nil:
+		size = len(value.B)
+	case value.SS != nil:
+		size = len(value.SS)
+	case value.NS != nil:
+		size = len(value.NS)
+	case value.BS != nil:
+		size = len(value.BS)
+	case value.M != nil:
+		size = len(value.M)
+	case value.L != nil:
+		size = len(value.L)
+	default:
+		return nil
+	}
+
+	return numberValue(int64(size))
+}
+
+type andCondition struct {
+	left, right condition
+}
+
+func (c andCondition) match(it item) bool {
+	return c.left.match(it) && c.right.match(it)
+}
+
+type orCondition struct {
+	left, right condition
+}
+
+func (c orCondition) match(it item) bool {
+	return c.left.match(it) || c.right.match(it)
+}
+
+type notCondition struct {
+	inner condition
+}
+
+func (c notCondition) match(it item) bool {
+	return !c.inner.match(it)
+}
+
+type compareCondition struct {
+	operator    string
+	left, right operand
+}
+
+func (c compareCondition) match(it item) bool {
+	left, right := c.left.resolve(it), c.right.resolve(it)
+
+	if c.operator == "<>" {
+		return left == nil || right == nil || !equal(left, right)
+	}
+
+	if left == nil || right == nil {
+		return false
+	}
+
+	if c.operator == "=" {
+		return equal(left, right)
+	}
+
+	order, ok := compare(left, right)
+
+	if !ok {
+		return false
+	}
+
+	switch c.operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+type betweenCondition struct {
+	subject, low, high operand
+}
+
+func (c betweenCondition) match(it item) bool {
+	subject := c.subject.resolve(it)
+	low, high := c.low.resolve(it), c.high.resolve(it)
+
+	if subject == nil || low == nil || high == nil {
+		return false
+	}
+
+	lower, ok := compare(subject, low)
+
+	if !ok || lower < 0 {
+		return false
+	}
+
+	upper, ok := compare(subject, high)
+
+	return ok && upper <= 0
+}
+
+type inCondition struct {
+	subject operand
+	list    []operand
+}
+
+func (c inCondition) match(it item) bool {
+	subject := c.subject.resolve(it)
+
+	if subject == nil {
+		return false
+	}
+
+	for _, o := range c.list {
+		if value := o.resolve(it); value != nil && equal(subject, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type functionCondition struct {
+	name string
+	path string
+	arg  operand
+}
+
+func (c functionCondition) match(it item) bool {
+	value, found := it[c.path]
+
+	switch c.name {
+	case "attribute_exists":
+		return found
+	case "attribute_not_exists":
+		return !found
+	}
+
+	arg := c.arg.resolve(it)
+
+	if !found || value == nil || arg == nil {
+		return false
+	}
+
+	switch c.name {
+	case "begins_with":
+		if value.S != nil && arg.S != nil {
+			return strings.HasPrefix(*value.S, *arg.S)
+		}
+
+		return value.B != nil && arg.B != nil && bytes.HasPrefix(value.B, arg.B)
+	case "attribute_type":
+		return arg.S != nil && typeOf(value) == *arg.S
+	default:
+		return contains(value, arg)
+	}
+}
+
+func (p *parser) condition() (condition, error) {
+	left, err := p.and()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isWord("OR") {
+		p.next()
+
+		right, err := p.and()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = orCondition{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and() (condition, error) {
+	left, err := p.not()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isWord("AND") {
+		p.next()
+
+		right, err := p.not()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = andCondition{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) not() (condition, error) {
+	if p.isWord("NOT") {
+		p.next()
+
+		inner, err := p.not()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return notCondition{inner}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (condition, error) {
+	if p.isOperator("(") {
+		p.next()
+
+		c, err := p.condition()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return c, p.expectOperator(")")
+	}
+
+	if t := p.peek(); t.kind == tokenWord &&
+		p.tokens[p.pos+1].kind == tokenOperator &&
+		p.tokens[p.pos+1].text == "(" {
+		switch name := strings.ToLower(t.text); name {
+		case "attribute_exists", "attribute_not_exists",
+			"begins_with", "contains", "attribute_type":
+			return p.function(name)
+		}
+	}
+
+	subject, err := p.operand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isWord("BETWEEN") {
+		p.next()
+
+		low, err := p.operand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.isWord("AND") {
+			return nil, p.unexpected(p.peek())
+		}
+
+		p.next()
+
+		high, err := p.operand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return betweenCondition{subject, low, high}, nil
+	}
+
+	if p.isWord("IN") {
+		p.next()
+
+		if err = p.expectOperator("("); err != nil {
+			return nil, err
+		}
+
+		var list []operand
+
+		for {
+			o, err := p.operand()
+
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, o)
+
+			if !p.isOperator(",") {
+				break
+			}
+
+			p.next()
+		}
+
+		return inCondition{subject, list}, p.expectOperator(")")
+	}
+
+	t := p.next()
+
+	switch t.text {
+	case "=", "<>", "<", "<=", ">", ">=":
+		if t.kind != tokenOperator {
+			break
+		}
+
+		right, err := p.operand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return compareCondition{t.text, subject, right}, nil
+	}
+
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) function(name string) (condition, error) {
+	p.next()
+	p.next()
+
+	path, err := p.path()
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := functionCondition{name: name, path: path}
+
+	if name != "attribute_exists" && name != "attribute_not_exists" {
+		if err = p.expectOperator(","); err != nil {
+			return nil, err
+		}
+
+		if c.arg, err = p.operand(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, p.expectOperator(")")
+}
+
+func (p *parser) operand() (operand, error) {
+	if p.isWord("size") && p.tokens[p.pos+1].text == "(" {
+		p.next()
+		p.next()
+
+		path, err := p.path()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return sizeOperand(path), p.expectOperator(")")
+	}
+
+	if p.peek().kind == tokenValue {
+		value, err := p.value()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return valueOperand{value}, nil
+	}
+
+	path, err := p.path()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pathOperand(path), nil
+}
+
+// parseCondition parses a condition, filter or key condition expression.
+func (p *parser) parseCondition(expression string) (condition, error) {
+	if err := p.reset(expression); err != nil {
+		return nil, err
+	}
+
+	c, err := p.condition()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c, p.end()
+}
+
+// validateKeyCondition accepts an equality on the hash key, optionally
+// joined by a single range key condition.
+func validateKeyCondition(c condition, hash, rng string) error {
+	var (
+		hashFound  bool
+		rangeFound bool
+	)
+
+	for _, leaf := range flattenAnd(c) {
+		var path pathOperand
+
+		switch l := leaf.(type) {
+		case compareCondition:
+			path, _ = l.left.(pathOperand)
+
+			if string(path) == hash && l.operator == "=" && !hashFound {
+				hashFound = true
+				continue
+			}
+
+			if l.operator == "<>" {
+				path = ""
+			}
+		case betweenCondition:
+			path, _ = l.subject.(pathOperand)
+		case functionCondition:
+			if l.name == "begins_with" {
+				path = pathOperand(l.path)
+			}
+		}
+
+		if rng == "" || string(path) != rng || rangeFound {
+			return fmt.Errorf("query key condition not supported")
+		}
+
+		rangeFound = true
+	}
+
+	if !hashFound {
+		return fmt.Errorf("query condition missed key schema element: %s", hash)
+	}
+
+	return nil
+}
+
+func flattenAnd(c condition) []condition {
+	if and, ok := c.(andCondition); ok {
+		return append(flattenAnd(and.left), flattenAnd(and.right)...)
+	}
+
+	return []condition{c}
+}
@@ -0,0 +1,123 @@
+package dynamotest
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("condition", func() {
+	it := item{
+		"id":     {S: aws.String("1234")},
+		"status": {S: aws.String("IDLE")},
+		"dueAt":  {N: aws.String("100")},
+		"tags":   {SS: aws.StringSlice([]string{"a", "b"})},
+	}
+
+	names := map[string]*string{
+		"#s":  aws.String("status"),
+		"#da": aws.String("dueAt"),
+		"#x":  aws.String("missing"),
+	}
+
+	values := map[string]*dynamodb.AttributeValue{
+		":idle": {S: aws.String("IDLE")},
+		":done": {S: aws.String("SUCCEEDED")},
+		":n50":  {N: aws.String("50")},
+		":n100": {N: aws.String("100.0")},
+		":n200": {N: aws.String("200")},
+		":pre":  {S: aws.String("ID")},
+		":a":    {S: aws.String("a")},
+	}
+
+	DescribeTable("match",
+		func(expression string, expected bool) {
+			c, err := newParser(names, values).parseCondition(expression)
+
+			Expect(err).To(BeNil())
+			Expect(c.match(it)).To(Equal(expected))
+		},
+		Entry("equal", "#s = :idle", true),
+		Entry("not equal", "#s <> :idle", false),
+		Entry("not equal missing", "#x <> :idle", true),
+		Entry("numeric equal", "#da = :n100", true),
+		Entry("less", "#da < :n200", true),
+		Entry("less or equal", "#da <= :n50", false),
+		Entry("greater", "#da > :n50", true),
+		Entry("greater or equal", "#da >= :n100", true),
+		Entry("missing compared", "#x > :n50", false),
+		Entry("between", "#da BETWEEN :n50 AND :n200", true),
+		Entry("outside between", "#da BETWEEN :n50 AND :n50", false),
+		Entry("in", "#s IN (:done, :idle)", true),
+		Entry("exists", "attribute_exists(#s)", true),
+		Entry("not exists", "attribute_not_exists(#x)", true),
+		Entry("begins with", "begins_with(#s, :pre)", true),
+		Entry("contains", "contains(tags, :a)", true),
+		Entry("size", "size(tags) < :n50", true),
+		Entry("and", "#s = :idle AND #da > :n200", false),
+		Entry("or", "#s = :done OR #da < :n200", true),
+		Entry("not", "NOT #s = :done", true),
+		Entry("parentheses",
+			"(#s = :done OR #s = :idle) AND #da = :n100", true),
+	)
+
+	DescribeTable("invalid",
+		func(expression string) {
+			_, err := newParser(names, values).parseCondition(expression)
+
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("undefined name", "#nope = :idle"),
+		Entry("undefined value", "#s = :nope"),
+		Entry("dangling operator", "#s ="),
+		Entry("nested path", "#s.foo = :idle"),
+		Entry("trailing tokens", "#s = :idle :done"),
+	)
+
+	Describe("unused", func() {
+		It("reports unused names", func() {
+			p := newParser(names, values)
+			_, _ = p.parseCondition("#s = :idle")
+
+			Expect(p.unused()).NotTo(BeNil())
+		})
+
+		It("accepts all used", func() {
+			p := newParser(
+				map[string]*string{"#s": aws.String("status")},
+				map[string]*dynamodb.AttributeValue{
+					":idle": {S: aws.String("IDLE")},
+				})
+			_, _ = p.parseCondition("#s = :idle")
+
+			Expect(p.unused()).To(BeNil())
+		})
+	})
+
+	DescribeTable("key condition",
+		func(expression string, valid bool) {
+			c, err := newParser(names, values).parseCondition(expression)
+			Expect(err).To(BeNil())
+
+			err = validateKeyCondition(c, "status", "dueAt")
+
+			if valid {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).NotTo(BeNil())
+			}
+		},
+		Entry("hash", "#s = :idle", true),
+		Entry("hash and range", "#s = :idle AND #da <= :n200", true),
+		Entry("hash and between",
+			"#s = :idle AND #da BETWEEN :n50 AND :n200", true),
+		Entry("range only", "#da <= :n200", false),
+		Entry("hash inequality", "#s <> :idle", false),
+		Entry("or", "#s = :idle OR #s = :done", false),
+		Entry("two range conditions",
+			"#s = :idle AND #da > :n50 AND #da < :n200", false),
+	)
+})
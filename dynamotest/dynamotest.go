@@ -0,0 +1,976 @@
+// Package dynamotest is an in-memory DynamoDB for tests. It implements the
+// part of dynamodbiface.DynamoDBAPI the scheduler uses, evaluates the
+// condition, key condition, filter, projection and update expressions it
+// sends, maintains the global secondary indexes and records stream events.
+//
+// Only top level attributes are addressable in expressions and the key
+// condition of a query is validated against the key schema before it is
+// evaluated like a filter. Any other DynamoDB call panics.
+package dynamotest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+const (
+	maxBatchGets      = 100
+	maxBatchWrites    = 25
+	maxTransactWrites = 100
+)
+
+type Index struct {
+	Name     string
+	HashKey  string
+	RangeKey string
+}
+
+type Table struct {
+	Name     string
+	HashKey  string
+	RangeKey string
+	Indexes  []Index
+
+	// StreamViewType enables the stream of the table when set, with one of
+	// the dynamodb.StreamViewType values.
+	StreamViewType string
+}
+
+type table struct {
+	Table
+
+	items   map[string]item
+	records []events.DynamoDBEventRecord
+}
+
+type DynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mutex    sync.Mutex
+	tables   map[string]*table
+	sequence int64
+}
+
+func New(tables ...Table) *DynamoDB {
+	db := DynamoDB{tables: make(map[string]*table)}
+
+	for _, t := range tables {
+		db.tables[t.Name] = &table{Table: t, items: make(map[string]item)}
+	}
+
+	return &db
+}
+
+// Stream returns the records written to the stream of the table since the
+// last call, in the order they happened.
+func (db *DynamoDB) Stream(name string) events.DynamoDBEvent {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	t, found := db.tables[name]
+
+	if !found {
+		return events.DynamoDBEvent{}
+	}
+
+	records := t.records
+	t.records = nil
+
+	return events.DynamoDBEvent{Records: records}
+}
+
+func (db *DynamoDB) GetItem(
+	input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+
+	return db.GetItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) GetItemWithContext(
+	_ aws.Context,
+	input *dynamodb.GetItemInput,
+	_ ...request.Option) (*dynamodb.GetItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	t, err := db.table(input.TableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := t.key(input.Key, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(input.ExpressionAttributeNames, nil)
+
+	projection, err := p.parseProjection(input.ProjectionExpression)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	if err = p.unused(); err != nil {
+		return nil, validationError(err)
+	}
+
+	it, found := t.items[key]
+
+	if !found {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+
+	return &dynamodb.GetItemOutput{Item: it.clone().project(projection)}, nil
+}
+
+func (db *DynamoDB) PutItem(
+	input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+
+	return db.PutItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) PutItemWithContext(
+	_ aws.Context,
+	input *dynamodb.PutItemInput,
+	_ ...request.Option) (*dynamodb.PutItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	c, err := db.put(&dynamodb.Put{
+		TableName:                 input.TableName,
+		Item:                      input.Item,
+		ConditionExpression:       input.ConditionExpression,
+		ExpressionAttributeNames:  input.ExpressionAttributeNames,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	db.apply(c)
+
+	output := dynamodb.PutItemOutput{}
+
+	if aws.StringValue(input.ReturnValues) == dynamodb.ReturnValueAllOld {
+		output.Attributes = c.before.clone()
+	}
+
+	return &output, nil
+}
+
+// put is the change a put makes, or the error it fails with.
+func (db *DynamoDB) put(input *dynamodb.Put) (*change, error) {
+	t, err := db.table(input.TableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := t.key(input.Item, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(
+		input.ExpressionAttributeNames,
+		input.ExpressionAttributeValues)
+
+	c, err := p.parseOptionalCondition(input.ConditionExpression)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	if err = p.unused(); err != nil {
+		return nil, validationError(err)
+	}
+
+	old := t.items[key]
+
+	if c != nil && !c.match(old) {
+		return nil, conditionalCheckFailed()
+	}
+
+	return &change{t, key, old, item(input.Item).clone()}, nil
+}
+
+func (db *DynamoDB) UpdateItem(
+	input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+
+	return db.UpdateItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) UpdateItemWithContext(
+	_ aws.Context,
+	input *dynamodb.UpdateItemInput,
+	_ ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	c, paths, err := db.update(&dynamodb.Update{
+		TableName:                 input.TableName,
+		Key:                       input.Key,
+		UpdateExpression:          input.UpdateExpression,
+		ConditionExpression:       input.ConditionExpression,
+		ExpressionAttributeNames:  input.ExpressionAttributeNames,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	db.apply(c)
+
+	output := dynamodb.UpdateItemOutput{}
+
+	switch aws.StringValue(input.ReturnValues) {
+	case dynamodb.ReturnValueAllOld:
+		output.Attributes = c.before.clone()
+	case dynamodb.ReturnValueAllNew:
+		output.Attributes = c.after.clone()
+	case dynamodb.ReturnValueUpdatedOld:
+		output.Attributes = c.before.clone().project(paths)
+	case dynamodb.ReturnValueUpdatedNew:
+		output.Attributes = c.after.clone().project(paths)
+	}
+
+	return &output, nil
+}
+
+// update is the change an update makes and the paths it sets, or the error
+// it fails with.
+func (db *DynamoDB) update(input *dynamodb.Update) (*change, []string, error) {
+	t, err := db.table(input.TableName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := t.key(input.Key, true)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if input.UpdateExpression == nil {
+		return nil, nil, validationError(
+			fmt.Errorf("UpdateExpression must be specified"))
+	}
+
+	p := newParser(
+		input.ExpressionAttributeNames,
+		input.ExpressionAttributeValues)
+
+	u, err := p.parseUpdate(*input.UpdateExpression)
+
+	if err != nil {
+		return nil, nil, validationError(err)
+	}
+
+	c, err := p.parseOptionalCondition(input.ConditionExpression)
+
+	if err != nil {
+		return nil, nil, validationError(err)
+	}
+
+	if err = p.unused(); err != nil {
+		return nil, nil, validationError(err)
+	}
+
+	paths := u.paths()
+
+	for _, path := range paths {
+		if path == t.HashKey || path == t.RangeKey {
+			return nil, nil, validationError(fmt.Errorf(
+				"cannot update attribute %s. this attribute is part of the "+
+					"key", path))
+		}
+	}
+
+	old := t.items[key]
+
+	if c != nil && !c.match(old) {
+		return nil, nil, conditionalCheckFailed()
+	}
+
+	updated := old.clone()
+
+	if updated == nil {
+		updated = item(input.Key).clone()
+	}
+
+	if err = u.apply(updated); err != nil {
+		return nil, nil, validationError(err)
+	}
+
+	return &change{t, key, old, updated}, paths, nil
+}
+
+func (db *DynamoDB) DeleteItem(
+	input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+
+	return db.DeleteItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) DeleteItemWithContext(
+	_ aws.Context,
+	input *dynamodb.DeleteItemInput,
+	_ ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	c, err := db.check(
+		input.TableName,
+		input.Key,
+		input.ConditionExpression,
+		input.ExpressionAttributeNames,
+		input.ExpressionAttributeValues)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.after = nil
+	db.apply(c)
+
+	output := dynamodb.DeleteItemOutput{}
+
+	if aws.StringValue(input.ReturnValues) == dynamodb.ReturnValueAllOld {
+		output.Attributes = c.before.clone()
+	}
+
+	return &output, nil
+}
+
+// check is the change of an item that stays as it is, when the condition
+// holds, a delete sets the after image to nil.
+func (db *DynamoDB) check(
+	name *string,
+	keyAttrs map[string]*dynamodb.AttributeValue,
+	condition *string,
+	names map[string]*string,
+	values map[string]*dynamodb.AttributeValue) (*change, error) {
+
+	t, err := db.table(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := t.key(keyAttrs, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(names, values)
+
+	c, err := p.parseOptionalCondition(condition)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	if err = p.unused(); err != nil {
+		return nil, validationError(err)
+	}
+
+	old := t.items[key]
+
+	if c != nil && !c.match(old) {
+		return nil, conditionalCheckFailed()
+	}
+
+	return &change{t, key, old, old}, nil
+}
+
+func (db *DynamoDB) TransactWriteItems(
+	input *dynamodb.TransactWriteItemsInput) (
+	*dynamodb.TransactWriteItemsOutput, error) {
+
+	return db.TransactWriteItemsWithContext(aws.BackgroundContext(), input)
+}
+
+// TransactWriteItemsWithContext applies every write or none, a failed
+// condition cancels the transaction with the reason of each item.
+func (db *DynamoDB) TransactWriteItemsWithContext(
+	_ aws.Context,
+	input *dynamodb.TransactWriteItemsInput,
+	_ ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if len(input.TransactItems) == 0 ||
+		len(input.TransactItems) > maxTransactWrites {
+		return nil, validationError(fmt.Errorf(
+			"transaction must have between 1 and %d items",
+			maxTransactWrites))
+	}
+
+	changes := make([]*change, len(input.TransactItems))
+	reasons := make([]*dynamodb.CancellationReason, len(changes))
+	seen := make(map[string]bool)
+	canceled := false
+
+	for i, ti := range input.TransactItems {
+		c, err := db.transactItem(ti)
+
+		if err != nil {
+			if !isConditionalCheckFailed(err) {
+				return nil, err
+			}
+
+			canceled = true
+			reasons[i] = &dynamodb.CancellationReason{
+				Code:    aws.String("ConditionalCheckFailed"),
+				Message: aws.String("The conditional request failed"),
+			}
+
+			continue
+		}
+
+		id := c.t.Name + "/" + c.key
+
+		if seen[id] {
+			return nil, validationError(fmt.Errorf(
+				"transaction request cannot include multiple operations " +
+					"on one item"))
+		}
+
+		seen[id] = true
+		changes[i] = c
+		reasons[i] = &dynamodb.CancellationReason{Code: aws.String("None")}
+	}
+
+	if canceled {
+		return nil, &dynamodb.TransactionCanceledException{
+			Message_:            aws.String("Transaction cancelled"),
+			CancellationReasons: reasons,
+		}
+	}
+
+	for _, c := range changes {
+		db.apply(c)
+	}
+
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (db *DynamoDB) transactItem(
+	ti *dynamodb.TransactWriteItem) (*change, error) {
+
+	switch {
+	case ti.Put != nil:
+		return db.put(ti.Put)
+	case ti.Update != nil:
+		c, _, err := db.update(ti.Update)
+
+		return c, err
+	case ti.Delete != nil:
+		c, err := db.check(
+			ti.Delete.TableName,
+			ti.Delete.Key,
+			ti.Delete.ConditionExpression,
+			ti.Delete.ExpressionAttributeNames,
+			ti.Delete.ExpressionAttributeValues)
+
+		if err == nil {
+			c.after = nil
+		}
+
+		return c, err
+	case ti.ConditionCheck != nil:
+		return db.check(
+			ti.ConditionCheck.TableName,
+			ti.ConditionCheck.Key,
+			ti.ConditionCheck.ConditionExpression,
+			ti.ConditionCheck.ExpressionAttributeNames,
+			ti.ConditionCheck.ExpressionAttributeValues)
+	}
+
+	return nil, validationError(fmt.Errorf("transaction item has no action"))
+}
+
+func (db *DynamoDB) BatchGetItem(
+	input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+
+	return db.BatchGetItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) BatchGetItemWithContext(
+	_ aws.Context,
+	input *dynamodb.BatchGetItemInput,
+	_ ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	output := dynamodb.BatchGetItemOutput{
+		Responses:       map[string][]map[string]*dynamodb.AttributeValue{},
+		UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{},
+	}
+
+	count := 0
+
+	for name, keys := range input.RequestItems {
+		t, err := db.table(aws.String(name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		p := newParser(keys.ExpressionAttributeNames, nil)
+
+		projection, err := p.parseProjection(keys.ProjectionExpression)
+
+		if err != nil {
+			return nil, validationError(err)
+		}
+
+		if err = p.unused(); err != nil {
+			return nil, validationError(err)
+		}
+
+		seen := make(map[string]bool)
+		items := []map[string]*dynamodb.AttributeValue{}
+
+		for _, k := range keys.Keys {
+			count++
+
+			key, err := t.key(k, true)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[key] {
+				return nil, validationError(fmt.Errorf(
+					"provided list of item keys contains duplicates"))
+			}
+
+			seen[key] = true
+
+			if it, found := t.items[key]; found {
+				items = append(items, it.clone().project(projection))
+			}
+		}
+
+		output.Responses[name] = items
+	}
+
+	if count == 0 || count > maxBatchGets {
+		return nil, validationError(fmt.Errorf(
+			"member must have length less than or equal to %d and greater "+
+				"than or equal to 1", maxBatchGets))
+	}
+
+	return &output, nil
+}
+
+func (db *DynamoDB) BatchWriteItem(
+	input *dynamodb.BatchWriteItemInput) (
+	*dynamodb.BatchWriteItemOutput, error) {
+
+	return db.BatchWriteItemWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) BatchWriteItemWithContext(
+	_ aws.Context,
+	input *dynamodb.BatchWriteItemInput,
+	_ ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	type write struct {
+		table *table
+		key   string
+		item  item
+	}
+
+	var (
+		writes []write
+		count  int
+	)
+
+	for name, requests := range input.RequestItems {
+		t, err := db.table(aws.String(name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]bool)
+
+		for _, r := range requests {
+			count++
+
+			var (
+				key string
+				it  item
+			)
+
+			switch {
+			case r.PutRequest != nil:
+				key, err = t.key(r.PutRequest.Item, false)
+				it = item(r.PutRequest.Item).clone()
+			case r.DeleteRequest != nil:
+				key, err = t.key(r.DeleteRequest.Key, true)
+			default:
+				err = validationError(fmt.Errorf("empty write request"))
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[key] {
+				return nil, validationError(fmt.Errorf(
+					"provided list of item keys contains duplicates"))
+			}
+
+			seen[key] = true
+			writes = append(writes, write{t, key, it})
+		}
+	}
+
+	if count == 0 || count > maxBatchWrites {
+		return nil, validationError(fmt.Errorf(
+			"member must have length less than or equal to %d and greater "+
+				"than or equal to 1", maxBatchWrites))
+	}
+
+	for _, w := range writes {
+		db.write(w.table, w.key, w.table.items[w.key], w.item)
+	}
+
+	return &dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]*dynamodb.WriteRequest{},
+	}, nil
+}
+
+func (db *DynamoDB) Query(
+	input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+
+	return db.QueryWithContext(aws.BackgroundContext(), input)
+}
+
+func (db *DynamoDB) QueryWithContext(
+	_ aws.Context,
+	input *dynamodb.QueryInput,
+	_ ...request.Option) (*dynamodb.QueryOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	t, err := db.table(input.TableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash, rng := t.HashKey, t.RangeKey
+
+	if input.IndexName != nil {
+		index, found := t.index(*input.IndexName)
+
+		if !found {
+			return nil, validationError(fmt.Errorf(
+				"the table does not have the specified index: %s",
+				*input.IndexName))
+		}
+
+		hash, rng = index.HashKey, index.RangeKey
+	}
+
+	if input.KeyConditionExpression == nil {
+		return nil, validationError(fmt.Errorf(
+			"either the KeyConditions or KeyConditionExpression parameter " +
+				"must be specified"))
+	}
+
+	if input.Limit != nil && *input.Limit < 1 {
+		return nil, validationError(fmt.Errorf(
+			"limit must be greater than or equal to 1"))
+	}
+
+	p := newParser(
+		input.ExpressionAttributeNames,
+		input.ExpressionAttributeValues)
+
+	keyCondition, err := p.parseCondition(*input.KeyConditionExpression)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	if err = validateKeyCondition(keyCondition, hash, rng); err != nil {
+		return nil, validationError(err)
+	}
+
+	filter, err := p.parseOptionalCondition(input.FilterExpression)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	projection, err := p.parseProjection(input.ProjectionExpression)
+
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	if err = p.unused(); err != nil {
+		return nil, validationError(err)
+	}
+
+	direction := 1
+
+	if input.ScanIndexForward != nil && !*input.ScanIndexForward {
+		direction = -1
+	}
+
+	var matches []item
+
+	for _, it := range t.items {
+		if _, ok := keyOf(it, hash, rng); ok && keyCondition.match(it) {
+			matches = append(matches, it)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return t.order(matches[i], matches[j], rng)*direction < 0
+	})
+
+	if len(input.ExclusiveStartKey) > 0 {
+		start := item(input.ExclusiveStartKey)
+		skip := 0
+
+		for skip < len(matches) &&
+			t.order(matches[skip], start, rng)*direction <= 0 {
+			skip++
+		}
+
+		matches = matches[skip:]
+	}
+
+	var (
+		items   []map[string]*dynamodb.AttributeValue
+		scanned int64
+		lastKey map[string]*dynamodb.AttributeValue
+	)
+
+	// Like DynamoDB a page that stops at the limit always carries the last
+	// evaluated key, even when nothing is left after it.
+	for _, it := range matches {
+		scanned++
+
+		if filter == nil || filter.match(it) {
+			items = append(items, it.clone().project(projection))
+		}
+
+		if input.Limit != nil && scanned == *input.Limit {
+			lastKey = it.clone().project(
+				keyNames(t.HashKey, t.RangeKey, hash, rng))
+
+			break
+		}
+	}
+
+	return &dynamodb.QueryOutput{
+		Items:            items,
+		Count:            aws.Int64(int64(len(items))),
+		ScannedCount:     aws.Int64(scanned),
+		LastEvaluatedKey: lastKey,
+	}, nil
+}
+
+func (db *DynamoDB) table(name *string) (*table, error) {
+	t, found := db.tables[aws.StringValue(name)]
+
+	if !found {
+		return nil, awserr.NewRequestFailure(
+			awserr.New(
+				dynamodb.ErrCodeResourceNotFoundException,
+				"Requested resource not found",
+				nil),
+			400,
+			"")
+	}
+
+	return t, nil
+}
+
+// change is the before and after image of an item a write makes.
+type change struct {
+	t      *table
+	key    string
+	before item
+	after  item
+}
+
+func (db *DynamoDB) apply(c *change) {
+	db.write(c.t, c.key, c.before, c.after)
+}
+
+// write stores the new image of an item, nil removes it, and records the
+// change on the stream. Writes that change nothing are not recorded.
+func (db *DynamoDB) write(t *table, key string, before, after item) {
+	if after == nil {
+		delete(t.items, key)
+	} else {
+		t.items[key] = after
+	}
+
+	if t.StreamViewType == "" || (before == nil && after == nil) ||
+		reflect.DeepEqual(before, after) {
+		return
+	}
+
+	db.sequence++
+
+	record := events.DynamoDBEventRecord{
+		AWSRegion:    "local",
+		EventID:      strconv.FormatInt(db.sequence, 10),
+		EventName:    "MODIFY",
+		EventSource:  "aws:dynamodb",
+		EventVersion: "1.1",
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: strconv.FormatInt(db.sequence, 10),
+			StreamViewType: t.StreamViewType,
+		},
+	}
+
+	image := after
+
+	switch {
+	case before == nil:
+		record.EventName = "INSERT"
+	case after == nil:
+		record.EventName = "REMOVE"
+		image = before
+	}
+
+	record.Change.Keys = toEventAttributes(
+		image.project(keyNames(t.HashKey, t.RangeKey)))
+
+	switch t.StreamViewType {
+	case dynamodb.StreamViewTypeNewImage:
+		record.Change.NewImage = toEventAttributes(after)
+	case dynamodb.StreamViewTypeOldImage:
+		record.Change.OldImage = toEventAttributes(before)
+	case dynamodb.StreamViewTypeNewAndOldImages:
+		record.Change.NewImage = toEventAttributes(after)
+		record.Change.OldImage = toEventAttributes(before)
+	}
+
+	t.records = append(t.records, record)
+}
+
+// key encodes the primary key of the given attributes, exact requires
+// nothing but the key attributes to be present.
+func (t *table) key(
+	attrs map[string]*dynamodb.AttributeValue,
+	exact bool) (string, error) {
+
+	names := keyNames(t.HashKey, t.RangeKey)
+	key, ok := keyOf(attrs, names...)
+
+	if !ok || (exact && len(attrs) != len(names)) {
+		return "", validationError(fmt.Errorf(
+			"the provided key element does not match the schema"))
+	}
+
+	return key, nil
+}
+
+func (t *table) index(name string) (Index, bool) {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+
+	return Index{}, false
+}
+
+// order sorts on the range key of the index being read and falls back to
+// the primary key so the order is stable.
+func (t *table) order(a, b item, rng string) int {
+	if rng != "" {
+		if order, ok := compare(a[rng], b[rng]); ok && order != 0 {
+			return order
+		}
+	}
+
+	x, _ := keyOf(a, t.HashKey, t.RangeKey)
+	y, _ := keyOf(b, t.HashKey, t.RangeKey)
+
+	return strings.Compare(x, y)
+}
+
+func keyNames(names ...string) []string {
+	var unique []string
+
+	for _, name := range names {
+		if name != "" && !containsName(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validationError(err error) error {
+	return awserr.NewRequestFailure(
+		awserr.New("ValidationException", err.Error(), nil),
+		400,
+		"")
+}
+
+func isConditionalCheckFailed(err error) bool {
+	ae, ok := err.(awserr.Error)
+
+	return ok && ae.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
+
+func conditionalCheckFailed() error {
+	return awserr.NewRequestFailure(
+		awserr.New(
+			dynamodb.ErrCodeConditionalCheckFailedException,
+			"The conditional request failed",
+			nil),
+		400,
+		"")
+}
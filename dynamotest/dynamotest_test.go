@@ -0,0 +1,593 @@
+package dynamotest
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DynamoDB", func() {
+	const name = "scheduler_v1"
+
+	var db *DynamoDB
+
+	schedule := func(id, status string, dueAt int) *dynamodb.PutItemInput {
+		return &dynamodb.PutItemInput{
+			TableName: aws.String(name),
+			Item: map[string]*dynamodb.AttributeValue{
+				"id":     {S: aws.String(id)},
+				"status": {S: aws.String(status)},
+				"dueAt":  {N: aws.String(strconv.Itoa(dueAt))},
+			},
+		}
+	}
+
+	code := func(err error) string {
+		if ae, ok := err.(awserr.RequestFailure); ok {
+			return ae.Code()
+		}
+
+		return ""
+	}
+
+	BeforeEach(func() {
+		db = New(Table{
+			Name:    name,
+			HashKey: "id",
+			Indexes: []Index{
+				{Name: "ix_status_dueAt", HashKey: "status", RangeKey: "dueAt"},
+			},
+			StreamViewType: dynamodb.StreamViewTypeNewImage,
+		})
+	})
+
+	Describe("GetItem", func() {
+		It("returns put item", func() {
+			_, err := db.PutItem(schedule("1", "IDLE", 10))
+			Expect(err).To(BeNil())
+
+			res, err := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+				ProjectionExpression: aws.String("#s"),
+				ExpressionAttributeNames: map[string]*string{
+					"#s": aws.String("status"),
+				},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(res.Item).To(HaveLen(1))
+			Expect(*res.Item["status"].S).To(Equal("IDLE"))
+		})
+
+		It("returns nothing for unknown key", func() {
+			res, err := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(res.Item).To(BeEmpty())
+		})
+
+		It("rejects keys not matching the schema", func() {
+			_, err := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"status": {S: aws.String("IDLE")},
+				},
+			})
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+
+		It("rejects unknown tables", func() {
+			_, err := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String("unknown"),
+			})
+
+			Expect(code(err)).To(
+				Equal(dynamodb.ErrCodeResourceNotFoundException))
+		})
+	})
+
+	Describe("PutItem", func() {
+		It("checks condition", func() {
+			input := schedule("1", "IDLE", 10)
+			input.ConditionExpression = aws.String("attribute_not_exists(id)")
+
+			_, err := db.PutItem(input)
+			Expect(err).To(BeNil())
+
+			_, err = db.PutItem(input)
+			Expect(code(err)).To(
+				Equal(dynamodb.ErrCodeConditionalCheckFailedException))
+		})
+
+		It("does not keep a reference to the input", func() {
+			input := schedule("1", "IDLE", 10)
+			_, _ = db.PutItem(input)
+
+			*input.Item["status"].S = "QUEUED"
+
+			res, _ := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+			})
+
+			Expect(*res.Item["status"].S).To(Equal("IDLE"))
+		})
+	})
+
+	Describe("UpdateItem", func() {
+		update := func(expected string) (*dynamodb.UpdateItemOutput, error) {
+			return db.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+				UpdateExpression:    aws.String("SET #s = :s1"),
+				ConditionExpression: aws.String("#s = :s2"),
+				ExpressionAttributeNames: map[string]*string{
+					"#s": aws.String("status"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":s1": {S: aws.String("CANCELED")},
+					":s2": {S: aws.String(expected)},
+				},
+				ReturnValues: aws.String(dynamodb.ReturnValueUpdatedNew),
+			})
+		}
+
+		BeforeEach(func() {
+			_, _ = db.PutItem(schedule("1", "IDLE", 10))
+		})
+
+		It("updates matching item", func() {
+			res, err := update("IDLE")
+
+			Expect(err).To(BeNil())
+			Expect(res.Attributes).To(HaveLen(1))
+			Expect(*res.Attributes["status"].S).To(Equal("CANCELED"))
+		})
+
+		It("fails condition of changed item", func() {
+			_, _ = update("IDLE")
+			_, err := update("IDLE")
+
+			Expect(code(err)).To(
+				Equal(dynamodb.ErrCodeConditionalCheckFailedException))
+		})
+
+		It("rejects unused placeholders", func() {
+			_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+				UpdateExpression: aws.String("SET #s = :s"),
+				ExpressionAttributeNames: map[string]*string{
+					"#s":  aws.String("status"),
+					"#ca": aws.String("canceledAt"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":s": {S: aws.String("CANCELED")},
+				},
+			})
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+
+		It("rejects key updates", func() {
+			_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+				UpdateExpression: aws.String("SET id = :id"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":id": {S: aws.String("2")},
+				},
+			})
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+
+		It("creates missing item", func() {
+			_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("2")},
+				},
+				UpdateExpression: aws.String("ADD failures :o"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":o": {N: aws.String("1")},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			res, _ := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("2")},
+				},
+			})
+
+			Expect(*res.Item["failures"].N).To(Equal("1"))
+		})
+	})
+
+	Describe("Query", func() {
+		query := func(
+			limit int64,
+			forward bool,
+			startKey map[string]*dynamodb.AttributeValue,
+		) *dynamodb.QueryOutput {
+
+			res, err := db.Query(&dynamodb.QueryInput{
+				TableName:              aws.String(name),
+				IndexName:              aws.String("ix_status_dueAt"),
+				KeyConditionExpression: aws.String("#s = :s AND #da <= :da"),
+				ExpressionAttributeNames: map[string]*string{
+					"#s":  aws.String("status"),
+					"#da": aws.String("dueAt"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":s":  {S: aws.String("IDLE")},
+					":da": {N: aws.String("30")},
+				},
+				Limit:             aws.Int64(limit),
+				ScanIndexForward:  aws.Bool(forward),
+				ExclusiveStartKey: startKey,
+			})
+
+			Expect(err).To(BeNil())
+
+			return res
+		}
+
+		ids := func(res *dynamodb.QueryOutput) []string {
+			var ids []string
+
+			for _, it := range res.Items {
+				ids = append(ids, *it["id"].S)
+			}
+
+			return ids
+		}
+
+		BeforeEach(func() {
+			for _, input := range []*dynamodb.PutItemInput{
+				schedule("1", "IDLE", 30),
+				schedule("2", "IDLE", 10),
+				schedule("3", "IDLE", 20),
+				schedule("4", "IDLE", 40),
+				schedule("5", "QUEUED", 5),
+			} {
+				_, err := db.PutItem(input)
+				Expect(err).To(BeNil())
+			}
+
+			_, _ = db.PutItem(&dynamodb.PutItemInput{
+				TableName: aws.String(name),
+				Item: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("6")},
+				},
+			})
+		})
+
+		It("sorts on index range key", func() {
+			Expect(ids(query(10, true, nil))).To(Equal([]string{"2", "3", "1"}))
+		})
+
+		It("sorts backward", func() {
+			Expect(ids(query(10, false, nil))).To(
+				Equal([]string{"1", "3", "2"}))
+		})
+
+		It("pages with last evaluated key", func() {
+			first := query(2, true, nil)
+
+			Expect(ids(first)).To(Equal([]string{"2", "3"}))
+			Expect(first.LastEvaluatedKey).To(HaveLen(3))
+
+			second := query(2, true, first.LastEvaluatedKey)
+
+			Expect(ids(second)).To(Equal([]string{"1"}))
+			Expect(second.LastEvaluatedKey).To(BeEmpty())
+		})
+
+		It("returns last evaluated key on a full last page", func() {
+			res := query(3, true, nil)
+
+			Expect(res.LastEvaluatedKey).NotTo(BeEmpty())
+			Expect(query(3, true, res.LastEvaluatedKey).Items).To(BeEmpty())
+		})
+
+		It("filters after the limit", func() {
+			res, err := db.Query(&dynamodb.QueryInput{
+				TableName:              aws.String(name),
+				IndexName:              aws.String("ix_status_dueAt"),
+				KeyConditionExpression: aws.String("#s = :s"),
+				FilterExpression:       aws.String("#da > :da"),
+				ExpressionAttributeNames: map[string]*string{
+					"#s":  aws.String("status"),
+					"#da": aws.String("dueAt"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":s":  {S: aws.String("IDLE")},
+					":da": {N: aws.String("15")},
+				},
+				Limit: aws.Int64(2),
+			})
+
+			Expect(err).To(BeNil())
+			Expect(ids(res)).To(Equal([]string{"3"}))
+			Expect(*res.ScannedCount).To(BeEquivalentTo(2))
+		})
+
+		It("rejects conditions without hash key", func() {
+			_, err := db.Query(&dynamodb.QueryInput{
+				TableName:              aws.String(name),
+				IndexName:              aws.String("ix_status_dueAt"),
+				KeyConditionExpression: aws.String("#da <= :da"),
+				ExpressionAttributeNames: map[string]*string{
+					"#da": aws.String("dueAt"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":da": {N: aws.String("30")},
+				},
+			})
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+
+		It("rejects unknown index", func() {
+			_, err := db.Query(&dynamodb.QueryInput{
+				TableName:              aws.String(name),
+				IndexName:              aws.String("ix_unknown"),
+				KeyConditionExpression: aws.String("id = :id"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":id": {S: aws.String("1")},
+				},
+			})
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+	})
+
+	Describe("BatchGetItem", func() {
+		get := func(ids ...string) (*dynamodb.BatchGetItemOutput, error) {
+			var keys []map[string]*dynamodb.AttributeValue
+
+			for _, id := range ids {
+				keys = append(keys, map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(id)},
+				})
+			}
+
+			return db.BatchGetItem(&dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					name: {
+						Keys:                 keys,
+						ProjectionExpression: aws.String("id, #s"),
+						ExpressionAttributeNames: map[string]*string{
+							"#s": aws.String("status"),
+						},
+					},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			_, _ = db.PutItem(schedule("1", "IDLE", 10))
+			_, _ = db.PutItem(schedule("2", "QUEUED", 20))
+		})
+
+		It("returns projected items found", func() {
+			res, err := get("1", "2", "3")
+
+			Expect(err).To(BeNil())
+			Expect(res.Responses[name]).To(HaveLen(2))
+			Expect(res.UnprocessedKeys).To(BeEmpty())
+
+			for _, it := range res.Responses[name] {
+				Expect(it).To(HaveLen(2))
+				Expect(it).To(HaveKey("status"))
+			}
+		})
+
+		It("rejects more than 100 keys", func() {
+			var ids []string
+
+			for i := 0; i < 101; i++ {
+				ids = append(ids, strconv.Itoa(i))
+			}
+
+			_, err := get(ids...)
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+
+		It("rejects duplicate keys", func() {
+			_, err := get("1", "1")
+
+			Expect(code(err)).To(Equal("ValidationException"))
+		})
+	})
+
+	Describe("BatchWriteItem", func() {
+		write := func(ids ...string) error {
+			var writes []*dynamodb.WriteRequest
+
+			for _, id := range ids {
+				writes = append(writes, &dynamodb.WriteRequest{
+					PutRequest: &dynamodb.PutRequest{
+						Item: schedule(id, "QUEUED", 10).Item,
+					},
+				})
+			}
+
+			_, err := db.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]*dynamodb.WriteRequest{
+					name: writes,
+				},
+			})
+
+			return err
+		}
+
+		It("writes all items", func() {
+			Expect(write("1", "2")).To(Succeed())
+			Expect(db.Stream(name).Records).To(HaveLen(2))
+		})
+
+		It("rejects more than 25 writes", func() {
+			var ids []string
+
+			for i := 0; i < 26; i++ {
+				ids = append(ids, strconv.Itoa(i))
+			}
+
+			Expect(code(write(ids...))).To(Equal("ValidationException"))
+		})
+
+		It("rejects duplicate keys", func() {
+			Expect(code(write("1", "1"))).To(Equal("ValidationException"))
+		})
+	})
+
+	Describe("TransactWriteItems", func() {
+		put := func(id, status string) *dynamodb.TransactWriteItem {
+			p := schedule(id, status, 10)
+
+			return &dynamodb.TransactWriteItem{
+				Put: &dynamodb.Put{TableName: p.TableName, Item: p.Item},
+			}
+		}
+
+		cancel := func(id string) *dynamodb.TransactWriteItem {
+			return &dynamodb.TransactWriteItem{
+				Update: &dynamodb.Update{
+					TableName: aws.String(name),
+					Key: map[string]*dynamodb.AttributeValue{
+						"id": {S: aws.String(id)},
+					},
+					UpdateExpression:    aws.String("SET #s = :s1"),
+					ConditionExpression: aws.String("#s = :s2"),
+					ExpressionAttributeNames: map[string]*string{
+						"#s": aws.String("status"),
+					},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":s1": {S: aws.String("CANCELED")},
+						":s2": {S: aws.String("IDLE")},
+					},
+				},
+			}
+		}
+
+		transact := func(items ...*dynamodb.TransactWriteItem) error {
+			_, err := db.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+				TransactItems: items,
+			})
+
+			return err
+		}
+
+		status := func(id string) string {
+			res, err := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(id)},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			return aws.StringValue(res.Item["status"].S)
+		}
+
+		BeforeEach(func() {
+			_, _ = db.PutItem(schedule("1", "IDLE", 10))
+		})
+
+		It("writes all items", func() {
+			Expect(transact(cancel("1"), put("2", "IDLE"))).To(Succeed())
+
+			Expect(status("1")).To(Equal("CANCELED"))
+			Expect(status("2")).To(Equal("IDLE"))
+		})
+
+		It("writes none on failed condition", func() {
+			Expect(transact(cancel("1"))).To(Succeed())
+
+			err := transact(put("2", "IDLE"), cancel("1"))
+
+			tce, ok := err.(*dynamodb.TransactionCanceledException)
+			Expect(ok).To(BeTrue())
+			Expect(*tce.CancellationReasons[0].Code).To(Equal("None"))
+			Expect(*tce.CancellationReasons[1].Code).To(
+				Equal("ConditionalCheckFailed"))
+
+			res, _ := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("2")},
+				},
+			})
+			Expect(res.Item).To(BeNil())
+		})
+
+		It("rejects operations on one item", func() {
+			Expect(code(transact(put("2", "IDLE"), put("2", "QUEUED")))).To(
+				Equal("ValidationException"))
+		})
+	})
+
+	Describe("Stream", func() {
+		BeforeEach(func() {
+			_, _ = db.PutItem(schedule("1", "IDLE", 10))
+			_, _ = db.PutItem(schedule("1", "IDLE", 10))
+			_, _ = db.PutItem(schedule("1", "QUEUED", 10))
+			_, _ = db.DeleteItem(&dynamodb.DeleteItemInput{
+				TableName: aws.String(name),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+			})
+		})
+
+		It("records changes in order", func() {
+			records := db.Stream(name).Records
+
+			Expect(records).To(HaveLen(3))
+			Expect(records[0].EventName).To(Equal("INSERT"))
+			Expect(records[1].EventName).To(Equal("MODIFY"))
+			Expect(records[2].EventName).To(Equal("REMOVE"))
+		})
+
+		It("carries new image", func() {
+			records := db.Stream(name).Records
+
+			Expect(records[1].Change.NewImage["status"].String()).To(
+				Equal("QUEUED"))
+			Expect(records[1].Change.Keys["id"].String()).To(Equal("1"))
+		})
+
+		It("drains records", func() {
+			db.Stream(name)
+
+			Expect(db.Stream(name).Records).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,274 @@
+package dynamotest
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenName
+	tokenValue
+	tokenOperator
+	tokenWord
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("(),+-=", r):
+			tokens = append(tokens, token{tokenOperator, string(r)})
+			i++
+		case r == '<' || r == '>':
+			op := string(r)
+
+			if i+1 < len(runes) &&
+				(runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				op += string(runes[i+1])
+			}
+
+			tokens = append(tokens, token{tokenOperator, op})
+			i += len(op)
+		case r == '#' || r == ':' || isWordRune(r):
+			start := i
+			i++
+
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+
+			text := string(runes[start:i])
+
+			switch {
+			case r == '#':
+				tokens = append(tokens, token{tokenName, text})
+			case r == ':':
+				tokens = append(tokens, token{tokenValue, text})
+			default:
+				tokens = append(tokens, token{tokenWord, text})
+			}
+		case r == '.' || r == '[':
+			return nil, fmt.Errorf("nested attribute paths are not supported")
+		default:
+			return nil, fmt.Errorf("invalid character %q in %q", r, expression)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// parser turns an expression into a tree, substituting the placeholders as
+// it goes and recording which of them were used.
+type parser struct {
+	tokens []token
+	pos    int
+
+	names  map[string]*string
+	values map[string]*dynamodb.AttributeValue
+
+	usedNames  map[string]bool
+	usedValues map[string]bool
+}
+
+func newParser(
+	names map[string]*string,
+	values map[string]*dynamodb.AttributeValue) *parser {
+
+	return &parser{
+		names:      names,
+		values:     values,
+		usedNames:  make(map[string]bool),
+		usedValues: make(map[string]bool),
+	}
+}
+
+func (p *parser) reset(expression string) error {
+	tokens, err := tokenize(expression)
+
+	if err != nil {
+		return err
+	}
+
+	p.tokens = tokens
+	p.pos = 0
+
+	return nil
+}
+
+// unused reports placeholders that no expression referenced, DynamoDB
+// rejects those.
+func (p *parser) unused() error {
+	for name := range p.names {
+		if !p.usedNames[name] {
+			return fmt.Errorf(
+				"value provided in ExpressionAttributeNames unused in "+
+					"expressions: keys: {%s}", name)
+		}
+	}
+
+	for value := range p.values {
+		if !p.usedValues[value] {
+			return fmt.Errorf(
+				"value provided in ExpressionAttributeValues unused in "+
+					"expressions: keys: {%s}", value)
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) isWord(word string) bool {
+	t := p.peek()
+
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) isOperator(op string) bool {
+	t := p.peek()
+
+	return t.kind == tokenOperator && t.text == op
+}
+
+func (p *parser) expectOperator(op string) error {
+	if t := p.next(); t.kind != tokenOperator || t.text != op {
+		return fmt.Errorf("expected %q but found %q", op, t.text)
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEnd {
+		return fmt.Errorf("unexpected end of expression")
+	}
+
+	return fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *parser) end() error {
+	if t := p.peek(); t.kind != tokenEnd {
+		return p.unexpected(t)
+	}
+
+	return nil
+}
+
+func (p *parser) path() (string, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenName:
+		name, found := p.names[t.text]
+
+		if !found || name == nil {
+			return "", fmt.Errorf(
+				"an expression attribute name used in the document path "+
+					"is not defined; attribute name: %s", t.text)
+		}
+
+		p.usedNames[t.text] = true
+
+		return *name, nil
+	case tokenWord:
+		return t.text, nil
+	default:
+		return "", fmt.Errorf("expected attribute name but found %q", t.text)
+	}
+}
+
+func (p *parser) value() (*dynamodb.AttributeValue, error) {
+	t := p.next()
+
+	if t.kind != tokenValue {
+		return nil, fmt.Errorf("expected value but found %q", t.text)
+	}
+
+	value, found := p.values[t.text]
+
+	if !found || value == nil {
+		return nil, fmt.Errorf(
+			"an expression attribute value used in expression is not "+
+				"defined; attribute value: %s", t.text)
+	}
+
+	p.usedValues[t.text] = true
+
+	return value, nil
+}
+
+func (p *parser) parseOptionalCondition(
+	expression *string) (condition, error) {
+
+	if expression == nil {
+		return nil, nil
+	}
+
+	return p.parseCondition(*expression)
+}
+
+// parseProjection returns the attributes to keep, nil keeps all of them.
+func (p *parser) parseProjection(expression *string) ([]string, error) {
+	if expression == nil {
+		return nil, nil
+	}
+
+	if err := p.reset(*expression); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+
+	for {
+		path, err := p.path()
+
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+
+		if !p.isOperator(",") {
+			break
+		}
+
+		p.next()
+	}
+
+	return paths, p.end()
+}
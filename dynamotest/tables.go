@@ -0,0 +1,54 @@
+package dynamotest
+
+import "github.com/aws/aws-sdk-go/service/dynamodb"
+
+// SchedulerTable mirrors the schedule table of the stack with one shard.
+func SchedulerTable(name string) Table {
+	return schedulerTable(name, Index{
+		Name:     "ix_status_dueAt",
+		HashKey:  "status",
+		RangeKey: "dueAt",
+	})
+}
+
+// ShardedSchedulerTable mirrors the schedule table of the stack with more
+// than one shard, the status is only indexed along with its shard.
+func ShardedSchedulerTable(name string) Table {
+	return schedulerTable(name, Index{
+		Name:     "ix_statusShard_dueAt",
+		HashKey:  "statusShard",
+		RangeKey: "dueAt",
+	})
+}
+
+func schedulerTable(name string, status Index) Table {
+	return Table{
+		Name:    name,
+		HashKey: "id",
+		Indexes: []Index{
+			status,
+			{Name: "ix_dummy_dueAt", HashKey: "dummy", RangeKey: "dueAt"},
+			{
+				Name:     "ix_dummy_createdAt",
+				HashKey:  "dummy",
+				RangeKey: "createdAt",
+			},
+		},
+		StreamViewType: dynamodb.StreamViewTypeNewImage,
+	}
+}
+
+// CircuitTable mirrors the circuit breaker table of the stack.
+func CircuitTable(name string) Table {
+	return Table{Name: name, HashKey: "host"}
+}
+
+// LeaseTable mirrors the collector lease table of the stack.
+func LeaseTable(name string) Table {
+	return Table{Name: name, HashKey: "id"}
+}
+
+// StatsTable mirrors the schedule stats table of the stack.
+func StatsTable(name string) Table {
+	return Table{Name: name, HashKey: "bucket"}
+}
@@ -0,0 +1,393 @@
+package dynamotest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type arithmeticOperand struct {
+	left, right operand
+	subtract    bool
+}
+
+func (o arithmeticOperand) resolve(it item) *dynamodb.AttributeValue {
+	left, right := o.left.resolve(it), o.right.resolve(it)
+
+	if left == nil || right == nil || left.N == nil || right.N == nil {
+		return nil
+	}
+
+	x, xok := parseNumber(*left.N)
+	y, yok := parseNumber(*right.N)
+
+	if !xok || !yok {
+		return nil
+	}
+
+	if o.subtract {
+		y.Neg(y)
+	}
+
+	n := formatNumber(x.Add(x, y))
+
+	return &dynamodb.AttributeValue{N: &n}
+}
+
+type ifNotExistsOperand struct {
+	path     string
+	fallback operand
+}
+
+func (o ifNotExistsOperand) resolve(it item) *dynamodb.AttributeValue {
+	if value, found := it[o.path]; found {
+		return value
+	}
+
+	return o.fallback.resolve(it)
+}
+
+type listAppendOperand struct {
+	left, right operand
+}
+
+func (o listAppendOperand) resolve(it item) *dynamodb.AttributeValue {
+	left, right := o.left.resolve(it), o.right.resolve(it)
+
+	if left == nil || right == nil || left.L == nil || right.L == nil {
+		return nil
+	}
+
+	list := append(append([]*dynamodb.AttributeValue{}, left.L...), right.L...)
+
+	return &dynamodb.AttributeValue{L: list}
+}
+
+type setAction struct {
+	path  string
+	value operand
+}
+
+type valueAction struct {
+	path  string
+	value *dynamodb.AttributeValue
+}
+
+type update struct {
+	sets    []setAction
+	removes []string
+	adds    []valueAction
+	deletes []valueAction
+}
+
+// paths lists every attribute the update touches.
+func (u *update) paths() []string {
+	var paths []string
+
+	for _, a := range u.sets {
+		paths = append(paths, a.path)
+	}
+
+	paths = append(paths, u.removes...)
+
+	for _, a := range u.adds {
+		paths = append(paths, a.path)
+	}
+
+	for _, a := range u.deletes {
+		paths = append(paths, a.path)
+	}
+
+	return paths
+}
+
+// apply changes the item in place, every right hand side is resolved
+// against the item as it was before the update.
+func (u *update) apply(it item) error {
+	old := it.clone()
+
+	for _, a := range u.sets {
+		value := a.value.resolve(old)
+
+		if value == nil {
+			return fmt.Errorf(
+				"an operand in the update expression has an incorrect " +
+					"data type")
+		}
+
+		it[a.path] = cloneValue(value)
+	}
+
+	for _, path := range u.removes {
+		delete(it, path)
+	}
+
+	for _, a := range u.adds {
+		value, err := add(old[a.path], a.value)
+
+		if err != nil {
+			return err
+		}
+
+		it[a.path] = value
+	}
+
+	for _, a := range u.deletes {
+		value, err := remove(old[a.path], a.value)
+
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			delete(it, a.path)
+		} else {
+			it[a.path] = value
+		}
+	}
+
+	return nil
+}
+
+func add(
+	current *dynamodb.AttributeValue,
+	value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+
+	if current == nil {
+		return cloneValue(value), nil
+	}
+
+	switch {
+	case current.N != nil && value.N != nil:
+		return arithmeticOperand{
+			left:  valueOperand{current},
+			right: valueOperand{value},
+		}.resolve(nil), nil
+	case current.SS != nil && value.SS != nil:
+		return &dynamodb.AttributeValue{SS: unionOf(current.SS, value.SS)}, nil
+	case current.NS != nil && value.NS != nil:
+		return &dynamodb.AttributeValue{NS: unionOf(current.NS, value.NS)}, nil
+	default:
+		return nil, fmt.Errorf(
+			"an operand in the update expression has an incorrect data type")
+	}
+}
+
+func remove(
+	current *dynamodb.AttributeValue,
+	value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+
+	if current == nil {
+		return nil, nil
+	}
+
+	var left []*string
+
+	switch {
+	case current.SS != nil && value.SS != nil:
+		left = differenceOf(current.SS, value.SS)
+	case current.NS != nil && value.NS != nil:
+		left = differenceOf(current.NS, value.NS)
+	default:
+		return nil, fmt.Errorf(
+			"an operand in the update expression has an incorrect data type")
+	}
+
+	if len(left) == 0 {
+		return nil, nil
+	}
+
+	if current.SS != nil {
+		return &dynamodb.AttributeValue{SS: left}, nil
+	}
+
+	return &dynamodb.AttributeValue{NS: left}, nil
+}
+
+func unionOf(a, b []*string) []*string {
+	union := append([]*string{}, a...)
+
+	for _, s := range b {
+		if !containsString(union, *s) {
+			union = append(union, s)
+		}
+	}
+
+	return union
+}
+
+func differenceOf(a, b []*string) []*string {
+	var difference []*string
+
+	for _, s := range a {
+		if !containsString(b, *s) {
+			difference = append(difference, s)
+		}
+	}
+
+	return difference
+}
+
+func containsString(list []*string, s string) bool {
+	for _, e := range list {
+		if *e == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) parseUpdate(expression string) (*update, error) {
+	if err := p.reset(expression); err != nil {
+		return nil, err
+	}
+
+	u := update{}
+	seen := make(map[string]bool)
+
+	for p.peek().kind != tokenEnd {
+		t := p.next()
+		clause := strings.ToUpper(t.text)
+
+		if t.kind != tokenWord || seen[clause] {
+			return nil, p.unexpected(t)
+		}
+
+		seen[clause] = true
+
+		for {
+			if err := p.action(clause, &u); err != nil {
+				return nil, err
+			}
+
+			if !p.isOperator(",") {
+				break
+			}
+
+			p.next()
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("update expression is empty")
+	}
+
+	return &u, nil
+}
+
+func (p *parser) action(clause string, u *update) error {
+	path, err := p.path()
+
+	if err != nil {
+		return err
+	}
+
+	switch clause {
+	case "SET":
+		if err = p.expectOperator("="); err != nil {
+			return err
+		}
+
+		value, err := p.setOperand()
+
+		if err != nil {
+			return err
+		}
+
+		u.sets = append(u.sets, setAction{path, value})
+	case "REMOVE":
+		u.removes = append(u.removes, path)
+	case "ADD", "DELETE":
+		value, err := p.value()
+
+		if err != nil {
+			return err
+		}
+
+		if clause == "ADD" {
+			u.adds = append(u.adds, valueAction{path, value})
+		} else {
+			u.deletes = append(u.deletes, valueAction{path, value})
+		}
+	default:
+		return fmt.Errorf("unknown update clause %q", clause)
+	}
+
+	return nil
+}
+
+func (p *parser) setOperand() (operand, error) {
+	left, err := p.term()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isOperator("+") || p.isOperator("-") {
+		subtract := p.next().text == "-"
+
+		right, err := p.term()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return arithmeticOperand{left, right, subtract}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) term() (operand, error) {
+	if t := p.peek(); t.kind == tokenWord &&
+		p.tokens[p.pos+1].text == "(" {
+		switch strings.ToLower(t.text) {
+		case "if_not_exists":
+			p.next()
+			p.next()
+
+			path, err := p.path()
+
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expectOperator(","); err != nil {
+				return nil, err
+			}
+
+			fallback, err := p.term()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return ifNotExistsOperand{path, fallback}, p.expectOperator(")")
+		case "list_append":
+			p.next()
+			p.next()
+
+			left, err := p.term()
+
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expectOperator(","); err != nil {
+				return nil, err
+			}
+
+			right, err := p.term()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return listAppendOperand{left, right}, p.expectOperator(")")
+		}
+	}
+
+	return p.operand()
+}
@@ -0,0 +1,99 @@
+package dynamotest
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("update", func() {
+	var it item
+
+	values := map[string]*dynamodb.AttributeValue{
+		":one":  {N: aws.String("1")},
+		":zero": {N: aws.String("0")},
+		":s":    {S: aws.String("QUEUED")},
+		":tags": {SS: aws.StringSlice([]string{"b", "c"})},
+		":list": {L: []*dynamodb.AttributeValue{{S: aws.String("y")}}},
+	}
+
+	apply := func(expression string) error {
+		u, err := newParser(nil, values).parseUpdate(expression)
+
+		if err != nil {
+			return err
+		}
+
+		return u.apply(it)
+	}
+
+	BeforeEach(func() {
+		it = item{
+			"id":       {S: aws.String("1234")},
+			"failures": {N: aws.String("2")},
+			"status":   {S: aws.String("IDLE")},
+			"tags":     {SS: aws.StringSlice([]string{"a", "b"})},
+			"list":     {L: []*dynamodb.AttributeValue{{S: aws.String("x")}}},
+		}
+	})
+
+	It("sets values", func() {
+		Expect(apply("SET status = :s")).To(Succeed())
+		Expect(*it["status"].S).To(Equal("QUEUED"))
+	})
+
+	It("adds and subtracts", func() {
+		Expect(apply("SET failures = failures + :one")).To(Succeed())
+		Expect(*it["failures"].N).To(Equal("3"))
+
+		Expect(apply("SET failures = failures - :one")).To(Succeed())
+		Expect(*it["failures"].N).To(Equal("2"))
+	})
+
+	It("sets when missing", func() {
+		Expect(apply(
+			"SET count = if_not_exists(count, :zero) + :one")).To(Succeed())
+		Expect(*it["count"].N).To(Equal("1"))
+	})
+
+	It("appends lists", func() {
+		Expect(apply("SET list = list_append(list, :list)")).To(Succeed())
+		Expect(it["list"].L).To(HaveLen(2))
+	})
+
+	It("removes attributes", func() {
+		Expect(apply("SET status = :s REMOVE tags, list")).To(Succeed())
+		Expect(it).NotTo(HaveKey("tags"))
+		Expect(it).NotTo(HaveKey("list"))
+	})
+
+	It("adds to numbers and sets", func() {
+		Expect(apply("ADD failures :one, tags :tags, created :one")).
+			To(Succeed())
+		Expect(*it["failures"].N).To(Equal("3"))
+		Expect(aws.StringValueSlice(it["tags"].SS)).To(
+			ConsistOf("a", "b", "c"))
+		Expect(*it["created"].N).To(Equal("1"))
+	})
+
+	It("deletes from sets", func() {
+		Expect(apply("DELETE tags :tags")).To(Succeed())
+		Expect(aws.StringValueSlice(it["tags"].SS)).To(ConsistOf("a"))
+	})
+
+	It("resolves against the item before the update", func() {
+		Expect(apply(
+			"SET failures = :zero, status = failures")).To(Succeed())
+		Expect(*it["status"].N).To(Equal("2"))
+	})
+
+	It("rejects arithmetic on strings", func() {
+		Expect(apply("SET status = status + :one")).NotTo(Succeed())
+	})
+
+	It("rejects repeated clauses", func() {
+		Expect(apply("SET status = :s SET failures = :one")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,284 @@
+package dynamotest
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type item map[string]*dynamodb.AttributeValue
+
+func (it item) clone() item {
+	if it == nil {
+		return nil
+	}
+
+	c := make(item, len(it))
+
+	for k, v := range it {
+		c[k] = cloneValue(v)
+	}
+
+	return c
+}
+
+func (it item) project(names []string) item {
+	if names == nil {
+		return it
+	}
+
+	p := make(item)
+
+	for _, name := range names {
+		if value, found := it[name]; found {
+			p[name] = value
+		}
+	}
+
+	return p
+}
+
+func cloneValue(v *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if v == nil {
+		return nil
+	}
+
+	c := &dynamodb.AttributeValue{
+		BOOL: cloneBool(v.BOOL),
+		NULL: cloneBool(v.NULL),
+	}
+
+	if v.S != nil {
+		c.S = aws.String(*v.S)
+	}
+
+	if v.N != nil {
+		c.N = aws.String(*v.N)
+	}
+
+	if v.B != nil {
+		c.B = append([]byte{}, v.B...)
+	}
+
+	for _, s := range v.SS {
+		c.SS = append(c.SS, aws.String(*s))
+	}
+
+	for _, n := range v.NS {
+		c.NS = append(c.NS, aws.String(*n))
+	}
+
+	for _, b := range v.BS {
+		c.BS = append(c.BS, append([]byte{}, b...))
+	}
+
+	if v.M != nil {
+		c.M = item(v.M).clone()
+	}
+
+	if v.L != nil {
+		c.L = make([]*dynamodb.AttributeValue, len(v.L))
+
+		for i, e := range v.L {
+			c.L[i] = cloneValue(e)
+		}
+	}
+
+	return c
+}
+
+func cloneBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+
+	return aws.Bool(*b)
+}
+
+func numberValue(n int64) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(n, 10))}
+}
+
+func parseNumber(n string) (*big.Float, bool) {
+	f, ok := new(big.Float).SetPrec(256).SetString(n)
+
+	return f, ok
+}
+
+func formatNumber(f *big.Float) string {
+	return f.Text('f', -1)
+}
+
+func typeOf(v *dynamodb.AttributeValue) string {
+	switch {
+	case v.S != nil:
+		return dynamodb.ScalarAttributeTypeS
+	case v.N != nil:
+		return dynamodb.ScalarAttributeTypeN
+	case v.B != nil:
+		return dynamodb.ScalarAttributeTypeB
+	case v.BOOL != nil:
+		return "BOOL"
+	case v.NULL != nil:
+		return "NULL"
+	case v.SS != nil:
+		return "SS"
+	case v.NS != nil:
+		return "NS"
+	case v.BS != nil:
+		return "BS"
+	case v.M != nil:
+		return "M"
+	case v.L != nil:
+		return "L"
+	default:
+		return ""
+	}
+}
+
+// compare orders two scalars of the same type, ok is false for anything
+// else.
+func compare(a, b *dynamodb.AttributeValue) (order int, ok bool) {
+	switch {
+	case a.N != nil && b.N != nil:
+		x, xok := parseNumber(*a.N)
+		y, yok := parseNumber(*b.N)
+
+		if !xok || !yok {
+			return 0, false
+		}
+
+		return x.Cmp(y), true
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	default:
+		return 0, false
+	}
+}
+
+func equal(a, b *dynamodb.AttributeValue) bool {
+	if order, ok := compare(a, b); ok {
+		return order == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func contains(value, arg *dynamodb.AttributeValue) bool {
+	switch {
+	case value.S != nil && arg.S != nil:
+		return strings.Contains(*value.S, *arg.S)
+	case value.SS != nil && arg.S != nil:
+		for _, s := range value.SS {
+			if *s == *arg.S {
+				return true
+			}
+		}
+	case value.NS != nil && arg.N != nil:
+		for _, n := range value.NS {
+			if equal(&dynamodb.AttributeValue{N: n}, arg) {
+				return true
+			}
+		}
+	case value.L != nil:
+		for _, e := range value.L {
+			if equal(e, arg) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// keyOf encodes the values of the given key attributes, false when the
+// item lacks one of them.
+func keyOf(it item, names ...string) (string, bool) {
+	var parts []string
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		value, found := it[name]
+
+		if !found || value == nil {
+			return "", false
+		}
+
+		switch {
+		case value.S != nil:
+			parts = append(parts, "S:"+*value.S)
+		case value.N != nil:
+			n, ok := parseNumber(*value.N)
+
+			if !ok {
+				return "", false
+			}
+
+			parts = append(parts, "N:"+formatNumber(n))
+		case value.B != nil:
+			parts = append(parts, "B:"+string(value.B))
+		default:
+			return "", false
+		}
+	}
+
+	return strings.Join(parts, "|"), true
+}
+
+func toEventAttributes(it item) map[string]events.DynamoDBAttributeValue {
+	if it == nil {
+		return nil
+	}
+
+	attrs := make(map[string]events.DynamoDBAttributeValue, len(it))
+
+	for k, v := range it {
+		attrs[k] = toEventAttribute(v)
+	}
+
+	return attrs
+}
+
+func toEventAttribute(
+	v *dynamodb.AttributeValue) events.DynamoDBAttributeValue {
+
+	switch {
+	case v.S != nil:
+		return events.NewStringAttribute(*v.S)
+	case v.N != nil:
+		return events.NewNumberAttribute(*v.N)
+	case v.B != nil:
+		return events.NewBinaryAttribute(v.B)
+	case v.BOOL != nil:
+		return events.NewBooleanAttribute(*v.BOOL)
+	case v.SS != nil:
+		return events.NewStringSetAttribute(aws.StringValueSlice(v.SS))
+	case v.NS != nil:
+		return events.NewNumberSetAttribute(aws.StringValueSlice(v.NS))
+	case v.BS != nil:
+		return events.NewBinarySetAttribute(v.BS)
+	case v.M != nil:
+		return events.NewMapAttribute(toEventAttributes(v.M))
+	case v.L != nil:
+		list := make([]events.DynamoDBAttributeValue, len(v.L))
+
+		for i, e := range v.L {
+			list[i] = toEventAttribute(e)
+		}
+
+		return events.NewListAttribute(list)
+	default:
+		return events.NewNullAttribute()
+	}
+}
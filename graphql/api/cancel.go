@@ -1,10 +1,6 @@
 package api
 
-import (
-	"fmt"
-
-	"github.com/graphql-go/graphql"
-)
+import "github.com/graphql-go/graphql"
 
 func (f *Factory) Cancel() *graphql.Field {
 	return &graphql.Field{
@@ -16,8 +12,8 @@ func (f *Factory) Cancel() *graphql.Field {
 		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 			id := p.Args["id"].(string)
 
-			if id == "" {
-				return false, fmt.Errorf("id is required")
+			if err := ValidateID(id); err != nil {
+				return false, err
 			}
 
 			return f.storage.Cancel(p.Context, id)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -19,7 +20,7 @@ var _ = Describe("Cancel", func() {
 
 	BeforeEach(func() {
 		db = fakeCancelStorage{}
-		factory := NewFactory(&db)
+		factory := NewFactory(&db, clock.System, nil)
 
 		field = factory.Cancel()
 	})
@@ -1,10 +1,6 @@
 package api
 
 import (
-	"fmt"
-	"net/url"
-	"time"
-
 	"github.com/graphql-go/graphql"
 
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
@@ -28,24 +24,23 @@ func (f *Factory) Create() *graphql.Field {
 			"body": &graphql.ArgumentConfig{
 				Type: graphql.String,
 			},
+			"deadline": &graphql.ArgumentConfig{
+				Type: graphql.DateTime,
+			},
+			"maxLateness": &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "Seconds after dueAt the schedule may still fire",
+			},
 		},
 		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 			var input storage.CreateInput
 
 			if err := loadStruct(p.Args, &input); err != nil {
-				return nil, fmt.Errorf("invalid input")
-			}
-
-			if input.DueAt.Before(time.Now()) {
-				return nil, fmt.Errorf("dueAt must be in future")
-			}
-
-			if input.URL == "" {
-				return nil, fmt.Errorf("url is required")
+				return nil, inputError("invalid input")
 			}
 
-			if _, err := url.ParseRequestURI(input.URL); err != nil {
-				return nil, fmt.Errorf("invalid url")
+			if err := ValidateCreate(&input, f.clock.Now()); err != nil {
+				return nil, err
 			}
 
 			return f.storage.Create(p.Context, input)
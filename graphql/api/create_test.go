@@ -7,6 +7,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -21,7 +22,7 @@ var _ = Describe("Create", func() {
 
 	BeforeEach(func() {
 		db = fakeCreateStorage{}
-		factory := NewFactory(&db)
+		factory := NewFactory(&db, clock.System, nil)
 
 		field = factory.Create()
 	})
@@ -55,6 +56,14 @@ var _ = Describe("Create", func() {
 		It("has body as nullable String", func() {
 			Expect(field.Args["body"].Type).To(Equal(graphql.String))
 		})
+
+		It("has deadline as nullable DateTime", func() {
+			Expect(field.Args["deadline"].Type).To(Equal(graphql.DateTime))
+		})
+
+		It("has maxLateness as nullable Int", func() {
+			Expect(field.Args["maxLateness"].Type).To(Equal(graphql.Int))
+		})
 	})
 
 	Describe("Resolve", func() {
@@ -109,7 +118,91 @@ var _ = Describe("Create", func() {
 			})
 		})
 
+		Describe("valid input with maxLateness", func() {
+			var dueAt time.Time
+
+			BeforeEach(func() {
+				dueAt = time.Now().Add(time.Minute * 1)
+
+				_, _ = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"dueAt":       dueAt,
+						"url":         url,
+						"method":      method,
+						"maxLateness": 30,
+					},
+				})
+			})
+
+			It("sends deadline to db", func() {
+				Expect(db.Input.Deadline.Unix()).To(
+					Equal(dueAt.Add(time.Second * 30).Unix()))
+			})
+		})
+
 		Describe("invalid input", func() {
+			Context("both deadline and maxLateness", func() {
+				var err error
+
+				BeforeEach(func() {
+					dueAt := time.Now().Add(time.Minute * 1)
+
+					_, err = field.Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt":       dueAt,
+							"url":         url,
+							"method":      method,
+							"deadline":    dueAt.Add(time.Minute),
+							"maxLateness": 30,
+						},
+					})
+				})
+
+				It("returns error", func() {
+					Expect(err).NotTo(BeNil())
+				})
+			})
+
+			Context("not positive maxLateness", func() {
+				var err error
+
+				BeforeEach(func() {
+					_, err = field.Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt":       time.Now().Add(time.Minute * 1),
+							"url":         url,
+							"method":      method,
+							"maxLateness": 0,
+						},
+					})
+				})
+
+				It("returns error", func() {
+					Expect(err).NotTo(BeNil())
+				})
+			})
+
+			Context("deadline before dueAt", func() {
+				var err error
+
+				BeforeEach(func() {
+					dueAt := time.Now().Add(time.Minute * 1)
+
+					_, err = field.Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt":    dueAt,
+							"url":      url,
+							"method":   method,
+							"deadline": dueAt.Add(-time.Second),
+						},
+					})
+				})
+
+				It("returns error", func() {
+					Expect(err).NotTo(BeNil())
+				})
+			})
+
 			Context("not future dua at", func() {
 				var (
 					res interface{}
@@ -139,6 +232,36 @@ var _ = Describe("Create", func() {
 				})
 			})
 
+			Context("due at in future of clock", func() {
+				var (
+					res interface{}
+					err error
+				)
+
+				BeforeEach(func() {
+					factory := NewFactory(
+						&db,
+						clock.NewFake(time.Now().Add(-time.Hour)),
+						nil)
+
+					res, err = factory.Create().Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt":  time.Now().Add(-time.Minute * 1),
+							"url":    url,
+							"method": method,
+						},
+					})
+				})
+
+				It("returns id", func() {
+					Expect(res).NotTo(BeNil())
+				})
+
+				It("does not return error", func() {
+					Expect(err).To(BeNil())
+				})
+			})
+
 			Context("missing url", func() {
 				var (
 					res interface{}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+var (
+	ErrInvalidCursor    error = &InputError{"invalid cursor"}
+	ErrCursorMismatch   error = &InputError{"cursor does not match the filters"}
+	ErrStartKeyMismatch error = &InputError{
+		"startKey does not match the filters",
+	}
+
+	randomSecret     []byte
+	randomSecretOnce sync.Once
+)
+
+// cursor is the position of a list page, it is bound to the index and the
+// filters it was read with so it can not be replayed against another one.
+type cursor struct {
+	Index     string           `json:"i"`
+	Direction string           `json:"d"`
+	Status    string           `json:"s,omitempty"`
+	From      *int64           `json:"f,omitempty"`
+	To        *int64           `json:"t,omitempty"`
+	Key       *storage.ListKey `json:"k"`
+}
+
+// EncodeCursor turns the next key of a list into the opaque string a client
+// passes back with the same filters for the next page.
+func EncodeCursor(input storage.ListInput, key *storage.ListKey) (
+	string, error) {
+
+	c := newCursor(input)
+	c.Key = key
+
+	buff, err := json.Marshal(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	return encodeSegment(buff) + "." + encodeSegment(sign(buff)), nil
+}
+
+// DecodeCursor verifies the signature of the cursor and that it was issued
+// for the filters of the input before returning its key.
+func DecodeCursor(input storage.ListInput, value string) (
+	*storage.ListKey, error) {
+
+	payload, signature, found := strings.Cut(value, ".")
+
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	buff, err := base64.RawURLEncoding.DecodeString(payload)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+
+	if err != nil || !hmac.Equal(mac, sign(buff)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+
+	if err = json.Unmarshal(buff, &c); err != nil || c.Key == nil {
+		return nil, ErrInvalidCursor
+	}
+
+	key := c.Key
+	c.Key = nil
+
+	expected, _ := json.Marshal(newCursor(input))
+	actual, _ := json.Marshal(c)
+
+	if !bytes.Equal(expected, actual) {
+		return nil, ErrCursorMismatch
+	}
+
+	return key, nil
+}
+
+// CheckStartKey holds the unsigned start key of the deprecated startKey
+// argument to the index and filters of the input like a cursor is held to
+// them, it must have the keys of the index the list reads, in the range of
+// the dueAt filter.
+func CheckStartKey(input storage.ListInput, key *storage.ListKey) error {
+	c := newCursor(input)
+
+	if c.Index == "createdAt" {
+		if key.CreatedAt == nil || key.DueAt != nil || key.Status != nil {
+			return ErrStartKeyMismatch
+		}
+
+		return nil
+	}
+
+	if key.DueAt == nil || key.CreatedAt != nil {
+		return ErrStartKeyMismatch
+	}
+
+	if c.Index == "status" {
+		if key.Status == nil || *key.Status != input.Status {
+			return ErrStartKeyMismatch
+		}
+	} else if key.Status != nil {
+		return ErrStartKeyMismatch
+	}
+
+	if c.From != nil && (*key.DueAt < *c.From || *key.DueAt > *c.To) {
+		return ErrStartKeyMismatch
+	}
+
+	return nil
+}
+
+func newCursor(input storage.ListInput) *cursor {
+	c := &cursor{
+		Index:     "dueAt",
+		Direction: storage.ListDirectionDesc,
+		Status:    input.Status,
+	}
+
+	if input.OrderBy == storage.ListOrderCreatedAt {
+		c.Index = "createdAt"
+	} else if input.Status != "" {
+		c.Index = "status"
+	}
+
+	if input.Direction != "" {
+		c.Direction = input.Direction
+	}
+
+	if input.DueAt != nil {
+		from, to := input.DueAt.From.Unix(), input.DueAt.To.Unix()
+		c.From, c.To = &from, &to
+	}
+
+	return c
+}
+
+func encodeSegment(buff []byte) string {
+	return base64.RawURLEncoding.EncodeToString(buff)
+}
+
+func sign(buff []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret())
+	mac.Write(buff)
+
+	return mac.Sum(nil)
+}
+
+// cursorSecret falls back to a random secret, the cursors are then only
+// valid in the process that issued them.
+func cursorSecret() []byte {
+	if secret := os.Getenv("SCHEDULER_CURSOR_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	randomSecretOnce.Do(func() {
+		randomSecret = make([]byte, sha256.Size)
+		_, _ = rand.Read(randomSecret)
+	})
+
+	return randomSecret
+}
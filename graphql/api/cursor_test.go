@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cursor", func() {
+	var (
+		input storage.ListInput
+		key   *storage.ListKey
+	)
+
+	BeforeEach(func() {
+		input = storage.ListInput{
+			Status: storage.ScheduleStatusIdle,
+			DueAt: &storage.DateRange{
+				From: time.Unix(1000, 0),
+				To:   time.Unix(2000, 0),
+			},
+			Limit: 10,
+		}
+
+		key = &storage.ListKey{
+			ID:     "1234",
+			DueAt:  aws.Int64(1500),
+			Status: aws.String(storage.ScheduleStatusIdle),
+		}
+	})
+
+	It("decodes encoded key", func() {
+		cursor, err := EncodeCursor(input, key)
+		Expect(err).To(BeNil())
+
+		Expect(DecodeCursor(input, cursor)).To(Equal(key))
+	})
+
+	It("is opaque", func() {
+		cursor, _ := EncodeCursor(input, key)
+
+		Expect(cursor).NotTo(ContainSubstring("1234"))
+	})
+
+	It("allows another limit", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.Limit = 50
+
+		Expect(DecodeCursor(input, cursor)).To(Equal(key))
+	})
+
+	It("rejects malformed cursor", func() {
+		_, err := DecodeCursor(input, "%%")
+
+		Expect(err).To(Equal(ErrInvalidCursor))
+	})
+
+	It("rejects tampered key", func() {
+		cursor, _ := EncodeCursor(input, key)
+		signature := cursor[strings.Index(cursor, "."):]
+
+		payload := base64.RawURLEncoding.EncodeToString([]byte(
+			`{"i":"status","d":"DESC","s":"IDLE","f":1000,"t":2000,` +
+				`"k":{"id":"9999","dueAt":1500}}`))
+
+		_, err := DecodeCursor(input, payload+signature)
+
+		Expect(err).To(Equal(ErrInvalidCursor))
+	})
+
+	It("rejects cursor signed with another secret", func() {
+		cursor, _ := EncodeCursor(input, key)
+
+		os.Setenv("SCHEDULER_CURSOR_SECRET", "another")
+		defer os.Unsetenv("SCHEDULER_CURSOR_SECRET")
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrInvalidCursor))
+	})
+
+	It("rejects cursor of another status", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.Status = storage.ScheduleStatusQueued
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrCursorMismatch))
+	})
+
+	It("rejects cursor of another index", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.Status = ""
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrCursorMismatch))
+	})
+
+	It("rejects cursor of another order", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.OrderBy = storage.ListOrderCreatedAt
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrCursorMismatch))
+	})
+
+	It("rejects cursor of another direction", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.Direction = storage.ListDirectionAsc
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrCursorMismatch))
+	})
+
+	It("takes missing order as dueAt descending", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.OrderBy = storage.ListOrderDueAt
+		input.Direction = storage.ListDirectionDesc
+
+		Expect(DecodeCursor(input, cursor)).To(Equal(key))
+	})
+
+	It("rejects cursor of another dueAt", func() {
+		cursor, _ := EncodeCursor(input, key)
+		input.DueAt.To = time.Unix(3000, 0)
+
+		_, err := DecodeCursor(input, cursor)
+
+		Expect(err).To(Equal(ErrCursorMismatch))
+	})
+
+	Describe("CheckStartKey", func() {
+		It("accepts key of index within filters", func() {
+			Expect(CheckStartKey(input, key)).To(Succeed())
+		})
+
+		It("rejects key of another status", func() {
+			key.Status = aws.String(storage.ScheduleStatusQueued)
+
+			Expect(CheckStartKey(input, key)).To(Equal(ErrStartKeyMismatch))
+		})
+
+		It("rejects key without status of status index", func() {
+			key.Status = nil
+
+			Expect(CheckStartKey(input, key)).To(Equal(ErrStartKeyMismatch))
+		})
+
+		It("rejects key outside dueAt", func() {
+			key.DueAt = aws.Int64(2001)
+
+			Expect(CheckStartKey(input, key)).To(Equal(ErrStartKeyMismatch))
+		})
+
+		It("rejects key of another index", func() {
+			input.OrderBy = storage.ListOrderCreatedAt
+
+			Expect(CheckStartKey(input, key)).To(Equal(ErrStartKeyMismatch))
+		})
+
+		It("accepts key of createdAt index", func() {
+			input.OrderBy = storage.ListOrderCreatedAt
+			key = &storage.ListKey{ID: "1234", CreatedAt: aws.Int64(1500)}
+
+			Expect(CheckStartKey(input, key)).To(Succeed())
+		})
+
+		It("rejects status of dueAt index", func() {
+			input.Status = ""
+
+			Expect(CheckStartKey(input, key)).To(Equal(ErrStartKeyMismatch))
+		})
+	})
+})
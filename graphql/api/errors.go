@@ -0,0 +1,29 @@
+package api
+
+import "fmt"
+
+// CodeBadUserInput is the extensions code of the errors of an input the api
+// rejects, sending it again fails the same way.
+const CodeBadUserInput = "BAD_USER_INPUT"
+
+// CodeValidationFailed is the extensions code of an operation that does not
+// parse, validate against the schema or coerce its variables.
+const CodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+
+// InputError is an input the api rejects, it carries CodeBadUserInput in
+// the response.
+type InputError struct {
+	Message string
+}
+
+func (e *InputError) Error() string {
+	return e.Message
+}
+
+func (e *InputError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": CodeBadUserInput}
+}
+
+func inputError(format string, a ...interface{}) error {
+	return &InputError{fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InputError", func() {
+	It("returns message", func() {
+		Expect(inputError("limit must be between 1-%d", 100)).To(
+			MatchError("limit must be between 1-100"))
+	})
+
+	It("carries bad user input code", func() {
+		err := &InputError{"invalid url"}
+
+		Expect(err.Extensions()).To(
+			Equal(map[string]interface{}{"code": CodeBadUserInput}))
+	})
+})
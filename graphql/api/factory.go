@@ -3,23 +3,45 @@ package api
 import (
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 )
 
 type Factory struct {
 	storage storage.Storage
+	clock   clock.Clock
+
+	scheduleType     *graphql.Object
+	scheduleListType *graphql.Object
 }
 
-func NewFactory(storage storage.Storage) *Factory {
-	return &Factory{storage}
+// NewFactory loads offloaded bodies and results from the blob store, which
+// is nil when the storage does not offload.
+func NewFactory(
+	storage storage.Storage,
+	clock clock.Clock,
+	blobs blob.Store) *Factory {
+
+	schedule := newScheduleType(blobs)
+
+	return &Factory{
+		storage:          storage,
+		clock:            clock,
+		scheduleType:     schedule,
+		scheduleListType: newScheduleListType(schedule),
+	}
 }
 
 func (f *Factory) Schema() (graphql.Schema, error) {
 	query := graphql.NewObject(graphql.ObjectConfig{
 		Name: "Queries",
 		Fields: graphql.Fields{
-			"get":  f.Get(),
-			"list": f.List(),
+			"get":       f.Get(),
+			"schedules": f.Schedules(),
+			"list":      f.List(),
+			"lateness":  f.Lateness(),
+			"stats":     f.Stats(),
 		},
 	})
 
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -15,7 +16,7 @@ var _ = Describe("Factory", func() {
 
 		BeforeEach(func() {
 			db := fakeStorage{}
-			factory = NewFactory(&db)
+			factory = NewFactory(&db, clock.System, nil)
 		})
 
 		It("returns new factory", func() {
@@ -31,7 +32,7 @@ var _ = Describe("Factory", func() {
 
 		BeforeEach(func() {
 			db := fakeStorage{}
-			factory := NewFactory(&db)
+			factory := NewFactory(&db, clock.System, nil)
 
 			schema, err = factory.Schema()
 		})
@@ -48,10 +49,22 @@ var _ = Describe("Factory", func() {
 			Expect(schema.QueryType().Fields()["get"]).NotTo(BeNil())
 		})
 
+		It("has schedules in query", func() {
+			Expect(schema.QueryType().Fields()["schedules"]).NotTo(BeNil())
+		})
+
 		It("has list in query", func() {
 			Expect(schema.QueryType().Fields()["list"]).NotTo(BeNil())
 		})
 
+		It("has lateness in query", func() {
+			Expect(schema.QueryType().Fields()["lateness"]).NotTo(BeNil())
+		})
+
+		It("has stats in query", func() {
+			Expect(schema.QueryType().Fields()["stats"]).NotTo(BeNil())
+		})
+
 		It("has create in mutation", func() {
 			Expect(schema.MutationType().Fields()["create"]).NotTo(BeNil())
 		})
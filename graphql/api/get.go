@@ -1,10 +1,6 @@
 package api
 
-import (
-	"fmt"
-
-	"github.com/graphql-go/graphql"
-)
+import "github.com/graphql-go/graphql"
 
 func (f *Factory) Get() *graphql.Field {
 	return &graphql.Field{
@@ -16,12 +12,28 @@ func (f *Factory) Get() *graphql.Field {
 		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 			id := p.Args["id"].(string)
 
-			if id == "" {
-				return nil, fmt.Errorf("id is required")
+			if err := ValidateID(id); err != nil {
+				return nil, err
+			}
+
+			loader := loaderFrom(p.Context)
+
+			if loader == nil {
+				return f.storage.Get(p.Context, id)
 			}
 
-			return f.storage.Get(p.Context, id)
+			load := loader.load(p.Context, []string{id})
+
+			return func() (interface{}, error) {
+				schedules, err := load()
+
+				if err != nil {
+					return nil, err
+				}
+
+				return schedules[0], nil
+			}, nil
 		},
-		Type: scheduleType,
+		Type: f.scheduleType,
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -19,7 +20,7 @@ var _ = Describe("Get", func() {
 
 	BeforeEach(func() {
 		db = fakeGetStorage{}
-		factory := NewFactory(&db)
+		factory := NewFactory(&db, clock.System, nil)
 
 		field = factory.Get()
 	})
@@ -65,6 +66,40 @@ var _ = Describe("Get", func() {
 			})
 		})
 
+		Context("with loader", func() {
+			const id = "1234567890"
+
+			var (
+				res interface{}
+				err error
+			)
+
+			BeforeEach(func() {
+				db.Schedule = &storage.Schedule{}
+
+				res, err = field.Resolve(graphql.ResolveParams{
+					Context: WithLoader(context.TODO(), NewLoader(&db, 1)),
+					Args: map[string]interface{}{
+						"id": id,
+					},
+				})
+			})
+
+			It("loads once resolved", func() {
+				Expect(db.ID).To(BeEmpty())
+
+				schedule, err := res.(func() (interface{}, error))()
+
+				Expect(db.ID).To(Equal(id))
+				Expect(schedule).To(Equal(db.Schedule))
+				Expect(err).To(BeNil())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
 		Context("missing id", func() {
 			var (
 				res interface{}
@@ -91,7 +126,7 @@ var _ = Describe("Get", func() {
 
 	Describe("Type", func() {
 		It("returns Schedule", func() {
-			Expect(field.Type).To(Equal(scheduleType))
+			Expect(field.Type.Name()).To(Equal("Schedule"))
 		})
 	})
 })
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+// maxLatenessRange keeps a lateness read within a day of schedules, unlike
+// stats it reads every one of them.
+const maxLatenessRange = 24 * time.Hour
+
+func (f *Factory) Lateness() *graphql.Field {
+	return &graphql.Field{
+		Args: graphql.FieldConfigArgument{
+			"dueAt": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(dataRangeType),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var input storage.LatenessInput
+
+			if err := loadStruct(p.Args, &input); err != nil {
+				return nil, inputError("invalid input")
+			}
+
+			if !input.DueAt.To.After(input.DueAt.From) {
+				return nil, inputError("dueAt to must be after dueAt from")
+			}
+
+			if input.DueAt.To.Sub(input.DueAt.From) > maxLatenessRange {
+				return nil, inputError("dueAt must not span more than 24 hours")
+			}
+
+			return f.storage.Lateness(p.Context, input)
+		},
+		Type: latenessType,
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lateness field", func() {
+	var (
+		field *graphql.Field
+		db    fakeLatenessStorage
+	)
+
+	BeforeEach(func() {
+		db = fakeLatenessStorage{}
+		factory := NewFactory(&db, clock.System, nil)
+
+		field = factory.Lateness()
+	})
+
+	Describe("Args", func() {
+		It("has dueAt as non-nullable DateRange", func() {
+			t := field.Args["dueAt"].Type
+
+			Expect(t).To(BeAssignableToTypeOf(&graphql.NonNull{}))
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(dataRangeType))
+		})
+	})
+
+	Describe("Resolve", func() {
+		Describe("valid input", func() {
+			var (
+				res interface{}
+				err error
+
+				from time.Time
+				to   time.Time
+			)
+
+			BeforeEach(func() {
+				db.ReturnLateness = &storage.Lateness{Count: 3}
+
+				from = time.Now().Add(-time.Hour)
+				to = time.Now()
+
+				res, err = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"dueAt": map[string]interface{}{
+							"from": from,
+							"to":   to,
+						},
+					},
+				})
+			})
+
+			It("sends input to db", func() {
+				Expect(db.Input.DueAt.From.Unix()).To(Equal(from.Unix()))
+				Expect(db.Input.DueAt.To.Unix()).To(Equal(to.Unix()))
+			})
+
+			It("returns lateness", func() {
+				Expect(res).To(Equal(db.ReturnLateness))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("invalid input", func() {
+			Context("dueAt to before from", func() {
+				var (
+					res interface{}
+					err error
+				)
+
+				BeforeEach(func() {
+					res, err = field.Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt": map[string]interface{}{
+								"from": time.Now(),
+								"to":   time.Now().Add(-time.Hour),
+							},
+						},
+					})
+				})
+
+				It("does not return lateness", func() {
+					Expect(res).To(BeNil())
+				})
+
+				It("returns error", func() {
+					Expect(err).NotTo(BeNil())
+				})
+			})
+
+			Context("dueAt longer than a day", func() {
+				var (
+					res interface{}
+					err error
+				)
+
+				BeforeEach(func() {
+					res, err = field.Resolve(graphql.ResolveParams{
+						Args: map[string]interface{}{
+							"dueAt": map[string]interface{}{
+								"from": time.Now().Add(-25 * time.Hour),
+								"to":   time.Now(),
+							},
+						},
+					})
+				})
+
+				It("does not return lateness", func() {
+					Expect(res).To(BeNil())
+				})
+
+				It("returns error", func() {
+					Expect(err).To(MatchError(
+						"dueAt must not span more than 24 hours"))
+				})
+			})
+		})
+	})
+
+	Describe("Type", func() {
+		It("returns Lateness", func() {
+			Expect(field.Type).To(Equal(latenessType))
+		})
+	})
+})
+
+type fakeLatenessStorage struct {
+	storage.Storage
+	Input storage.LatenessInput
+
+	ReturnLateness *storage.Lateness
+}
+
+func (srv *fakeLatenessStorage) Lateness(
+	_ context.Context,
+	input storage.LatenessInput) (*storage.Lateness, error) {
+
+	srv.Input = input
+
+	return srv.ReturnLateness, nil
+}
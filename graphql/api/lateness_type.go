@@ -0,0 +1,31 @@
+package api
+
+import "github.com/graphql-go/graphql"
+
+var latenessType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Lateness",
+	Description: "Firing lag statistics in milliseconds",
+	Fields: graphql.Fields{
+		"count": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"expired": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"average": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"p50": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"p95": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"p99": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"max": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	},
+})
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lateness", func() {
+	Describe("Name", func() {
+		It("is Lateness", func() {
+			Expect(latenessType.Name()).To(Equal("Lateness"))
+		})
+	})
+
+	Describe("Fields", func() {
+		for _, name := range []string{
+			"count", "expired", "average", "p50", "p95", "p99", "max",
+		} {
+			field := name
+
+			It(fmt.Sprintf("has %s as non-nullable Int", field), func() {
+				t := latenessType.Fields()[field].Type
+
+				Expect(t).To(BeAssignableToTypeOf(&graphql.NonNull{}))
+				Expect(t.(*graphql.NonNull).OfType).To(Equal(graphql.Int))
+			})
+		}
+	})
+})
@@ -0,0 +1,308 @@
+package api
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// maxItems is the most schedules a field returns, the list limit and the
+// ids of the schedules query are rejected above it.
+const maxItems = 100
+
+// scans read every schedule of a range to answer a single value, they are
+// counted like a field returning the most schedules.
+var scans = map[string]bool{"lateness": true}
+
+// Limits bound an operation before it is executed, a zero leaves that one
+// unbounded.
+type Limits struct {
+	// Depth is the deepest a field may be nested, root fields are at one.
+	Depth int
+
+	// Aliases is the number of aliased fields.
+	Aliases int
+
+	// Cost is the sum of every field, the ones under a field that returns
+	// many are counted once per item it may return, its limit or the
+	// length of its list argument, and the ones under lateness as often as
+	// under a full page.
+	Cost int
+}
+
+// Check returns an error describing the first limit the operation exceeds,
+// an operation that does not parse or cannot be found is left for the
+// execution to report. Introspection fields are neither counted in the
+// depth nor in the cost.
+func (l Limits) Check(
+	schema graphql.Schema,
+	query string,
+	operationName string,
+	variables map[string]interface{}) error {
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+
+	if err != nil {
+		return nil
+	}
+
+	w := walker{
+		schema:    schema,
+		variables: variables,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+
+	var operations []*ast.OperationDefinition
+
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" ||
+				(d.Name != nil && d.Name.Value == operationName) {
+				operations = append(operations, d)
+			}
+		case *ast.FragmentDefinition:
+			w.fragments[d.Name.Value] = d
+		}
+	}
+
+	if len(operations) != 1 {
+		return nil
+	}
+
+	operation := operations[0]
+	var root graphql.Type = schema.QueryType()
+
+	if operation.Operation == ast.OperationTypeMutation {
+		root = nil
+
+		if mutation := schema.MutationType(); mutation != nil {
+			root = mutation
+		}
+	}
+
+	depth, cost := w.selections(operation.SelectionSet, root)
+
+	if l.Depth > 0 && depth > l.Depth {
+		return inputError(
+			"query depth %d exceeds the maximum of %d", depth, l.Depth)
+	}
+
+	if l.Aliases > 0 && w.aliases > l.Aliases {
+		return inputError(
+			"query has %d aliases, the maximum is %d", w.aliases, l.Aliases)
+	}
+
+	if l.Cost > 0 && cost > l.Cost {
+		return inputError(
+			"query cost %d exceeds the maximum of %d", cost, l.Cost)
+	}
+
+	return nil
+}
+
+type walker struct {
+	schema    graphql.Schema
+	variables map[string]interface{}
+	fragments map[string]*ast.FragmentDefinition
+	visiting  map[string]bool
+	aliases   int
+}
+
+// selections returns the depth and the cost of the selection set of a field
+// of the parent type, the parent is nil when it is not known.
+func (w *walker) selections(
+	set *ast.SelectionSet,
+	parent graphql.Type) (depth int, cost int) {
+
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(s, parent)
+		case *ast.InlineFragment:
+			d, c = w.selections(s.SelectionSet, w.condition(
+				s.TypeCondition,
+				parent))
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := w.fragments[name]
+
+			if !ok || w.visiting[name] {
+				continue
+			}
+
+			w.visiting[name] = true
+			d, c = w.selections(fragment.SelectionSet, w.condition(
+				fragment.TypeCondition,
+				parent))
+			w.visiting[name] = false
+		}
+
+		if d > depth {
+			depth = d
+		}
+
+		cost = add(cost, c)
+	}
+
+	return depth, cost
+}
+
+func (w *walker) field(
+	field *ast.Field,
+	parent graphql.Type) (depth int, cost int) {
+
+	if field.Alias != nil {
+		w.aliases++
+	}
+
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+
+	var definition *graphql.FieldDefinition
+
+	if fields, ok := parent.(interface {
+		Fields() graphql.FieldDefinitionMap
+	}); ok {
+		definition = fields.Fields()[field.Name.Value]
+	}
+
+	var child graphql.Type
+
+	if definition != nil {
+		child, _ = graphql.GetNamed(definition.Type).(graphql.Type)
+	}
+
+	depth, cost = w.selections(field.SelectionSet, child)
+
+	return depth + 1, add(1, multiply(w.items(field, definition), cost))
+}
+
+// condition is the type a fragment applies to, the parent when it has no
+// condition.
+func (w *walker) condition(
+	named *ast.Named,
+	parent graphql.Type) graphql.Type {
+
+	if named == nil || named.Name == nil {
+		return parent
+	}
+
+	return w.schema.Type(named.Name.Value)
+}
+
+// items is the most a field may return, its limit or the length of its
+// list argument up to maxItems, maxItems for a scan and one for the rest.
+func (w *walker) items(
+	field *ast.Field,
+	definition *graphql.FieldDefinition) int {
+
+	if definition != nil && scans[definition.Name] {
+		return maxItems
+	}
+
+	values := make(map[string]interface{})
+
+	if definition != nil {
+		for _, arg := range definition.Args {
+			if arg.DefaultValue != nil {
+				values[arg.Name()] = arg.DefaultValue
+			}
+		}
+	}
+
+	for _, arg := range field.Arguments {
+		values[arg.Name.Value] = w.value(arg.Value)
+	}
+
+	items := 1
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case int:
+			if name == "limit" && v > items {
+				items = v
+			}
+		case []interface{}:
+			if len(v) > items {
+				items = len(v)
+			}
+		}
+	}
+
+	// More is rejected by the field itself, with a better error than the
+	// cost would give.
+	if items > maxItems {
+		return maxItems
+	}
+
+	return items
+}
+
+// value is the Go value of an argument that can change the items of a
+// field, ints and lists, nil for the rest.
+func (w *walker) value(value ast.Value) interface{} {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+
+		if err != nil {
+			return math.MaxInt32
+		}
+
+		return n
+	case *ast.ListValue:
+		items := make([]interface{}, len(v.Values))
+
+		for i, item := range v.Values {
+			items[i] = w.value(item)
+		}
+
+		return items
+	case *ast.Variable:
+		switch vv := w.variables[v.Name.Value].(type) {
+		case float64:
+			if vv > math.MaxInt32 {
+				return math.MaxInt32
+			}
+
+			return int(vv)
+		case int:
+			return vv
+		case []interface{}:
+			return vv
+		}
+	}
+
+	return nil
+}
+
+// add and multiply saturate instead of overflowing, the cost of a crafted
+// query is only compared with the limit.
+func add(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+
+	return a + b
+}
+
+func multiply(a, b int) int {
+	if b != 0 && a > math.MaxInt32/b {
+		return math.MaxInt32
+	}
+
+	return a * b
+}
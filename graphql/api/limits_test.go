@@ -0,0 +1,175 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limits", func() {
+	var schema graphql.Schema
+
+	BeforeEach(func() {
+		var err error
+
+		schema, err = NewFactory(
+			&fakeLoaderStorage{},
+			clock.System,
+			nil).Schema()
+
+		Expect(err).To(BeNil())
+	})
+
+	check := func(l Limits, query string) error {
+		return l.Check(schema, query, "", nil)
+	}
+
+	Describe("Depth", func() {
+		It("returns error when exceeded", func() {
+			Expect(check(
+				Limits{Depth: 2},
+				`{ list { schedules { id } } }`)).To(
+				MatchError("query depth 3 exceeds the maximum of 2"))
+		})
+
+		It("does not return error within", func() {
+			Expect(check(
+				Limits{Depth: 3},
+				`{ list { schedules { id } } }`)).To(
+				Succeed())
+		})
+
+		It("does not count introspection", func() {
+			Expect(check(
+				Limits{Depth: 1},
+				`{ __schema { types { fields { type { name } } } } }`)).To(
+				Succeed())
+		})
+
+		It("counts fragments", func() {
+			Expect(check(
+				Limits{Depth: 1},
+				`{ ...f } fragment f on Queries { get(id: "1") { id } }`)).To(
+				MatchError("query depth 2 exceeds the maximum of 1"))
+		})
+
+		It("does not follow fragment cycles", func() {
+			Expect(check(
+				Limits{Depth: 1},
+				`{ get(id: "1") { ...a } }
+				fragment a on Schedule { id ...b }
+				fragment b on Schedule { url ...a }`)).To(
+				MatchError("query depth 2 exceeds the maximum of 1"))
+		})
+	})
+
+	Describe("Aliases", func() {
+		It("returns error when exceeded", func() {
+			Expect(check(
+				Limits{Aliases: 1},
+				`{ a: get(id: "1") { id } b: get(id: "2") { id } }`)).To(
+				MatchError("query has 2 aliases, the maximum is 1"))
+		})
+
+		It("does not return error within", func() {
+			Expect(check(
+				Limits{Aliases: 2},
+				`{ a: get(id: "1") { id } b: get(id: "2") { id } }`)).To(
+				Succeed())
+		})
+	})
+
+	Describe("Cost", func() {
+		It("returns error when exceeded", func() {
+			Expect(check(Limits{Cost: 2}, `{ get(id: "1") { id url } }`)).To(
+				MatchError("query cost 3 exceeds the maximum of 2"))
+		})
+
+		It("does not return error within", func() {
+			Expect(check(Limits{Cost: 3}, `{ get(id: "1") { id url } }`)).To(
+				Succeed())
+		})
+
+		It("counts mutations", func() {
+			Expect(check(Limits{Cost: 1}, `mutation { cancel(id: "1") }`)).To(
+				Succeed())
+		})
+
+		It("multiplies by default limit", func() {
+			Expect(check(
+				Limits{Cost: 50},
+				`{ list { schedules { id } } }`)).To(
+				MatchError("query cost 51 exceeds the maximum of 50"))
+		})
+
+		It("multiplies by limit", func() {
+			Expect(check(
+				Limits{Cost: 20},
+				`{ list(limit: 10) { schedules { id } } }`)).To(
+				MatchError("query cost 21 exceeds the maximum of 20"))
+		})
+
+		It("multiplies by limit variable", func() {
+			Expect(Limits{Cost: 8}.Check(
+				schema,
+				`query ($l: Int) { list(limit: $l) { schedules { id } } }`,
+				"",
+				map[string]interface{}{"l": float64(4)})).To(
+				MatchError("query cost 9 exceeds the maximum of 8"))
+		})
+
+		It("multiplies by ids", func() {
+			Expect(check(
+				Limits{Cost: 3},
+				`{ schedules(ids: ["1", "2", "3"]) { id } }`)).To(
+				MatchError("query cost 4 exceeds the maximum of 3"))
+		})
+
+		It("multiplies lateness by the most a field returns", func() {
+			Expect(check(
+				Limits{Cost: 200},
+				`{ lateness(dueAt: {
+					from: "2024-06-01T00:00:00Z",
+					to: "2024-06-01T01:00:00Z"
+				}) { count p99 } }`)).To(
+				MatchError("query cost 201 exceeds the maximum of 200"))
+		})
+
+		It("multiplies by at most the items a field returns", func() {
+			Expect(check(
+				Limits{Cost: 200},
+				`{ list(limit: 99999999999) { schedules { id } } }`)).To(
+				MatchError("query cost 201 exceeds the maximum of 200"))
+		})
+	})
+
+	Describe("Operation", func() {
+		query := `query A { get(id: "1") { id } }
+			query B { list { schedules { id } } }`
+
+		It("checks the named operation", func() {
+			Expect(Limits{Cost: 10}.Check(schema, query, "A", nil)).To(
+				Succeed())
+			Expect(Limits{Cost: 10}.Check(schema, query, "B", nil)).NotTo(
+				Succeed())
+		})
+
+		It("leaves ambiguous operation to execution", func() {
+			Expect(check(Limits{Cost: 1}, query)).To(Succeed())
+		})
+
+		It("leaves invalid query to execution", func() {
+			Expect(check(Limits{Cost: 1}, `{ get(`)).To(Succeed())
+		})
+	})
+
+	It("does not limit zero", func() {
+		Expect(check(
+			Limits{},
+			`{ a: list { b: schedules { c: id } } }`)).To(
+			Succeed())
+	})
+})
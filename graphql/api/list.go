@@ -1,8 +1,6 @@
 package api
 
 import (
-	"fmt"
-
 	"github.com/graphql-go/graphql"
 
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
@@ -18,7 +16,19 @@ func (f *Factory) List() *graphql.Field {
 				Type: dataRangeType,
 			},
 			"startKey": &graphql.ArgumentConfig{
-				Type: scheduleListStartKeyType,
+				Type:        scheduleListStartKeyType,
+				Description: "Deprecated: use cursor.",
+			},
+			"cursor": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			"orderBy": &graphql.ArgumentConfig{
+				Type:         scheduleListOrderType,
+				DefaultValue: storage.ListOrderDueAt,
+			},
+			"direction": &graphql.ArgumentConfig{
+				Type:         sortDirectionType,
+				DefaultValue: storage.ListDirectionDesc,
 			},
 			"limit": &graphql.ArgumentConfig{
 				Type:         graphql.Int,
@@ -29,21 +39,39 @@ func (f *Factory) List() *graphql.Field {
 			var input storage.ListInput
 
 			if err := loadStruct(p.Args, &input); err != nil {
-				return nil, fmt.Errorf("invalid input")
+				return nil, inputError("invalid input")
 			}
 
-			if input.DueAt != nil {
-				if !input.DueAt.To.After(input.DueAt.From) {
-					return nil, fmt.Errorf("dueAt to must be after dueAt from")
+			if value, ok := p.Args["cursor"].(string); ok && value != "" {
+				if input.StartKey != nil {
+					return nil, inputError("use either cursor or startKey")
+				}
+
+				key, err := DecodeCursor(input, value)
+
+				if err != nil {
+					return nil, err
+				}
+
+				input.StartKey = key
+			} else if input.StartKey != nil {
+				if err := CheckStartKey(input, input.StartKey); err != nil {
+					return nil, err
 				}
 			}
 
-			if input.Limit < 1 || input.Limit > 100 {
-				return nil, fmt.Errorf("limit must be between 1-100")
+			if err := ValidateList(&input); err != nil {
+				return nil, err
+			}
+
+			list, err := f.storage.List(p.Context, input)
+
+			if err != nil {
+				return nil, err
 			}
 
-			return f.storage.List(p.Context, input)
+			return newScheduleList(input, list)
 		},
-		Type: scheduleListType,
+		Type: f.scheduleListType,
 	}
 }
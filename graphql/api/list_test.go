@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -21,7 +23,7 @@ var _ = Describe("List", func() {
 
 	BeforeEach(func() {
 		db = fakeListStorage{}
-		factory := NewFactory(&db)
+		factory := NewFactory(&db, clock.System, nil)
 
 		field = factory.List()
 	})
@@ -40,6 +42,26 @@ var _ = Describe("List", func() {
 				Equal(scheduleListStartKeyType))
 		})
 
+		It("has orderBy as nullable ScheduleListOrder with default value",
+			func() {
+				a := field.Args["orderBy"]
+
+				Expect(a.Type).To(Equal(scheduleListOrderType))
+				Expect(a.DefaultValue).To(Equal(storage.ListOrderDueAt))
+			})
+
+		It("has direction as nullable SortDirection with default value",
+			func() {
+				a := field.Args["direction"]
+
+				Expect(a.Type).To(Equal(sortDirectionType))
+				Expect(a.DefaultValue).To(Equal(storage.ListDirectionDesc))
+			})
+
+		It("has cursor as nullable String", func() {
+			Expect(field.Args["cursor"].Type).To(Equal(graphql.String))
+		})
+
 		It("has limit as nullable Int with default value", func() {
 			a := field.Args["limit"]
 
@@ -78,14 +100,19 @@ var _ = Describe("List", func() {
 							"to":   to,
 						},
 						"startKey": map[string]interface{}{
-							"id": id,
+							"id":        id,
+							"createdAt": 1234,
 						},
-						"limit": limit,
+						"orderBy":   storage.ListOrderCreatedAt,
+						"direction": storage.ListDirectionAsc,
+						"limit":     limit,
 					},
 				})
 			})
 
 			It("sends input to db", func() {
+				Expect(db.Input.OrderBy).To(Equal(storage.ListOrderCreatedAt))
+				Expect(db.Input.Direction).To(Equal(storage.ListDirectionAsc))
 				Expect(db.Input.Status).To(Equal(status))
 				Expect(db.Input.DueAt.From.Unix()).To(Equal(from.Unix()))
 				Expect(db.Input.DueAt.To.Unix()).To(Equal(to.Unix()))
@@ -102,6 +129,95 @@ var _ = Describe("List", func() {
 			})
 		})
 
+		Describe("cursor", func() {
+			var input storage.ListInput
+
+			BeforeEach(func() {
+				input = storage.ListInput{Status: status, Limit: limit}
+
+				db.ReturnList = &storage.List{
+					NextKey: &storage.ListKey{
+						ID:     id,
+						DueAt:  aws.Int64(1234),
+						Status: aws.String(status),
+					},
+				}
+			})
+
+			It("returns cursor of next key", func() {
+				res, err := field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"status": status,
+						"limit":  limit,
+					},
+				})
+
+				Expect(err).To(BeNil())
+
+				cursor := res.(*scheduleList).Cursor
+				Expect(DecodeCursor(input, *cursor)).To(
+					Equal(db.ReturnList.NextKey))
+			})
+
+			It("sends key of cursor to db", func() {
+				cursor, _ := EncodeCursor(input, db.ReturnList.NextKey)
+
+				_, err := field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"status": status,
+						"cursor": cursor,
+						"limit":  limit,
+					},
+				})
+
+				Expect(err).To(BeNil())
+				Expect(db.Input.StartKey).To(Equal(db.ReturnList.NextKey))
+			})
+
+			It("rejects cursor of other filters", func() {
+				cursor, _ := EncodeCursor(input, db.ReturnList.NextKey)
+
+				_, err := field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"cursor": cursor,
+						"limit":  limit,
+					},
+				})
+
+				Expect(err).To(Equal(ErrCursorMismatch))
+			})
+
+			It("rejects startKey of another index", func() {
+				_, err := field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"status": status,
+						"startKey": map[string]interface{}{
+							"id":        id,
+							"createdAt": 1234,
+						},
+						"limit": limit,
+					},
+				})
+
+				Expect(err).To(Equal(ErrStartKeyMismatch))
+			})
+
+			It("rejects cursor with startKey", func() {
+				cursor, _ := EncodeCursor(input, db.ReturnList.NextKey)
+
+				_, err := field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"status":   status,
+						"cursor":   cursor,
+						"startKey": map[string]interface{}{"id": id},
+						"limit":    limit,
+					},
+				})
+
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
 		Describe("invalid input", func() {
 			Context("from is less than to of dueAt", func() {
 				var (
@@ -214,7 +330,7 @@ var _ = Describe("List", func() {
 
 	Describe("Type", func() {
 		It("returns ScheduleList", func() {
-			Expect(field.Type).To(Equal(scheduleListType))
+			Expect(field.Type.Name()).To(Equal("ScheduleList"))
 		})
 	})
 })
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+type loaderKey struct{}
+
+type loaded struct {
+	schedule *storage.Schedule
+	err      error
+}
+
+// Loader gathers the schedules the operations of a request get and loads
+// them together once each operation waits for one or is done, so a batch of
+// gets is a single GetMany. It keeps the loaded ones for the request.
+type Loader struct {
+	storage storage.Storage
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	running int
+	waiting int
+	loading bool
+	pending []string
+	loaded  map[string]*loaded
+}
+
+// NewLoader serves the given number of operations, each of them calls Done
+// once it is executed.
+func NewLoader(storage storage.Storage, operations int) *Loader {
+	l := &Loader{
+		storage: storage,
+		running: operations,
+		loaded:  map[string]*loaded{},
+	}
+
+	l.cond = sync.NewCond(&l.mutex)
+
+	return l
+}
+
+// WithLoader gives the resolvers of the context the loader.
+func WithLoader(ctx context.Context, loader *Loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func loaderFrom(ctx context.Context) *Loader {
+	if ctx == nil {
+		return nil
+	}
+
+	l, _ := ctx.Value(loaderKey{}).(*Loader)
+
+	return l
+}
+
+func (l *Loader) Done() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.running--
+	l.cond.Broadcast()
+}
+
+// load queues the ids and returns the thunk that waits for them.
+func (l *Loader) load(
+	ctx context.Context,
+	ids []string) func() ([]*storage.Schedule, error) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, id := range ids {
+		if _, found := l.loaded[id]; !found && !l.isPending(id) {
+			l.pending = append(l.pending, id)
+		}
+	}
+
+	return func() ([]*storage.Schedule, error) {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		l.waiting++
+
+		for !l.hasLoaded(ids) {
+			if l.waiting >= l.running && !l.loading {
+				l.dispatch(ctx)
+				continue
+			}
+
+			l.cond.Wait()
+		}
+
+		l.waiting--
+
+		schedules := make([]*storage.Schedule, len(ids))
+
+		for i, id := range ids {
+			if err := l.loaded[id].err; err != nil {
+				return nil, err
+			}
+
+			schedules[i] = l.loaded[id].schedule
+		}
+
+		return schedules, nil
+	}
+}
+
+// dispatch loads the pending ids without holding the lock, a single one
+// through Get.
+func (l *Loader) dispatch(ctx context.Context) {
+	ids := l.pending
+	l.pending = nil
+	l.loading = true
+	l.mutex.Unlock()
+
+	var (
+		schedules []*storage.Schedule
+		err       error
+	)
+
+	if len(ids) == 1 {
+		var s *storage.Schedule
+
+		s, err = l.storage.Get(ctx, ids[0])
+		schedules = []*storage.Schedule{s}
+	} else {
+		schedules, err = l.storage.GetMany(ctx, ids)
+	}
+
+	l.mutex.Lock()
+	l.loading = false
+
+	for i, id := range ids {
+		if err != nil {
+			l.loaded[id] = &loaded{err: err}
+		} else {
+			l.loaded[id] = &loaded{schedule: schedules[i]}
+		}
+	}
+
+	l.cond.Broadcast()
+}
+
+func (l *Loader) isPending(id string) bool {
+	for _, p := range l.pending {
+		if p == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Loader) hasLoaded(ids []string) bool {
+	for _, id := range ids {
+		if _, found := l.loaded[id]; !found {
+			return false
+		}
+	}
+
+	return true
+}
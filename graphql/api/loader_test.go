@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Loader", func() {
+	var db *fakeLoaderStorage
+
+	BeforeEach(func() {
+		db = &fakeLoaderStorage{}
+	})
+
+	It("is given through the context", func() {
+		loader := NewLoader(db, 1)
+
+		Expect(loaderFrom(WithLoader(context.TODO(), loader))).To(
+			BeIdenticalTo(loader))
+		Expect(loaderFrom(context.TODO())).To(BeNil())
+		Expect(loaderFrom(nil)).To(BeNil())
+	})
+
+	It("gets single id", func() {
+		loader := NewLoader(db, 1)
+
+		schedules, err := loader.load(context.TODO(), []string{"1"})()
+
+		Expect(err).To(BeNil())
+		Expect(schedules[0].ID).To(Equal("1"))
+		Expect(db.Gets).To(Equal([]string{"1"}))
+		Expect(db.GetManys).To(BeEmpty())
+	})
+
+	It("loads ids of every operation together", func() {
+		loader := NewLoader(db, 2)
+		first := loader.load(context.TODO(), []string{"1"})
+		second := loader.load(context.TODO(), []string{"2", "1"})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		for _, load := range []func() ([]*storage.Schedule, error){
+			first,
+			second,
+		} {
+			go func(load func() ([]*storage.Schedule, error)) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				defer loader.Done()
+
+				_, err := load()
+				Expect(err).To(BeNil())
+			}(load)
+		}
+
+		wg.Wait()
+
+		Expect(db.Gets).To(BeEmpty())
+		Expect(db.GetManys).To(Equal([][]string{{"1", "2"}}))
+	})
+
+	It("waits for running operations", func() {
+		loader := NewLoader(db, 2)
+		load := loader.load(context.TODO(), []string{"1"})
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			_, _ = load()
+		}()
+
+		Consistently(done).ShouldNot(BeClosed())
+
+		loader.Done()
+
+		Eventually(done).Should(BeClosed())
+		Expect(db.Gets).To(Equal([]string{"1"}))
+	})
+
+	It("keeps loaded schedules", func() {
+		loader := NewLoader(db, 1)
+		_, _ = loader.load(context.TODO(), []string{"1", "2"})()
+
+		schedules, err := loader.load(context.TODO(), []string{"2"})()
+
+		Expect(err).To(BeNil())
+		Expect(schedules[0].ID).To(Equal("2"))
+		Expect(db.GetManys).To(HaveLen(1))
+		Expect(db.Gets).To(BeEmpty())
+	})
+
+	It("returns storage error", func() {
+		db.Error = fmt.Errorf("get error")
+		loader := NewLoader(db, 1)
+
+		schedules, err := loader.load(context.TODO(), []string{"1", "2"})()
+
+		Expect(schedules).To(BeNil())
+		Expect(err).To(Equal(db.Error))
+	})
+})
+
+type fakeLoaderStorage struct {
+	storage.Storage
+	Gets     []string
+	GetManys [][]string
+	Error    error
+}
+
+func (srv *fakeLoaderStorage) Get(
+	_ context.Context,
+	id string) (*storage.Schedule, error) {
+
+	srv.Gets = append(srv.Gets, id)
+
+	if srv.Error != nil {
+		return nil, srv.Error
+	}
+
+	return &storage.Schedule{ID: id}, nil
+}
+
+func (srv *fakeLoaderStorage) GetMany(
+	_ context.Context,
+	ids []string) ([]*storage.Schedule, error) {
+
+	srv.GetManys = append(srv.GetManys, ids)
+
+	if srv.Error != nil {
+		return nil, srv.Error
+	}
+
+	schedules := make([]*storage.Schedule, len(ids))
+
+	for i, id := range ids {
+		schedules[i] = &storage.Schedule{ID: id}
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,28 @@
+package api
+
+import "github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+type scheduleList struct {
+	Schedules []*storage.Schedule `json:"schedules"`
+	NextKey   *storage.ListKey    `json:"nextKey"`
+	Cursor    *string             `json:"cursor"`
+}
+
+func newScheduleList(
+	input storage.ListInput,
+	list *storage.List) (*scheduleList, error) {
+
+	res := &scheduleList{Schedules: list.Schedules, NextKey: list.NextKey}
+
+	if list.NextKey != nil {
+		cursor, err := EncodeCursor(input, list.NextKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Cursor = &cursor
+	}
+
+	return res, nil
+}
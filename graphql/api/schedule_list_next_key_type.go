@@ -11,6 +11,9 @@ var scheduleListNextKeyType = graphql.NewObject(graphql.ObjectConfig{
 		"dueAt": &graphql.Field{
 			Type: graphql.Int,
 		},
+		"createdAt": &graphql.Field{
+			Type: graphql.Int,
+		},
 		"status": &graphql.Field{
 			Type: scheduleStatusType,
 		},
@@ -28,6 +28,11 @@ var _ = Describe("ScheduleListNextKey", func() {
 				Equal(graphql.Int))
 		})
 
+		It("has createdAt as nullable Int", func() {
+			Expect(scheduleListNextKeyType.Fields()["createdAt"].Type).To(
+				Equal(graphql.Int))
+		})
+
 		It("has status as nullable ScheduleStatus", func() {
 			Expect(scheduleListNextKeyType.Fields()["status"].Type).To(
 				Equal(scheduleStatusType))
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+var scheduleListOrderType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "ScheduleListOrder",
+	Values: map[string]*graphql.EnumValueConfig{
+		"DUE_AT":     {Value: storage.ListOrderDueAt},
+		"CREATED_AT": {Value: storage.ListOrderCreatedAt},
+	},
+})
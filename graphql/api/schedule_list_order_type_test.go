@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduleListOrder", func() {
+	Describe("Name", func() {
+		It("is ScheduleListOrder", func() {
+			Expect(scheduleListOrderType.Name()).To(Equal("ScheduleListOrder"))
+		})
+	})
+
+	Describe("Values", func() {
+		var (
+			create = func(s string) *graphql.EnumValueDefinition {
+				return &graphql.EnumValueDefinition{
+					Name:  s,
+					Value: s,
+				}
+			}
+			values []*graphql.EnumValueDefinition
+		)
+
+		BeforeEach(func() {
+			values = scheduleListOrderType.Values()
+		})
+
+		It("has DUE_AT", func() {
+			Expect(values).To(ContainElements(create("DUE_AT")))
+		})
+
+		It("has CREATED_AT", func() {
+			Expect(values).To(ContainElements(create("CREATED_AT")))
+		})
+	})
+})
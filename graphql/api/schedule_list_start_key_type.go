@@ -11,6 +11,9 @@ var scheduleListStartKeyType = graphql.NewInputObject(graphql.InputObjectConfig{
 		"dueAt": &graphql.InputObjectFieldConfig{
 			Type: graphql.Int,
 		},
+		"createdAt": &graphql.InputObjectFieldConfig{
+			Type: graphql.Int,
+		},
 		"status": &graphql.InputObjectFieldConfig{
 			Type: scheduleStatusType,
 		},
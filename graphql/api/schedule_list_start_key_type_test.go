@@ -28,6 +28,11 @@ var _ = Describe("ScheduleListStartKey", func() {
 				Equal(graphql.Int))
 		})
 
+		It("has createdAt as nullable Int", func() {
+			Expect(scheduleListStartKeyType.Fields()["createdAt"].Type).To(
+				Equal(graphql.Int))
+		})
+
 		It("has status as nullable ScheduleStatus", func() {
 			Expect(scheduleListStartKeyType.Fields()["status"].Type).To(
 				Equal(scheduleStatusType))
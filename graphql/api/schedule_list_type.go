@@ -2,14 +2,20 @@ package api
 
 import "github.com/graphql-go/graphql"
 
-var scheduleListType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "ScheduleList",
-	Fields: graphql.Fields{
-		"schedules": &graphql.Field{
-			Type: graphql.NewList(scheduleType),
+func newScheduleListType(schedule *graphql.Object) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "ScheduleList",
+		Fields: graphql.Fields{
+			"schedules": &graphql.Field{
+				Type: graphql.NewList(schedule),
+			},
+			"nextKey": &graphql.Field{
+				Type:              scheduleListNextKeyType,
+				DeprecationReason: "Use cursor.",
+			},
+			"cursor": &graphql.Field{
+				Type: graphql.String,
+			},
 		},
-		"nextKey": &graphql.Field{
-			Type: scheduleListNextKeyType,
-		},
-	},
-})
+	})
+}
@@ -8,6 +8,9 @@ import (
 )
 
 var _ = Describe("ScheduleList", func() {
+	scheduleType := newScheduleType(nil)
+	scheduleListType := newScheduleListType(scheduleType)
+
 	Describe("Name", func() {
 		It("is ScheduleList", func() {
 			Expect(scheduleListType.Name()).To(Equal("ScheduleList"))
@@ -26,5 +29,15 @@ var _ = Describe("ScheduleList", func() {
 			Expect(scheduleListType.Fields()["nextKey"].Type).To(
 				Equal(scheduleListNextKeyType))
 		})
+
+		It("has nextKey deprecated", func() {
+			Expect(scheduleListType.Fields()["nextKey"].DeprecationReason).NotTo(
+				BeEmpty())
+		})
+
+		It("has cursor as nullable String", func() {
+			Expect(scheduleListType.Fields()["cursor"].Type).To(
+				Equal(graphql.String))
+		})
 	})
 })
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+var scheduleReasonType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "ScheduleReason",
+	Values: map[string]*graphql.EnumValueConfig{
+		"DEFERRED":    {Value: storage.ScheduleReasonDeferred},
+		"INTERRUPTED": {Value: storage.ScheduleReasonInterrupted},
+	},
+})
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduleReason", func() {
+	Describe("Name", func() {
+		It("is ScheduleReason", func() {
+			Expect(scheduleReasonType.Name()).To(Equal("ScheduleReason"))
+		})
+	})
+
+	Describe("Values", func() {
+		var (
+			create = func(s string) *graphql.EnumValueDefinition {
+				return &graphql.EnumValueDefinition{
+					Name:  s,
+					Value: s,
+				}
+			}
+			values []*graphql.EnumValueDefinition
+		)
+
+		BeforeEach(func() {
+			values = scheduleReasonType.Values()
+		})
+
+		It("has DEFERRED", func() {
+			Expect(values).To(ContainElements(create("DEFERRED")))
+		})
+
+		It("has INTERRUPTED", func() {
+			Expect(values).To(ContainElements(create("INTERRUPTED")))
+		})
+	})
+})
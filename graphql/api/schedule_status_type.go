@@ -14,5 +14,6 @@ var scheduleStatusType = graphql.NewEnum(graphql.EnumConfig{
 		"SUCCEEDED": {Value: storage.ScheduleStatusSucceeded},
 		"CANCELED":  {Value: storage.ScheduleStatusCanceled},
 		"FAILED":    {Value: storage.ScheduleStatusFailed},
+		"EXPIRED":   {Value: storage.ScheduleStatusExpired},
 	},
 })
@@ -48,5 +48,9 @@ var _ = Describe("ScheduleStatus", func() {
 		It("has FAILED", func() {
 			Expect(values).To(ContainElements(create("FAILED")))
 		})
+
+		It("has EXPIRED", func() {
+			Expect(values).To(ContainElements(create("EXPIRED")))
+		})
 	})
 })
@@ -1,45 +1,99 @@
 package api
 
-import "github.com/graphql-go/graphql"
+import (
+	"context"
 
-var scheduleType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Schedule",
-	Fields: graphql.Fields{
-		"id": &graphql.Field{
-			Type: graphql.NewNonNull(graphql.ID),
-		},
-		"dueAt": &graphql.Field{
-			Type: graphql.NewNonNull(graphql.DateTime),
-		},
-		"url": &graphql.Field{
-			Type: graphql.NewNonNull(graphql.String),
-		},
-		"method": &graphql.Field{
-			Type: graphql.NewNonNull(httpMethodType),
-		},
-		"headers": &graphql.Field{
-			Type: stringMapType,
-		},
-		"body": &graphql.Field{
-			Type: graphql.String,
-		},
-		"status": &graphql.Field{
-			Type: graphql.NewNonNull(scheduleStatusType),
-		},
-		"startedAt": &graphql.Field{
-			Type: graphql.DateTime,
-		},
-		"completedAt": &graphql.Field{
-			Type: graphql.DateTime,
-		},
-		"canceledAt": &graphql.Field{
-			Type: graphql.DateTime,
-		},
-		"result": &graphql.Field{
-			Type: graphql.String,
-		},
-		"createdAt": &graphql.Field{
-			Type: graphql.NewNonNull(graphql.DateTime),
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+// newScheduleType loads a body or result that was offloaded to the blob
+// store only when it is asked for.
+func newScheduleType(blobs blob.Store) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Schedule",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+			},
+			"dueAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"url": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"method": &graphql.Field{
+				Type: graphql.NewNonNull(httpMethodType),
+			},
+			"headers": &graphql.Field{
+				Type: stringMapType,
+			},
+			"body": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := p.Source.(*storage.Schedule)
+
+					return loadBlob(p.Context, blobs, s.Body, s.BodyRef)
+				},
+			},
+			"status": &graphql.Field{
+				Type: graphql.NewNonNull(scheduleStatusType),
+			},
+			"startedAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"completedAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"canceledAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"result": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := p.Source.(*storage.Schedule)
+
+					return loadBlob(p.Context, blobs, s.Result, s.ResultRef)
+				},
+			},
+			"reason": &graphql.Field{
+				Type: scheduleReasonType,
+			},
+			"lag": &graphql.Field{
+				Type:        graphql.Int,
+				Description: "Milliseconds between dueAt and the actual start",
+			},
+			"deadline": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"createdAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
 		},
-	},
-})
+	})
+}
+
+func loadBlob(
+	ctx context.Context,
+	blobs blob.Store,
+	value *string,
+	ref *string) (interface{}, error) {
+
+	if ref == nil || blobs == nil {
+		if value == nil {
+			return nil, nil
+		}
+
+		return *value, nil
+	}
+
+	body, err := blobs.Get(ctx, *ref)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(body), nil
+}
@@ -1,13 +1,31 @@
 package api
 
 import (
+	"context"
+
 	"github.com/graphql-go/graphql"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Schedule", func() {
+	blobs := fakeBlobs{
+		"bodies/1234":  []byte("large body"),
+		"results/1234": []byte("large result"),
+	}
+	scheduleType := newScheduleType(blobs)
+
+	resolve := func(name string, s *storage.Schedule) (interface{}, error) {
+		return scheduleType.Fields()[name].Resolve(graphql.ResolveParams{
+			Context: context.TODO(),
+			Source:  s,
+		})
+	}
+
 	Describe("Name", func() {
 		It("is Schedule", func() {
 			Expect(scheduleType.Name()).To(Equal("Schedule"))
@@ -79,6 +97,20 @@ var _ = Describe("Schedule", func() {
 				Equal(graphql.String))
 		})
 
+		It("has reason as nullable ScheduleReason", func() {
+			Expect(scheduleType.Fields()["reason"].Type).To(
+				Equal(scheduleReasonType))
+		})
+
+		It("has lag as nullable Int", func() {
+			Expect(scheduleType.Fields()["lag"].Type).To(Equal(graphql.Int))
+		})
+
+		It("has deadline as nullable DateTime", func() {
+			Expect(scheduleType.Fields()["deadline"].Type).To(
+				Equal(graphql.DateTime))
+		})
+
 		It("has createdAt as non-nullable DateTime", func() {
 			t := scheduleType.Fields()["createdAt"].Type
 
@@ -86,4 +118,67 @@ var _ = Describe("Schedule", func() {
 			Expect(t.(*graphql.NonNull).OfType).To(Equal(graphql.DateTime))
 		})
 	})
+
+	Describe("Resolve", func() {
+		body := "small body"
+		result := "small result"
+
+		It("returns body of schedule", func() {
+			Expect(resolve("body", &storage.Schedule{Body: &body})).To(
+				Equal(body))
+		})
+
+		It("loads offloaded body", func() {
+			ref := "bodies/1234"
+
+			Expect(resolve("body", &storage.Schedule{BodyRef: &ref})).To(
+				Equal("large body"))
+		})
+
+		It("returns nil without body", func() {
+			Expect(resolve("body", &storage.Schedule{})).To(BeNil())
+		})
+
+		It("returns result of schedule", func() {
+			Expect(resolve("result", &storage.Schedule{Result: &result})).To(
+				Equal(result))
+		})
+
+		It("loads offloaded result", func() {
+			ref := "results/1234"
+
+			Expect(resolve("result", &storage.Schedule{ResultRef: &ref})).To(
+				Equal("large result"))
+		})
+
+		It("returns error of missing blob", func() {
+			ref := "results/unknown"
+
+			_, err := resolve("result", &storage.Schedule{ResultRef: &ref})
+
+			Expect(err).To(Equal(blob.ErrNotFound))
+		})
+	})
 })
+
+type fakeBlobs map[string][]byte
+
+func (fb fakeBlobs) Put(_ context.Context, key string, body []byte) error {
+	fb[key] = body
+
+	return nil
+}
+
+func (fb fakeBlobs) Get(_ context.Context, key string) ([]byte, error) {
+	if body, found := fb[key]; found {
+		return body, nil
+	}
+
+	return nil, blob.ErrNotFound
+}
+
+func (fb fakeBlobs) Delete(_ context.Context, key string) error {
+	delete(fb, key)
+
+	return nil
+}
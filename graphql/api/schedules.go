@@ -0,0 +1,41 @@
+package api
+
+import "github.com/graphql-go/graphql"
+
+// Schedules answers the schedules of the ids in the same order, null for
+// the ones that do not exist.
+func (f *Factory) Schedules() *graphql.Field {
+	return &graphql.Field{
+		Args: graphql.FieldConfigArgument{
+			"ids": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(
+					graphql.NewList(graphql.NewNonNull(graphql.ID))),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			args := p.Args["ids"].([]interface{})
+			ids := make([]string, len(args))
+
+			for i, id := range args {
+				ids[i] = id.(string)
+			}
+
+			if err := ValidateIDs(ids); err != nil {
+				return nil, err
+			}
+
+			loader := loaderFrom(p.Context)
+
+			if loader == nil {
+				return f.storage.GetMany(p.Context, ids)
+			}
+
+			load := loader.load(p.Context, ids)
+
+			return func() (interface{}, error) {
+				return load()
+			}, nil
+		},
+		Type: graphql.NewNonNull(graphql.NewList(f.scheduleType)),
+	}
+}
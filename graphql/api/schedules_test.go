@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedules", func() {
+	var (
+		field *graphql.Field
+		db    *fakeLoaderStorage
+	)
+
+	BeforeEach(func() {
+		db = &fakeLoaderStorage{}
+		factory := NewFactory(db, clock.System, nil)
+
+		field = factory.Schedules()
+	})
+
+	Describe("Args", func() {
+		It("has ids as non-nullable list of non-nullable ID", func() {
+			t := field.Args["ids"].Type
+
+			Expect(t.String()).To(Equal("[ID!]!"))
+		})
+	})
+
+	Describe("Resolve", func() {
+		Context("valid ids", func() {
+			var (
+				res interface{}
+				err error
+			)
+
+			BeforeEach(func() {
+				res, err = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"ids": []interface{}{"1", "2"},
+					},
+				})
+			})
+
+			It("sends ids to db", func() {
+				Expect(db.GetManys).To(Equal([][]string{{"1", "2"}}))
+			})
+
+			It("returns matching schedules", func() {
+				Expect(res).To(HaveLen(2))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("with loader", func() {
+			var (
+				res interface{}
+				err error
+			)
+
+			BeforeEach(func() {
+				res, err = field.Resolve(graphql.ResolveParams{
+					Context: WithLoader(context.TODO(), NewLoader(db, 1)),
+					Args: map[string]interface{}{
+						"ids": []interface{}{"1", "2"},
+					},
+				})
+			})
+
+			It("loads once resolved", func() {
+				Expect(db.GetManys).To(BeEmpty())
+
+				schedules, err := res.(func() (interface{}, error))()
+
+				Expect(schedules).To(HaveLen(2))
+				Expect(err).To(BeNil())
+				Expect(db.GetManys).To(Equal([][]string{{"1", "2"}}))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("no ids", func() {
+			var (
+				res interface{}
+				err error
+			)
+
+			BeforeEach(func() {
+				res, err = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"ids": []interface{}{},
+					},
+				})
+			})
+
+			It("does not return any schedule", func() {
+				Expect(res).To(BeNil())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Type", func() {
+		It("returns list of Schedule", func() {
+			Expect(field.Type.String()).To(Equal("[Schedule]!"))
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+var sortDirectionType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: map[string]*graphql.EnumValueConfig{
+		"DESC": {Value: storage.ListDirectionDesc},
+		"ASC":  {Value: storage.ListDirectionAsc},
+	},
+})
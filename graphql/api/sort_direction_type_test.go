@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SortDirection", func() {
+	Describe("Name", func() {
+		It("is SortDirection", func() {
+			Expect(sortDirectionType.Name()).To(Equal("SortDirection"))
+		})
+	})
+
+	Describe("Values", func() {
+		var (
+			create = func(s string) *graphql.EnumValueDefinition {
+				return &graphql.EnumValueDefinition{
+					Name:  s,
+					Value: s,
+				}
+			}
+			values []*graphql.EnumValueDefinition
+		)
+
+		BeforeEach(func() {
+			values = sortDirectionType.Values()
+		})
+
+		It("has DESC", func() {
+			Expect(values).To(ContainElements(create("DESC")))
+		})
+
+		It("has ASC", func() {
+			Expect(values).To(ContainElements(create("ASC")))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+// maxStatsRange keeps a stats read within a month of hourly counts.
+const maxStatsRange = 31 * 24 * time.Hour
+
+func (f *Factory) Stats() *graphql.Field {
+	return &graphql.Field{
+		Args: graphql.FieldConfigArgument{
+			"at": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(dataRangeType),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var input storage.StatsInput
+
+			if err := loadStruct(p.Args, &input); err != nil {
+				return nil, inputError("invalid input")
+			}
+
+			if !input.At.To.After(input.At.From) {
+				return nil, inputError("at to must be after at from")
+			}
+
+			if input.At.To.Sub(input.At.From) > maxStatsRange {
+				return nil, inputError("at must not span more than 31 days")
+			}
+
+			return f.storage.Stats(p.Context, input)
+		},
+		Type: statsType,
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats field", func() {
+	var (
+		field *graphql.Field
+		db    fakeStatsStorage
+	)
+
+	BeforeEach(func() {
+		db = fakeStatsStorage{}
+		factory := NewFactory(&db, clock.System, nil)
+
+		field = factory.Stats()
+	})
+
+	Describe("Args", func() {
+		It("has at as non-nullable DateRange", func() {
+			t := field.Args["at"].Type
+
+			Expect(t).To(BeAssignableToTypeOf(&graphql.NonNull{}))
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(dataRangeType))
+		})
+	})
+
+	Describe("Resolve", func() {
+		Describe("valid input", func() {
+			var (
+				res interface{}
+				err error
+
+				from time.Time
+				to   time.Time
+			)
+
+			BeforeEach(func() {
+				db.ReturnStats = &storage.Stats{
+					Statuses: []storage.StatusCount{
+						{Status: storage.ScheduleStatusSucceeded, Count: 3},
+					},
+				}
+
+				from = time.Now().Add(-time.Hour)
+				to = time.Now()
+
+				res, err = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"at": map[string]interface{}{
+							"from": from,
+							"to":   to,
+						},
+					},
+				})
+			})
+
+			It("sends input to db", func() {
+				Expect(db.Input.At.From.Unix()).To(Equal(from.Unix()))
+				Expect(db.Input.At.To.Unix()).To(Equal(to.Unix()))
+			})
+
+			It("returns stats", func() {
+				Expect(res).To(Equal(db.ReturnStats))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("invalid input", func() {
+			var (
+				res interface{}
+				err error
+			)
+
+			resolve := func(from, to time.Time) {
+				res, err = field.Resolve(graphql.ResolveParams{
+					Args: map[string]interface{}{
+						"at": map[string]interface{}{
+							"from": from,
+							"to":   to,
+						},
+					},
+				})
+			}
+
+			Context("at to before from", func() {
+				BeforeEach(func() {
+					resolve(time.Now(), time.Now().Add(-time.Hour))
+				})
+
+				It("does not return stats", func() {
+					Expect(res).To(BeNil())
+				})
+
+				It("returns error", func() {
+					Expect(err).To(MatchError("at to must be after at from"))
+				})
+			})
+
+			Context("at more than 31 days", func() {
+				BeforeEach(func() {
+					resolve(time.Now().Add(-32*24*time.Hour), time.Now())
+				})
+
+				It("does not return stats", func() {
+					Expect(res).To(BeNil())
+				})
+
+				It("returns error", func() {
+					Expect(err).To(
+						MatchError("at must not span more than 31 days"))
+				})
+
+				It("does not read db", func() {
+					Expect(db.Input).To(Equal(storage.StatsInput{}))
+				})
+			})
+		})
+	})
+
+	Describe("Type", func() {
+		It("returns Stats", func() {
+			Expect(field.Type).To(Equal(statsType))
+		})
+	})
+})
+
+type fakeStatsStorage struct {
+	storage.Storage
+	Input storage.StatsInput
+
+	ReturnStats *storage.Stats
+}
+
+func (srv *fakeStatsStorage) Stats(
+	_ context.Context,
+	input storage.StatsInput) (*storage.Stats, error) {
+
+	srv.Input = input
+
+	return srv.ReturnStats, nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import "github.com/graphql-go/graphql"
+
+var statusCountType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "StatusCount",
+	Fields: graphql.Fields{
+		"status": &graphql.Field{
+			Type: graphql.NewNonNull(scheduleStatusType),
+		},
+		"count": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	},
+})
+
+var hostCountType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "HostCount",
+	Fields: graphql.Fields{
+		"host": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"status": &graphql.Field{
+			Type: graphql.NewNonNull(scheduleStatusType),
+		},
+		"count": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	},
+})
+
+var bucketCountType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BucketCount",
+	Description: "Schedules reaching the status within the hour from at",
+	Fields: graphql.Fields{
+		"at": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.DateTime),
+		},
+		"status": &graphql.Field{
+			Type: graphql.NewNonNull(scheduleStatusType),
+		},
+		"count": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	},
+})
+
+var statsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Stats",
+	Description: "Schedules reaching each status, per host and per hour",
+	Fields: graphql.Fields{
+		"statuses": &graphql.Field{
+			Type: nonNullListOf(statusCountType),
+		},
+		"hosts": &graphql.Field{
+			Type: nonNullListOf(hostCountType),
+		},
+		"buckets": &graphql.Field{
+			Type: nonNullListOf(bucketCountType),
+		},
+	},
+})
+
+func nonNullListOf(t graphql.Type) graphql.Type {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"github.com/graphql-go/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	Describe("Name", func() {
+		It("is Stats", func() {
+			Expect(statsType.Name()).To(Equal("Stats"))
+		})
+	})
+
+	Describe("Fields", func() {
+		It("has statuses as non-nullable list of StatusCount", func() {
+			t := statsType.Fields()["statuses"].Type
+
+			Expect(t.String()).To(Equal("[StatusCount!]!"))
+		})
+
+		It("has hosts as non-nullable list of HostCount", func() {
+			t := statsType.Fields()["hosts"].Type
+
+			Expect(t.String()).To(Equal("[HostCount!]!"))
+		})
+
+		It("has buckets as non-nullable list of BucketCount", func() {
+			t := statsType.Fields()["buckets"].Type
+
+			Expect(t.String()).To(Equal("[BucketCount!]!"))
+		})
+	})
+
+	Describe("StatusCount", func() {
+		It("has status as non-nullable ScheduleStatus", func() {
+			t := statusCountType.Fields()["status"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(scheduleStatusType))
+		})
+
+		It("has count as non-nullable Int", func() {
+			t := statusCountType.Fields()["count"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(graphql.Int))
+		})
+	})
+
+	Describe("HostCount", func() {
+		It("has host as non-nullable String", func() {
+			t := hostCountType.Fields()["host"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(graphql.String))
+		})
+
+		It("has status as non-nullable ScheduleStatus", func() {
+			t := hostCountType.Fields()["status"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(scheduleStatusType))
+		})
+	})
+
+	Describe("BucketCount", func() {
+		It("has at as non-nullable DateTime", func() {
+			t := bucketCountType.Fields()["at"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(graphql.DateTime))
+		})
+
+		It("has status as non-nullable ScheduleStatus", func() {
+			t := bucketCountType.Fields()["status"].Type
+
+			Expect(t.(*graphql.NonNull).OfType).To(Equal(scheduleStatusType))
+		})
+	})
+})
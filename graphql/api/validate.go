@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+// ValidateCreate checks the input of a new schedule due after now, the same
+// way the create mutation does, and turns maxLateness into the deadline.
+func ValidateCreate(input *storage.CreateInput, now time.Time) error {
+	if input.DueAt.Before(now) {
+		return inputError("dueAt must be in future")
+	}
+
+	if input.URL == "" {
+		return inputError("url is required")
+	}
+
+	if _, err := url.ParseRequestURI(input.URL); err != nil {
+		return inputError("invalid url")
+	}
+
+	if !enumHas(httpMethodType, input.Method) {
+		return inputError("invalid method")
+	}
+
+	if input.Deadline != nil && input.MaxLateness != nil {
+		return inputError("only one of deadline or maxLateness is allowed")
+	}
+
+	if input.MaxLateness != nil {
+		if *input.MaxLateness < 1 {
+			return inputError("maxLateness must be positive")
+		}
+
+		deadline := input.DueAt.Add(
+			time.Duration(*input.MaxLateness) * time.Second)
+		input.Deadline = &deadline
+	}
+
+	if input.Deadline != nil && !input.Deadline.After(input.DueAt) {
+		return inputError("deadline must be after dueAt")
+	}
+
+	return nil
+}
+
+// ValidateList checks the input of a list the same way the list query does.
+func ValidateList(input *storage.ListInput) error {
+	if input.Status != "" && !enumHas(scheduleStatusType, input.Status) {
+		return inputError("invalid status")
+	}
+
+	if input.OrderBy != "" && !enumHas(scheduleListOrderType, input.OrderBy) {
+		return inputError("invalid orderBy")
+	}
+
+	if input.Direction != "" && !enumHas(sortDirectionType, input.Direction) {
+		return inputError("invalid direction")
+	}
+
+	if input.DueAt != nil {
+		if !input.DueAt.To.After(input.DueAt.From) {
+			return inputError("dueAt to must be after dueAt from")
+		}
+	}
+
+	if input.Limit < 1 || input.Limit > maxItems {
+		return inputError("limit must be between 1-100")
+	}
+
+	return nil
+}
+
+func ValidateID(id string) error {
+	if id == "" {
+		return inputError("id is required")
+	}
+
+	return nil
+}
+
+// ValidateIDs checks the ids of the schedules query.
+func ValidateIDs(ids []string) error {
+	if len(ids) < 1 || len(ids) > maxItems {
+		return inputError("ids must be between 1-100")
+	}
+
+	for _, id := range ids {
+		if err := ValidateID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadBlobs fills in the body and result of the schedule that were
+// offloaded to the blob store.
+func LoadBlobs(
+	ctx context.Context,
+	blobs blob.Store,
+	schedule *storage.Schedule) error {
+
+	body, err := loadBlob(ctx, blobs, schedule.Body, schedule.BodyRef)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := loadBlob(ctx, blobs, schedule.Result, schedule.ResultRef)
+
+	if err != nil {
+		return err
+	}
+
+	schedule.Body = stringOrNil(body)
+	schedule.Result = stringOrNil(result)
+
+	return nil
+}
+
+func stringOrNil(value interface{}) *string {
+	if s, ok := value.(string); ok {
+		return &s
+	}
+
+	return nil
+}
+
+func enumHas(enum *graphql.Enum, value string) bool {
+	for _, v := range enum.Values() {
+		if v.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	Describe("ValidateCreate", func() {
+		var input storage.CreateInput
+
+		BeforeEach(func() {
+			input = storage.CreateInput{
+				DueAt:  now.Add(time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+			}
+		})
+
+		It("accepts valid input", func() {
+			Expect(ValidateCreate(&input, now)).To(Succeed())
+		})
+
+		It("rejects past dueAt", func() {
+			input.DueAt = now.Add(-time.Second)
+
+			Expect(ValidateCreate(&input, now)).To(
+				MatchError("dueAt must be in future"))
+		})
+
+		It("rejects invalid url", func() {
+			input.URL = "foo-bar"
+
+			Expect(ValidateCreate(&input, now)).To(MatchError("invalid url"))
+		})
+
+		It("rejects unknown method", func() {
+			input.Method = "TRACE"
+
+			Expect(ValidateCreate(&input, now)).To(
+				MatchError("invalid method"))
+		})
+
+		It("turns maxLateness into deadline", func() {
+			input.MaxLateness = aws.Int64(60)
+
+			Expect(ValidateCreate(&input, now)).To(Succeed())
+			Expect(*input.Deadline).To(Equal(input.DueAt.Add(time.Minute)))
+		})
+	})
+
+	Describe("ValidateList", func() {
+		It("accepts valid input", func() {
+			Expect(ValidateList(&storage.ListInput{
+				Status: storage.ScheduleStatusIdle,
+				Limit:  25,
+			})).To(Succeed())
+		})
+
+		It("rejects unknown status", func() {
+			Expect(ValidateList(&storage.ListInput{
+				Status: "DONE",
+				Limit:  25,
+			})).To(MatchError("invalid status"))
+		})
+
+		It("rejects unknown orderBy", func() {
+			Expect(ValidateList(&storage.ListInput{
+				OrderBy: "URL",
+				Limit:   25,
+			})).To(MatchError("invalid orderBy"))
+		})
+
+		It("rejects unknown direction", func() {
+			Expect(ValidateList(&storage.ListInput{
+				Direction: "UP",
+				Limit:     25,
+			})).To(MatchError("invalid direction"))
+		})
+
+		It("rejects reversed dueAt", func() {
+			Expect(ValidateList(&storage.ListInput{
+				DueAt: &storage.DateRange{From: now, To: now},
+				Limit: 25,
+			})).To(MatchError("dueAt to must be after dueAt from"))
+		})
+
+		It("rejects limit out of range", func() {
+			Expect(ValidateList(&storage.ListInput{Limit: 101})).To(
+				MatchError("limit must be between 1-100"))
+		})
+	})
+
+	Describe("ValidateID", func() {
+		It("rejects empty id", func() {
+			Expect(ValidateID("")).To(MatchError("id is required"))
+		})
+	})
+
+	Describe("ValidateIDs", func() {
+		It("rejects no ids", func() {
+			Expect(ValidateIDs(nil)).To(MatchError("ids must be between 1-100"))
+		})
+
+		It("rejects more than 100 ids", func() {
+			Expect(ValidateIDs(make([]string, 101))).To(
+				MatchError("ids must be between 1-100"))
+		})
+
+		It("rejects empty id", func() {
+			Expect(ValidateIDs([]string{"1", ""})).To(
+				MatchError("id is required"))
+		})
+
+		It("accepts ids", func() {
+			Expect(ValidateIDs([]string{"1", "2"})).To(Succeed())
+		})
+	})
+
+	Describe("LoadBlobs", func() {
+		It("fills in offloaded body and result", func() {
+			schedule := &storage.Schedule{
+				BodyRef: aws.String("bodies/1234"),
+				Result:  aws.String("small result"),
+			}
+
+			Expect(LoadBlobs(
+				context.TODO(),
+				fakeBlobs{"bodies/1234": []byte("large body")},
+				schedule)).To(Succeed())
+
+			Expect(*schedule.Body).To(Equal("large body"))
+			Expect(*schedule.Result).To(Equal("small result"))
+		})
+	})
+})
@@ -8,6 +8,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func httpStatus(code int, w http.ResponseWriter) {
@@ -65,7 +68,7 @@ func handleGraphQL(w http.ResponseWriter, r *http.Request) {
 	body := strings.TrimSpace(string(bodyBytes))
 	ret, statusCode := executeGraphQL(r.Context(), body)
 
-	if statusCode != http.StatusOK {
+	if statusCode != http.StatusOK && ret == nil {
 		httpStatus(statusCode, w)
 		return
 	}
@@ -79,13 +82,83 @@ func handleGraphQL(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(statusCode)
 	_, _ = w.Write(buff)
 }
 
+func handleREST(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "31536000")
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		httpStatus(http.StatusBadRequest, w)
+		return
+	}
+
+	ret, statusCode := executeREST(
+		r.Context(),
+		r.Method,
+		r.URL.Path,
+		r.URL.Query(),
+		string(bodyBytes))
+
+	buff, err := marshalStruct(ret)
+
+	if err != nil {
+		httpStatus(http.StatusInternalServerError, w)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(buff)
+}
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httpStatus(http.StatusMethodNotAllowed, w)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openAPIDocument)
+}
+
+// withGRPC hands the gRPC calls, which come over cleartext HTTP/2, to the
+// gRPC server and the rest to the handler.
+func withGRPC(handler http.Handler) http.Handler {
+	return h2c.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor == 2 && strings.HasPrefix(
+				r.Header.Get("Content-Type"),
+				"application/grpc") {
+				rpcServer.ServeHTTP(w, r)
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}),
+		&http2.Server{})
+}
+
 func Http() {
 	http.HandleFunc("/graphql", handleGraphQL)
+	http.HandleFunc(schedulesPath, handleREST)
+	http.HandleFunc(schedulesPath+"/", handleREST)
+	http.HandleFunc("/openapi.json", handleOpenAPI)
 	http.HandleFunc("/", handlePlayground)
 
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", os.Getenv("PORT")), nil))
+	log.Fatal(http.ListenAndServe(
+		fmt.Sprintf(":%s", os.Getenv("PORT")),
+		withGRPC(http.DefaultServeMux)))
 }
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("withGRPC", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		Expect(Configure(&fakeStorage{}, nil, clock.System)).To(Succeed())
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/openapi.json", handleOpenAPI)
+
+		server = httptest.NewServer(withGRPC(mux))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("serves grpc", func() {
+		conn, err := grpc.Dial(
+			strings.TrimPrefix(server.URL, "http://"),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(BeNil())
+
+		defer func() { _ = conn.Close() }()
+
+		res, err := schedulerpb.NewSchedulerClient(conn).GetSchedule(
+			context.TODO(),
+			&schedulerpb.GetScheduleRequest{Id: "1234567890"})
+
+		Expect(err).To(BeNil())
+		Expect(res.GetUrl()).To(Equal("https://foo.bar/do"))
+	})
+
+	It("serves http", func() {
+		res, err := http.Get(server.URL + "/openapi.json")
+
+		Expect(err).To(BeNil())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+	})
+})
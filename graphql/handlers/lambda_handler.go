@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -60,8 +61,32 @@ func Lambda(
 		}, nil
 	}
 
-	if httpMethod != http.MethodPost ||
-		req.RawPath != fmt.Sprintf("/%s/graphql", req.RequestContext.Stage) {
+	path := strings.TrimPrefix(req.RawPath, "/"+req.RequestContext.Stage)
+
+	if isRESTPath(path) {
+		query := make(url.Values)
+
+		for name, value := range req.QueryStringParameters {
+			query.Set(name, value)
+		}
+
+		ret, statusCode := executeREST(ctx, httpMethod, path, query, req.Body)
+
+		return lambdaJSON(statusCode, ret)
+	}
+
+	if httpMethod == http.MethodGet && path == "/openapi.json" {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusOK,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin": "*",
+				"Content-Type":                "application/json;charset=utf-8",
+			},
+			Body: string(openAPIDocument),
+		}, nil
+	}
+
+	if httpMethod != http.MethodPost || path != "/graphql" {
 		return lambdaStatus(http.StatusNotFound, nil)
 	}
 
@@ -69,9 +94,20 @@ func Lambda(
 	ret, statusCode := executeGraphQL(ctx, body)
 
 	if statusCode != http.StatusOK {
+		if ret != nil {
+			return lambdaJSON(statusCode, ret)
+		}
+
 		return lambdaStatus(http.StatusBadRequest, nil)
 	}
 
+	return lambdaJSON(http.StatusOK, ret)
+}
+
+func lambdaJSON(
+	code int,
+	ret interface{}) (events.APIGatewayV2HTTPResponse, error) {
+
 	buff, err := marshalStruct(ret)
 
 	if err != nil {
@@ -79,7 +115,7 @@ func Lambda(
 	}
 
 	res := events.APIGatewayV2HTTPResponse{
-		StatusCode: http.StatusOK,
+		StatusCode: code,
 		Headers: map[string]string{
 			"Access-Control-Allow-Origin": "*",
 			"Content-Type":                "application/json;charset=utf-8",
@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 
-	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -19,9 +21,7 @@ import (
 
 var _ = Describe("Lambda", func() {
 	BeforeEach(func() {
-		f := api.NewFactory(&fakeStorage{})
-		s, _ := f.Schema()
-		schema = s
+		Expect(Configure(&fakeStorage{}, nil, clock.System)).To(Succeed())
 	})
 
 	Context("single request", func() {
@@ -168,6 +168,322 @@ var _ = Describe("Lambda", func() {
 		})
 	})
 
+	Context("multiple gets", func() {
+		var (
+			db              *fakeBatchStorage
+			gatewayResponse events.APIGatewayV2HTTPResponse
+		)
+
+		BeforeEach(func() {
+			db = &fakeBatchStorage{}
+			Expect(Configure(db, nil, clock.System)).To(Succeed())
+
+			var bodyStruct []request
+
+			for _, id := range []string{"1", "2", "3"} {
+				bodyStruct = append(bodyStruct, request{
+					Query: "query Get($id: ID!) { get(id: $id) { id } }",
+					Variables: map[string]interface{}{
+						"id": id,
+					},
+				})
+			}
+
+			bodyBuff, _ := json.Marshal(bodyStruct)
+
+			gatewayResponse, _ = Lambda(
+				context.TODO(),
+				events.APIGatewayV2HTTPRequest{
+					RawPath: "/v1/graphql",
+					Body:    string(bodyBuff),
+					RequestContext: events.APIGatewayV2HTTPRequestContext{
+						Stage: "v1",
+						HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+							Method: "POST",
+						},
+					},
+				})
+		})
+
+		It("loads them together", func() {
+			Expect(db.Calls).To(HaveLen(1))
+			Expect(db.Calls[0]).To(ConsistOf("1", "2", "3"))
+		})
+
+		It("returns each schedule", func() {
+			Expect(gatewayResponse.Body).To(Equal(
+				`[{"data":{"get":{"id":"1"}}},` +
+					`{"data":{"get":{"id":"2"}}},` +
+					`{"data":{"get":{"id":"3"}}}]`))
+		})
+	})
+
+	Context("limits", func() {
+		var (
+			db              *fakeBatchStorage
+			gatewayResponse events.APIGatewayV2HTTPResponse
+		)
+
+		send := func(body interface{}) {
+			bodyBuff, _ := json.Marshal(body)
+
+			gatewayResponse, _ = Lambda(
+				context.TODO(),
+				events.APIGatewayV2HTTPRequest{
+					RawPath: "/v1/graphql",
+					Body:    string(bodyBuff),
+					RequestContext: events.APIGatewayV2HTTPRequestContext{
+						Stage: "v1",
+						HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+							Method: "POST",
+						},
+					},
+				})
+		}
+
+		get := request{Query: `{ get(id: "1") { id } }`}
+
+		BeforeEach(func() {
+			db = &fakeBatchStorage{}
+			Expect(Configure(db, nil, clock.System)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_GRAPHQL_MAX_BATCH")
+			_ = os.Unsetenv("SCHEDULER_GRAPHQL_MAX_COST")
+		})
+
+		Context("batch too long", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_GRAPHQL_MAX_BATCH", "2")
+
+				send([]request{get, get, get})
+			})
+
+			It("returns status code Bad Request", func() {
+				Expect(gatewayResponse.StatusCode).To(
+					Equal(http.StatusBadRequest))
+			})
+
+			It("returns error", func() {
+				Expect(gatewayResponse.Body).To(ContainSubstring(
+					"batch has 3 operations, the maximum is 2"))
+				Expect(gatewayResponse.Body).To(ContainSubstring(
+					`"code":"BAD_USER_INPUT"`))
+			})
+
+			It("does not execute any operation", func() {
+				Expect(db.Calls).To(BeEmpty())
+			})
+		})
+
+		Context("query too costly", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_GRAPHQL_MAX_COST", "1")
+
+				send(get)
+			})
+
+			It("returns status code OK", func() {
+				Expect(gatewayResponse.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns error", func() {
+				Expect(gatewayResponse.Body).To(ContainSubstring(
+					"query cost 2 exceeds the maximum of 1"))
+			})
+
+			It("does not execute", func() {
+				Expect(db.Calls).To(BeEmpty())
+			})
+		})
+
+		Context("one query of batch too costly", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_GRAPHQL_MAX_COST", "3")
+
+				send([]request{
+					get,
+					{Query: `{ get(id: "2") { id url method } }`},
+				})
+			})
+
+			It("executes the others", func() {
+				Expect(db.Calls).To(Equal([][]string{{"1"}}))
+			})
+
+			It("returns error for it", func() {
+				Expect(gatewayResponse.Body).To(Equal(
+					`[{"data":{"get":{"id":"1"}}},` +
+						`{"data":null,"errors":[{"message":` +
+						`"query cost 4 exceeds the maximum of 3",` +
+						`"locations":[],` +
+						`"extensions":{"code":"BAD_USER_INPUT"}}]}]`))
+			})
+		})
+	})
+
+	Context("error codes", func() {
+		var gatewayResponse events.APIGatewayV2HTTPResponse
+
+		send := func(query string) {
+			bodyBuff, _ := json.Marshal(request{Query: query})
+
+			gatewayResponse, _ = Lambda(
+				context.TODO(),
+				events.APIGatewayV2HTTPRequest{
+					RawPath: "/v1/graphql",
+					Body:    string(bodyBuff),
+					RequestContext: events.APIGatewayV2HTTPRequestContext{
+						Stage: "v1",
+						HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+							Method: "POST",
+						},
+					},
+				})
+		}
+
+		It("returns bad user input for rejected input", func() {
+			send(`{ list(limit: 0) { schedules { id } } }`)
+
+			Expect(gatewayResponse.Body).To(ContainSubstring(
+				`"message":"limit must be between 1-100"`))
+			Expect(gatewayResponse.Body).To(ContainSubstring(
+				`"code":"BAD_USER_INPUT"`))
+		})
+
+		It("returns validation failed for invalid document", func() {
+			send(`{ get(id: "1") { unknown } }`)
+
+			Expect(gatewayResponse.Body).To(ContainSubstring(
+				`"code":"GRAPHQL_VALIDATION_FAILED"`))
+		})
+
+		It("returns no code for other errors", func() {
+			Expect(Configure(
+				&fakeBatchStorage{Error: fmt.Errorf("get error")},
+				nil,
+				clock.System)).To(Succeed())
+
+			send(`{ get(id: "1") { id } }`)
+
+			Expect(gatewayResponse.Body).To(ContainSubstring("get error"))
+			Expect(gatewayResponse.Body).NotTo(ContainSubstring(`"code"`))
+		})
+	})
+
+	Context("persisted queries", func() {
+		var (
+			db              *fakeBatchStorage
+			gatewayResponse events.APIGatewayV2HTTPResponse
+		)
+
+		const query = `{ get(id: "1") { id } }`
+
+		send := func(body string) {
+			bodyBuff, _ := json.Marshal(request{
+				Query: body,
+				Extensions: &extensions{
+					PersistedQuery: &persistedQuery{
+						Version:    1,
+						SHA256Hash: hashQuery(query),
+					},
+				},
+			})
+
+			gatewayResponse, _ = Lambda(
+				context.TODO(),
+				events.APIGatewayV2HTTPRequest{
+					RawPath: "/v1/graphql",
+					Body:    string(bodyBuff),
+					RequestContext: events.APIGatewayV2HTTPRequestContext{
+						Stage: "v1",
+						HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+							Method: "POST",
+						},
+					},
+				})
+		}
+
+		BeforeEach(func() {
+			db = &fakeBatchStorage{}
+			Expect(Configure(db, nil, clock.System)).To(Succeed())
+		})
+
+		It("asks for query of unknown hash", func() {
+			send("")
+
+			Expect(gatewayResponse.StatusCode).To(Equal(http.StatusOK))
+			Expect(gatewayResponse.Body).To(ContainSubstring(
+				`"code":"PERSISTED_QUERY_NOT_FOUND"`))
+			Expect(db.Calls).To(BeEmpty())
+		})
+
+		It("executes query of known hash", func() {
+			send(query)
+			send("")
+
+			Expect(gatewayResponse.Body).To(Equal(
+				`{"data":{"get":{"id":"1"}}}`))
+			Expect(db.Calls).To(HaveLen(2))
+		})
+
+		Context("allowlist", func() {
+			const name = "SCHEDULER_GRAPHQL_ALLOWLIST_FILE"
+
+			var dir string
+
+			BeforeEach(func() {
+				var err error
+
+				dir, err = os.MkdirTemp("", "allowlist")
+				Expect(err).To(BeNil())
+
+				file := filepath.Join(dir, "allowlist.json")
+				buff, _ := json.Marshal([]string{query})
+				Expect(os.WriteFile(file, buff, 0600)).To(Succeed())
+				_ = os.Setenv(name, file)
+
+				Expect(Configure(db, nil, clock.System)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv(name)
+				_ = os.RemoveAll(dir)
+			})
+
+			It("executes allowed hash", func() {
+				send("")
+
+				Expect(gatewayResponse.Body).To(Equal(
+					`{"data":{"get":{"id":"1"}}}`))
+			})
+
+			It("rejects other query", func() {
+				bodyBuff, _ := json.Marshal(request{
+					Query: `{ get(id: "2") { id } }`,
+				})
+
+				gatewayResponse, _ = Lambda(
+					context.TODO(),
+					events.APIGatewayV2HTTPRequest{
+						RawPath: "/v1/graphql",
+						Body:    string(bodyBuff),
+						RequestContext: events.APIGatewayV2HTTPRequestContext{
+							Stage: "v1",
+							HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+								Method: "POST",
+							},
+						},
+					})
+
+				Expect(gatewayResponse.Body).To(ContainSubstring(
+					`"code":"PERSISTED_QUERY_NOT_ALLOWED"`))
+				Expect(db.Calls).To(BeEmpty())
+			})
+		})
+	})
+
 	Context("any request", func() {
 		Context("empty body", func() {
 			var gatewayResponse events.APIGatewayV2HTTPResponse
@@ -288,3 +604,41 @@ func (srv *fakeStorage) Get(
 		Body: aws.String("{ \"foo\": \"bar\" }"),
 	}, nil
 }
+
+type fakeBatchStorage struct {
+	storage.Storage
+	Calls [][]string
+	Error error
+}
+
+func (srv *fakeBatchStorage) GetMany(
+	_ context.Context,
+	ids []string) ([]*storage.Schedule, error) {
+
+	srv.Calls = append(srv.Calls, ids)
+
+	if srv.Error != nil {
+		return nil, srv.Error
+	}
+
+	schedules := make([]*storage.Schedule, len(ids))
+
+	for i, id := range ids {
+		schedules[i] = &storage.Schedule{ID: id}
+	}
+
+	return schedules, nil
+}
+
+func (srv *fakeBatchStorage) Get(
+	_ context.Context,
+	id string) (*storage.Schedule, error) {
+
+	srv.Calls = append(srv.Calls, []string{id})
+
+	if srv.Error != nil {
+		return nil, srv.Error
+	}
+
+	return &storage.Schedule{ID: id}, nil
+}
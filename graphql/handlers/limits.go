@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+)
+
+const (
+	defaultMaxBatch   = 10
+	defaultMaxDepth   = 10
+	defaultMaxAliases = 30
+	defaultMaxCost    = 5000
+)
+
+// maxBatch is the most operations a batch array may have.
+func maxBatch() int {
+	return limit("SCHEDULER_GRAPHQL_MAX_BATCH", defaultMaxBatch)
+}
+
+func queryLimits() api.Limits {
+	return api.Limits{
+		Depth:   limit("SCHEDULER_GRAPHQL_MAX_DEPTH", defaultMaxDepth),
+		Aliases: limit("SCHEDULER_GRAPHQL_MAX_ALIASES", defaultMaxAliases),
+		Cost:    limit("SCHEDULER_GRAPHQL_MAX_COST", defaultMaxCost),
+	}
+}
+
+// limit reads a limit from the environment, zero turns it off and the
+// default stands in for a missing or invalid one.
+func limit(name string, fallback int) int {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+
+	if err != nil || n < 0 {
+		return fallback
+	}
+
+	return n
+}
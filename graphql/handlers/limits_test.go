@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"os"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("limits", func() {
+	const name = "SCHEDULER_GRAPHQL_MAX_DEPTH"
+
+	AfterEach(func() {
+		_ = os.Unsetenv(name)
+	})
+
+	It("returns defaults when not set", func() {
+		Expect(maxBatch()).To(Equal(defaultMaxBatch))
+		Expect(queryLimits()).To(Equal(api.Limits{
+			Depth:   defaultMaxDepth,
+			Aliases: defaultMaxAliases,
+			Cost:    defaultMaxCost,
+		}))
+	})
+
+	It("returns set value", func() {
+		_ = os.Setenv(name, "4")
+
+		Expect(queryLimits().Depth).To(Equal(4))
+	})
+
+	It("returns zero to turn off", func() {
+		_ = os.Setenv(name, "0")
+
+		Expect(queryLimits().Depth).To(BeZero())
+	})
+
+	It("returns default for invalid value", func() {
+		for _, value := range []string{"-1", "four"} {
+			_ = os.Setenv(name, value)
+
+			Expect(queryLimits().Depth).To(Equal(defaultMaxDepth))
+		}
+	})
+})
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+const (
+	persistedQueryVersion = 1
+
+	defaultPersistedQueries = 1000
+)
+
+type persistedQuery struct {
+	Version    int    `json:"version"`
+	SHA256Hash string `json:"sha256Hash"`
+}
+
+type extensions struct {
+	PersistedQuery *persistedQuery `json:"persistedQuery"`
+}
+
+// queryStore keeps the queries clients sent along with their hash, so they
+// can send only the hash next time.
+type queryStore interface {
+	Get(hash string) (string, bool)
+
+	Put(hash string, query string)
+}
+
+// lruQueries forgets the least recently used query once it holds size.
+type lruQueries struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruQuery struct {
+	hash  string
+	query string
+}
+
+func newLRUQueries(size int) *lruQueries {
+	return &lruQueries{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (s *lruQueries) Get(hash string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[hash]
+
+	if !ok {
+		return "", false
+	}
+
+	s.order.MoveToFront(item)
+
+	return item.Value.(*lruQuery).query, true
+}
+
+func (s *lruQueries) Put(hash string, query string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.items[hash]; ok {
+		s.order.MoveToFront(item)
+		return
+	}
+
+	s.items[hash] = s.order.PushFront(&lruQuery{hash, query})
+
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruQuery).hash)
+	}
+}
+
+// resolveQuery fills in the query of a payload that only has its hash and
+// keeps the query of one that has both. With an allowlist only the queries
+// on it are executed, whether they are sent in full or by hash.
+func resolveQuery(
+	payload request,
+	store queryStore,
+	allowlist map[string]string) (string, error) {
+
+	var pq *persistedQuery
+
+	if payload.Extensions != nil {
+		pq = payload.Extensions.PersistedQuery
+	}
+
+	if pq != nil && pq.Version != persistedQueryVersion {
+		return "", persistedQueryError(
+			"unsupported persisted query version",
+			"PERSISTED_QUERY_NOT_SUPPORTED")
+	}
+
+	if pq != nil && payload.Query != "" &&
+		hashQuery(payload.Query) != pq.SHA256Hash {
+		return "", persistedQueryError(
+			"provided sha does not match query",
+			"PERSISTED_QUERY_HASH_MISMATCH")
+	}
+
+	if allowlist != nil {
+		hash := hashQuery(payload.Query)
+
+		if pq != nil {
+			hash = pq.SHA256Hash
+		}
+
+		query, ok := allowlist[hash]
+
+		if !ok {
+			return "", persistedQueryError(
+				"query is not in the allowlist",
+				"PERSISTED_QUERY_NOT_ALLOWED")
+		}
+
+		return query, nil
+	}
+
+	if pq == nil {
+		return payload.Query, nil
+	}
+
+	if payload.Query != "" {
+		if store != nil {
+			store.Put(pq.SHA256Hash, payload.Query)
+		}
+
+		return payload.Query, nil
+	}
+
+	if store != nil {
+		if query, ok := store.Get(pq.SHA256Hash); ok {
+			return query, nil
+		}
+	}
+
+	return "", persistedQueryError(
+		"PersistedQueryNotFound",
+		"PERSISTED_QUERY_NOT_FOUND")
+}
+
+// persistedQueryError carries the code clients of the protocol look for,
+// a client sends the full query again when it is not found.
+func persistedQueryError(message string, code string) error {
+	return gqlerrors.FormattedError{
+		Message:    message,
+		Locations:  []location.SourceLocation{},
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
+
+func hashQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// createQueryStore keeps SCHEDULER_GRAPHQL_PERSISTED_QUERIES of the sent
+// queries, zero keeps none.
+func createQueryStore() queryStore {
+	size := limit(
+		"SCHEDULER_GRAPHQL_PERSISTED_QUERIES",
+		defaultPersistedQueries)
+
+	if size == 0 {
+		return nil
+	}
+
+	return newLRUQueries(size)
+}
+
+// loadAllowlist reads the JSON array of queries of the file
+// SCHEDULER_GRAPHQL_ALLOWLIST_FILE, there is no allowlist without one.
+func loadAllowlist() (map[string]string, error) {
+	name := os.Getenv("SCHEDULER_GRAPHQL_ALLOWLIST_FILE")
+
+	if name == "" {
+		return nil, nil
+	}
+
+	buff, err := os.ReadFile(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []string
+
+	if err = unmarshalStruct(buff, &queries); err != nil {
+		return nil, fmt.Errorf("allowlist %s: %w", name, err)
+	}
+
+	allowlist := make(map[string]string, len(queries))
+
+	for _, query := range queries {
+		allowlist[hashQuery(query)] = query
+	}
+
+	return allowlist, nil
+}
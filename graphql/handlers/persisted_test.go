@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Persisted queries", func() {
+	const query = `{ get(id: "1") { id } }`
+
+	hash := hashQuery(query)
+
+	withHash := func(query string, hash string) request {
+		return request{
+			Query: query,
+			Extensions: &extensions{
+				PersistedQuery: &persistedQuery{
+					Version:    persistedQueryVersion,
+					SHA256Hash: hash,
+				},
+			},
+		}
+	}
+
+	kept := func(s *lruQueries, hash string) string {
+		query, ok := s.Get(hash)
+
+		Expect(ok).To(BeTrue())
+
+		return query
+	}
+
+	code := func(err error) interface{} {
+		var fe gqlerrors.FormattedError
+
+		Expect(err).To(BeAssignableToTypeOf(fe))
+
+		return err.(gqlerrors.FormattedError).Extensions["code"]
+	}
+
+	Describe("lruQueries", func() {
+		It("does not return unknown hash", func() {
+			_, ok := newLRUQueries(2).Get(hash)
+
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns put query", func() {
+			s := newLRUQueries(2)
+			s.Put(hash, query)
+
+			Expect(kept(s, hash)).To(Equal(query))
+		})
+
+		It("forgets least recently used query", func() {
+			s := newLRUQueries(2)
+			s.Put("a", "query a")
+			s.Put("b", "query b")
+			_, _ = s.Get("a")
+			s.Put("a", "query a")
+			s.Put("c", "query c")
+
+			_, ok := s.Get("b")
+
+			Expect(ok).To(BeFalse())
+			Expect(kept(s, "a")).To(Equal("query a"))
+			Expect(kept(s, "c")).To(Equal("query c"))
+			Expect(s.order.Len()).To(Equal(2))
+		})
+	})
+
+	Describe("resolveQuery", func() {
+		var s *lruQueries
+
+		BeforeEach(func() {
+			s = newLRUQueries(10)
+		})
+
+		It("returns query without hash", func() {
+			Expect(resolveQuery(request{Query: query}, s, nil)).To(
+				Equal(query))
+		})
+
+		It("keeps query sent with hash", func() {
+			Expect(resolveQuery(withHash(query, hash), s, nil)).To(
+				Equal(query))
+			Expect(kept(s, hash)).To(Equal(query))
+		})
+
+		It("returns kept query of hash", func() {
+			s.Put(hash, query)
+
+			Expect(resolveQuery(withHash("", hash), s, nil)).To(
+				Equal(query))
+		})
+
+		It("returns not found for unknown hash", func() {
+			_, err := resolveQuery(withHash("", hash), s, nil)
+
+			Expect(err).To(MatchError("PersistedQueryNotFound"))
+			Expect(code(err)).To(Equal("PERSISTED_QUERY_NOT_FOUND"))
+		})
+
+		It("returns not found without store", func() {
+			_, err := resolveQuery(withHash("", hash), nil, nil)
+
+			Expect(err).To(MatchError("PersistedQueryNotFound"))
+		})
+
+		It("returns error for hash of other query", func() {
+			_, err := resolveQuery(withHash(query, hashQuery("{}")), s, nil)
+
+			Expect(err).To(MatchError("provided sha does not match query"))
+			Expect(code(err)).To(Equal("PERSISTED_QUERY_HASH_MISMATCH"))
+			Expect(s.order.Len()).To(BeZero())
+		})
+
+		It("returns error for other version", func() {
+			payload := withHash(query, hash)
+			payload.Extensions.PersistedQuery.Version = 2
+
+			_, err := resolveQuery(payload, s, nil)
+
+			Expect(err).To(MatchError("unsupported persisted query version"))
+			Expect(code(err)).To(Equal("PERSISTED_QUERY_NOT_SUPPORTED"))
+		})
+
+		Context("allowlist", func() {
+			allowlist := map[string]string{hash: query}
+
+			It("returns query of allowed hash", func() {
+				Expect(resolveQuery(withHash("", hash), s, allowlist)).To(
+					Equal(query))
+			})
+
+			It("returns allowed query", func() {
+				Expect(resolveQuery(request{Query: query}, s, allowlist)).To(
+					Equal(query))
+			})
+
+			It("returns error for other hash", func() {
+				s.Put(hashQuery("{}"), "{}")
+
+				_, err := resolveQuery(
+					withHash("", hashQuery("{}")),
+					s,
+					allowlist)
+
+				Expect(err).To(MatchError("query is not in the allowlist"))
+				Expect(code(err)).To(Equal("PERSISTED_QUERY_NOT_ALLOWED"))
+			})
+
+			It("returns error for other query", func() {
+				_, err := resolveQuery(request{Query: "{}"}, s, allowlist)
+
+				Expect(err).To(MatchError("query is not in the allowlist"))
+			})
+		})
+	})
+
+	Describe("loadAllowlist", func() {
+		const name = "SCHEDULER_GRAPHQL_ALLOWLIST_FILE"
+
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = os.MkdirTemp("", "allowlist")
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			_ = os.Unsetenv(name)
+			_ = os.RemoveAll(dir)
+		})
+
+		It("returns none when not set", func() {
+			Expect(loadAllowlist()).To(BeNil())
+		})
+
+		It("returns queries of file by hash", func() {
+			file := filepath.Join(dir, "allowlist.json")
+			Expect(os.WriteFile(
+				file,
+				[]byte(`["{ get(id: \"1\") { id } }"]`),
+				0600)).To(Succeed())
+			_ = os.Setenv(name, file)
+
+			Expect(loadAllowlist()).To(Equal(map[string]string{hash: query}))
+		})
+
+		It("returns error for missing file", func() {
+			_ = os.Setenv(name, filepath.Join(dir, "none"))
+
+			_, err := loadAllowlist()
+
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("returns error for invalid file", func() {
+			file := filepath.Join(dir, "allowlist.json")
+			Expect(os.WriteFile(file, []byte(`{}`), 0600)).To(Succeed())
+			_ = os.Setenv(name, file)
+
+			_, err := loadAllowlist()
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+const schedulesPath = "/schedules"
+
+type restError struct {
+	Error string `json:"error"`
+}
+
+type restList struct {
+	Schedules []*storage.Schedule `json:"schedules"`
+	Cursor    string              `json:"cursor,omitempty"`
+}
+
+// restAPI serves the schedules as plain JSON resources on top of the same
+// storage and validation as the graphql schema.
+type restAPI struct {
+	storage storage.Storage
+	clock   clock.Clock
+	blobs   blob.Store
+}
+
+var rest *restAPI
+
+func isRESTPath(path string) bool {
+	return path == schedulesPath || strings.HasPrefix(path, schedulesPath+"/")
+}
+
+// executeREST answers a request of a path under /schedules with the value
+// to write as JSON and its status code.
+func executeREST(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body string) (interface{}, int) {
+
+	if path == schedulesPath {
+		switch method {
+		case http.MethodPost:
+			return rest.create(ctx, body)
+		case http.MethodGet:
+			return rest.list(ctx, query)
+		}
+
+		return restFailure(http.StatusMethodNotAllowed, "")
+	}
+
+	id := strings.TrimPrefix(path, schedulesPath+"/")
+
+	if id == "" || strings.Contains(id, "/") {
+		return restFailure(http.StatusNotFound, "")
+	}
+
+	switch method {
+	case http.MethodGet:
+		return rest.get(ctx, id)
+	case http.MethodDelete:
+		return rest.cancel(ctx, id)
+	}
+
+	return restFailure(http.StatusMethodNotAllowed, "")
+}
+
+func (r *restAPI) create(ctx context.Context, body string) (interface{}, int) {
+	var input storage.CreateInput
+
+	if err := unmarshalStruct([]byte(body), &input); err != nil {
+		return restFailure(http.StatusBadRequest, "invalid input")
+	}
+
+	if err := api.ValidateCreate(&input, r.clock.Now()); err != nil {
+		return restFailure(http.StatusBadRequest, err.Error())
+	}
+
+	id, err := r.storage.Create(ctx, input)
+
+	if err != nil {
+		return restFailure(http.StatusInternalServerError, "")
+	}
+
+	return struct {
+		ID string `json:"id"`
+	}{id}, http.StatusCreated
+}
+
+func (r *restAPI) get(ctx context.Context, id string) (interface{}, int) {
+	schedule, err := r.storage.Get(ctx, id)
+
+	if err != nil {
+		return restFailure(http.StatusInternalServerError, "")
+	}
+
+	if schedule == nil {
+		return restFailure(http.StatusNotFound, "")
+	}
+
+	if err = api.LoadBlobs(ctx, r.blobs, schedule); err != nil {
+		return restFailure(http.StatusInternalServerError, "")
+	}
+
+	return schedule, http.StatusOK
+}
+
+func (r *restAPI) list(
+	ctx context.Context,
+	query url.Values) (interface{}, int) {
+
+	input, err := listInput(query)
+
+	if err != nil {
+		return restFailure(http.StatusBadRequest, err.Error())
+	}
+
+	if err = api.ValidateList(input); err != nil {
+		return restFailure(http.StatusBadRequest, err.Error())
+	}
+
+	list, err := r.storage.List(ctx, *input)
+
+	if err != nil {
+		return restFailure(http.StatusInternalServerError, "")
+	}
+
+	for _, schedule := range list.Schedules {
+		if err = api.LoadBlobs(ctx, r.blobs, schedule); err != nil {
+			return restFailure(http.StatusInternalServerError, "")
+		}
+	}
+
+	res := restList{Schedules: list.Schedules}
+
+	if res.Schedules == nil {
+		res.Schedules = []*storage.Schedule{}
+	}
+
+	if list.NextKey != nil {
+		res.Cursor, err = api.EncodeCursor(*input, list.NextKey)
+
+		if err != nil {
+			return restFailure(http.StatusInternalServerError, "")
+		}
+	}
+
+	return res, http.StatusOK
+}
+
+func (r *restAPI) cancel(ctx context.Context, id string) (interface{}, int) {
+	canceled, err := r.storage.Cancel(ctx, id)
+
+	if err != nil {
+		return restFailure(http.StatusInternalServerError, "")
+	}
+
+	if !canceled {
+		// Only an idle schedule can be canceled, tell a missing one apart.
+		schedule, err := r.storage.Get(ctx, id)
+
+		if err != nil {
+			return restFailure(http.StatusInternalServerError, "")
+		}
+
+		if schedule == nil {
+			return restFailure(http.StatusNotFound, "")
+		}
+
+		return restFailure(http.StatusConflict, "schedule is not idle")
+	}
+
+	return struct {
+		Canceled bool `json:"canceled"`
+	}{canceled}, http.StatusOK
+}
+
+func listInput(query url.Values) (*storage.ListInput, error) {
+	input := &storage.ListInput{
+		Status:    strings.ToUpper(query.Get("status")),
+		OrderBy:   strings.ToUpper(query.Get("orderBy")),
+		Direction: strings.ToUpper(query.Get("direction")),
+		Limit:     25,
+	}
+
+	// The order is named after the field, dueAt or createdAt.
+	switch input.OrderBy {
+	case "DUEAT":
+		input.OrderBy = storage.ListOrderDueAt
+	case "CREATEDAT":
+		input.OrderBy = storage.ListOrderCreatedAt
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.ParseInt(limit, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit")
+		}
+
+		input.Limit = value
+	}
+
+	from, to := query.Get("from"), query.Get("to")
+
+	if from != "" || to != "" {
+		var dueAt storage.DateRange
+		var err error
+
+		if dueAt.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, fmt.Errorf("invalid from")
+		}
+
+		if dueAt.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, fmt.Errorf("invalid to")
+		}
+
+		input.DueAt = &dueAt
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		key, err := api.DecodeCursor(*input, cursor)
+
+		if err != nil {
+			return nil, err
+		}
+
+		input.StartKey = key
+	}
+
+	return input, nil
+}
+
+func restFailure(code int, message string) (interface{}, int) {
+	if message == "" {
+		message = http.StatusText(code)
+	}
+
+	return restError{message}, code
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("REST", func() {
+	const table = "scheduler_v1"
+
+	var now time.Time
+
+	serve := func(method, target, body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+
+		handleREST(w, httptest.NewRequest(
+			method,
+			target,
+			strings.NewReader(body)))
+
+		var ret map[string]interface{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &ret)).To(Succeed())
+
+		return w.Code, ret
+	}
+
+	create := func(offset time.Duration) string {
+		code, ret := serve(http.MethodPost, "/schedules", `{
+			"dueAt": "`+now.Add(offset).Format(time.RFC3339)+`",
+			"url": "https://foo.bar/do",
+			"method": "POST",
+			"body": "{}"
+		}`)
+
+		Expect(code).To(Equal(http.StatusCreated))
+
+		return ret["id"].(string)
+	}
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+		now = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		clk := clock.NewFake(now)
+		dynamo := dynamotest.New(dynamotest.SchedulerTable(table))
+
+		Expect(Configure(
+			storage.NewDatabase(dynamo, clk, nil),
+			nil,
+			clk)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_TABLE_NAME")
+	})
+
+	Describe("POST /schedules", func() {
+		It("creates schedule", func() {
+			id := create(time.Hour)
+
+			code, ret := serve(http.MethodGet, "/schedules/"+id, "")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["url"]).To(Equal("https://foo.bar/do"))
+			Expect(ret["body"]).To(Equal("{}"))
+			Expect(ret["status"]).To(Equal(storage.ScheduleStatusIdle))
+		})
+
+		It("rejects input the resolver rejects", func() {
+			code, ret := serve(http.MethodPost, "/schedules", `{
+				"dueAt": "`+now.Add(-time.Hour).Format(time.RFC3339)+`",
+				"url": "https://foo.bar/do",
+				"method": "POST"
+			}`)
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("dueAt must be in future"))
+		})
+
+		It("rejects malformed input", func() {
+			code, ret := serve(http.MethodPost, "/schedules", "foo-bar")
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("invalid input"))
+		})
+	})
+
+	Describe("GET /schedules/{id}", func() {
+		It("returns not found of missing schedule", func() {
+			code, _ := serve(http.MethodGet, "/schedules/1234", "")
+
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("GET /schedules", func() {
+		It("pages through cursor", func() {
+			for i := 1; i <= 3; i++ {
+				create(time.Duration(i) * time.Hour)
+			}
+
+			code, ret := serve(
+				http.MethodGet,
+				"/schedules?status=idle&limit=2",
+				"")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["schedules"]).To(HaveLen(2))
+			Expect(ret["cursor"]).NotTo(BeEmpty())
+
+			code, ret = serve(
+				http.MethodGet,
+				"/schedules?status=idle&limit=2&cursor="+
+					ret["cursor"].(string),
+				"")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["schedules"]).To(HaveLen(1))
+			Expect(ret).NotTo(HaveKey("cursor"))
+		})
+
+		It("filters by dueAt", func() {
+			create(time.Hour)
+			create(3 * time.Hour)
+
+			code, ret := serve(
+				http.MethodGet,
+				"/schedules?from="+now.Format(time.RFC3339)+
+					"&to="+now.Add(2*time.Hour).Format(time.RFC3339),
+				"")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["schedules"]).To(HaveLen(1))
+		})
+
+		It("orders by createdAt ascending", func() {
+			create(time.Hour)
+			create(2 * time.Hour)
+
+			code, ret := serve(
+				http.MethodGet,
+				"/schedules?orderBy=createdAt&direction=asc",
+				"")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["schedules"]).To(HaveLen(2))
+		})
+
+		It("rejects unknown order", func() {
+			code, ret := serve(http.MethodGet, "/schedules?orderBy=url", "")
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("invalid orderBy"))
+		})
+
+		It("rejects invalid cursor", func() {
+			code, ret := serve(http.MethodGet, "/schedules?cursor=%25", "")
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("invalid cursor"))
+		})
+
+		It("rejects cursor of other filters", func() {
+			create(time.Hour)
+			create(2 * time.Hour)
+
+			_, ret := serve(http.MethodGet, "/schedules?status=idle&limit=1", "")
+
+			code, ret := serve(
+				http.MethodGet,
+				"/schedules?status=queued&cursor="+ret["cursor"].(string),
+				"")
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("cursor does not match the filters"))
+		})
+
+		It("rejects limit out of range", func() {
+			code, ret := serve(http.MethodGet, "/schedules?limit=500", "")
+
+			Expect(code).To(Equal(http.StatusBadRequest))
+			Expect(ret["error"]).To(Equal("limit must be between 1-100"))
+		})
+	})
+
+	Describe("DELETE /schedules/{id}", func() {
+		It("cancels idle schedule", func() {
+			id := create(time.Hour)
+
+			code, ret := serve(http.MethodDelete, "/schedules/"+id, "")
+
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(ret["canceled"]).To(BeTrue())
+
+			code, _ = serve(http.MethodDelete, "/schedules/"+id, "")
+
+			Expect(code).To(Equal(http.StatusConflict))
+		})
+
+		It("returns not found of missing schedule", func() {
+			code, _ := serve(http.MethodDelete, "/schedules/1234", "")
+
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("unsupported method", func() {
+		It("returns method not allowed", func() {
+			code, _ := serve(http.MethodPut, "/schedules", "")
+
+			Expect(code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Describe("Lambda", func() {
+		lambda := func(
+			method string,
+			path string,
+			body string) events.APIGatewayV2HTTPResponse {
+
+			res, err := Lambda(context.TODO(), events.APIGatewayV2HTTPRequest{
+				RawPath: "/v1" + path,
+				Body:    body,
+				RequestContext: events.APIGatewayV2HTTPRequestContext{
+					Stage: "v1",
+					HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+						Method: method,
+					},
+				},
+			})
+
+			Expect(err).To(BeNil())
+
+			return res
+		}
+
+		It("serves schedules", func() {
+			id := create(time.Hour)
+
+			res := lambda(http.MethodGet, "/schedules/"+id, "")
+
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Body).To(ContainSubstring(id))
+		})
+
+		It("serves openapi document", func() {
+			res := lambda(http.MethodGet, "/openapi.json", "")
+
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Body).To(ContainSubstring("/schedules/{id}"))
+		})
+	})
+})
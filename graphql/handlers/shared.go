@@ -2,25 +2,36 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
-	"log"
 	"net/http"
 	"os"
-	"path"
-	"path/filepath"
-	"runtime"
 	"strings"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-xray-sdk-go/xray"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/pages"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
 	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
 )
 
@@ -28,6 +39,7 @@ type request struct {
 	Query         string                 `json:"query"`
 	OperationName string                 `json:"operationName"`
 	Variables     map[string]interface{} `json:"variables"`
+	Extensions    *extensions            `json:"extensions,omitempty"`
 }
 
 type (
@@ -49,7 +61,13 @@ var (
 )
 
 var schema graphql.Schema
-var playgroundTemplate *template.Template
+var playgroundTemplate = template.Must(
+	template.New("playground").Parse(pages.Playground))
+var openAPIDocument = pages.OpenAPI
+var rpcServer *grpc.Server
+var store storage.Storage
+var queries queryStore
+var allowlist map[string]string
 
 func executeGraphQL(ctx context.Context, statement string) (interface{}, int) {
 	if statement == "" {
@@ -65,13 +83,11 @@ func executeGraphQL(ctx context.Context, statement string) (interface{}, int) {
 			return nil, http.StatusInternalServerError
 		}
 
-		ret = graphql.Do(graphql.Params{
-			Context:        ctx,
-			Schema:         schema,
-			RequestString:  payload.Query,
-			OperationName:  payload.OperationName,
-			VariableValues: payload.Variables,
-		})
+		loader := api.NewLoader(store, 1)
+
+		ret = execute(api.WithLoader(ctx, loader), queryLimits(), payload)
+
+		loader.Done()
 	} else if strings.HasPrefix(statement, "[") &&
 		strings.HasSuffix(statement, "]") {
 		var payloads []request
@@ -80,23 +96,31 @@ func executeGraphQL(ctx context.Context, statement string) (interface{}, int) {
 			return nil, http.StatusInternalServerError
 		}
 
+		if most := maxBatch(); most > 0 && len(payloads) > most {
+			return &graphql.Result{
+				Errors: formatErrors(&api.InputError{Message: fmt.Sprintf(
+					"batch has %d operations, the maximum is %d",
+					len(payloads),
+					most)}),
+			}, http.StatusBadRequest
+		}
+
+		limits := queryLimits()
 		rets := make([]*graphql.Result, len(payloads))
 		var wg sync.WaitGroup
 
+		// The gets of every operation share a loader, they are loaded
+		// together.
+		loader := api.NewLoader(store, len(payloads))
+		ctx = api.WithLoader(ctx, loader)
+
 		for i, p := range payloads {
 			wg.Add(1)
 			go func(payload request, index int) {
 				defer wg.Done()
+				defer loader.Done()
 
-				out := graphql.Do(graphql.Params{
-					Context:        ctx,
-					Schema:         schema,
-					RequestString:  payload.Query,
-					OperationName:  payload.OperationName,
-					VariableValues: payload.Variables,
-				})
-
-				rets[index] = out
+				rets[index] = execute(ctx, limits, payload)
 			}(p, i)
 		}
 
@@ -110,48 +134,208 @@ func executeGraphQL(ctx context.Context, statement string) (interface{}, int) {
 	return ret, http.StatusOK
 }
 
-func init() {
-	inLambda := os.Getenv("LAMBDA_TASK_ROOT") != ""
+// execute runs the operation of the payload, its query is the persisted
+// one of its hash when it has one, unless it exceeds the limits.
+func execute(
+	ctx context.Context,
+	limits api.Limits,
+	payload request) *graphql.Result {
 
-	if inLambda {
-		currentDir, _ := os.Getwd()
-		playgroundTemplate = template.Must(
-			template.ParseFiles(
-				filepath.Join(currentDir, "/pages/playground.html")))
-	} else {
-		_, currentFile, _, _ := runtime.Caller(0)
-		currentDir := path.Dir(currentFile)
+	query, err := resolveQuery(payload, queries, allowlist)
 
-		envFile := filepath.Join(currentDir, "./../.env")
+	if err != nil {
+		return &graphql.Result{Errors: formatErrors(err)}
+	}
 
-		if _, err := os.Stat(envFile); err == nil {
-			if err := godotenv.Load(envFile); err != nil {
-				log.Fatalf("env file load error: %v", err)
-				return
+	if err = limits.Check(
+		schema,
+		query,
+		payload.OperationName,
+		payload.Variables); err != nil {
+		return &graphql.Result{Errors: formatErrors(err)}
+	}
+
+	ret := graphql.Do(graphql.Params{
+		Context:        ctx,
+		Schema:         schema,
+		RequestString:  query,
+		OperationName:  payload.OperationName,
+		VariableValues: payload.Variables,
+	})
+
+	// Only an operation that never ran has no data, the root fields are
+	// nullable, so its errors are the ones of the document or variables.
+	if ret.Data == nil {
+		for i, e := range ret.Errors {
+			if e.Extensions == nil {
+				ret.Errors[i].Extensions = map[string]interface{}{
+					"code": api.CodeValidationFailed,
+				}
 			}
 		}
+	}
+
+	return ret
+}
 
-		templatePath := filepath.Join(currentDir, "./../pages/playground.html")
-		playgroundTemplate = template.Must(template.ParseFiles(templatePath))
+// formatErrors keeps the code of the errors that carry one, like the
+// execution does for the errors of the resolvers.
+func formatErrors(errs ...error) []gqlerrors.FormattedError {
+	formatted := gqlerrors.FormatErrors(errs...)
+
+	for i, err := range errs {
+		var extended gqlerrors.ExtendedError
+
+		if errors.As(err, &extended) {
+			formatted[i].Extensions = extended.Extensions()
+		}
 	}
 
-	ses := session.Must(session.NewSession())
+	return formatted
+}
 
-	ddbc := dynamodb.New(ses)
+// ConfigureEnvironment builds the storage and blob store the environment
+// picks and configures the handlers on top of them, the .env file of the
+// working directory is loaded first outside of lambda.
+func ConfigureEnvironment() error {
+	inLambda := os.Getenv("LAMBDA_TASK_ROOT") != ""
 
-	if inLambda {
-		xray.AWS(ddbc.Client)
+	if !inLambda {
+		if _, err := os.Stat(".env"); err == nil {
+			if err = godotenv.Load(".env"); err != nil {
+				return fmt.Errorf("env file load error: %w", err)
+			}
+		}
+	}
+
+	if arn := os.Getenv("SCHEDULER_CURSOR_SECRET_ARN"); inLambda && arn != "" {
+		smc := secretsmanager.New(session.Must(session.NewSession()))
+		xray.AWS(smc.Client)
+
+		if err := loadCursorSecret(smc, arn); err != nil {
+			return fmt.Errorf("cursor secret load error: %w", err)
+		}
 	}
 
-	database := storage.NewDatabase(ddbc)
+	// The instances of the lambda only page through the cursors of each
+	// other when they sign them with the same secret.
+	if inLambda && os.Getenv("SCHEDULER_CURSOR_SECRET") == "" {
+		return errors.New("SCHEDULER_CURSOR_SECRET is required in lambda")
+	}
+
+	blobs := createBlobs(inLambda)
+	database, err := createStorage(inLambda, blobs)
+
+	if err != nil {
+		return fmt.Errorf("storage create error: %w", err)
+	}
 
-	f := api.NewFactory(database)
-	s, err := f.Schema()
+	return Configure(database, blobs, clock.System)
+}
+
+// Configure rebuilds the schema on top of the given storage, blob store and
+// clock, for hosts that bring their own instead of the ones picked from the
+// environment.
+func Configure(
+	database storage.Storage,
+	blobs blob.Store,
+	clk clock.Clock) error {
+
+	s, err := api.NewFactory(database, clk, blobs).Schema()
 
 	if err != nil {
-		log.Fatalf("schema create error: %v", err)
-		return
+		return err
+	}
+
+	list, err := loadAllowlist()
+
+	if err != nil {
+		return err
 	}
 
 	schema = s
+	queries = createQueryStore()
+	allowlist = list
+	store = database
+	rest = &restAPI{database, clk, blobs}
+
+	rpcServer = grpc.NewServer()
+	schedulerpb.RegisterSchedulerServer(
+		rpcServer,
+		rpc.NewServer(database, clk, blobs))
+
+	return nil
+}
+
+// loadCursorSecret reads the cursor secret of the arn at cold start, so the
+// secret itself is not kept in the environment of the lambda. A secret that
+// is set already wins.
+func loadCursorSecret(
+	smc secretsmanageriface.SecretsManagerAPI,
+	arn string) error {
+
+	if os.Getenv("SCHEDULER_CURSOR_SECRET") != "" {
+		return nil
+	}
+
+	res, err := smc.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(arn),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return os.Setenv(
+		"SCHEDULER_CURSOR_SECRET",
+		aws.StringValue(res.SecretString))
+}
+
+// createBlobs picks the store offloaded bodies and results are kept in, none
+// keeps them in the item.
+func createBlobs(inLambda bool) blob.Store {
+	if bucket := os.Getenv("SCHEDULER_BLOB_BUCKET"); bucket != "" {
+		s3c := s3.New(session.Must(session.NewSession()))
+
+		if inLambda {
+			xray.AWS(s3c.Client)
+		}
+
+		return blob.NewS3Store(s3c, bucket)
+	}
+
+	if dir := os.Getenv("SCHEDULER_BLOB_DIR"); dir != "" {
+		return blob.NewFileStore(dir)
+	}
+
+	return nil
+}
+
+func createStorage(
+	inLambda bool,
+	blobs blob.Store) (storage.Storage, error) {
+
+	if os.Getenv("SCHEDULER_STORAGE") == "postgres" {
+		db, err := sql.Open("postgres", os.Getenv("SCHEDULER_DATABASE_URL"))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = storage.Migrate(context.Background(), db); err != nil {
+			return nil, err
+		}
+
+		return storage.NewPostgres(db, clock.System), nil
+	}
+
+	ses := session.Must(session.NewSession())
+
+	ddbc := dynamodb.New(ses)
+
+	if inLambda {
+		xray.AWS(ddbc.Client)
+	}
+
+	return storage.NewDatabase(ddbc, clock.System, blobs), nil
 }
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigureEnvironment", func() {
+	AfterEach(func() {
+		_ = os.Unsetenv("LAMBDA_TASK_ROOT")
+		_ = os.Unsetenv("SCHEDULER_CURSOR_SECRET")
+	})
+
+	It("requires cursor secret in lambda", func() {
+		_ = os.Setenv("LAMBDA_TASK_ROOT", "/var/task")
+		_ = os.Unsetenv("SCHEDULER_CURSOR_SECRET")
+
+		Expect(ConfigureEnvironment()).To(MatchError(
+			"SCHEDULER_CURSOR_SECRET is required in lambda"))
+	})
+})
+
+var _ = Describe("loadCursorSecret", func() {
+	const arn = "arn:aws:secretsmanager:ap-south-1:1234:secret:cursor"
+
+	var smc *fakeSecretsManager
+
+	BeforeEach(func() {
+		_ = os.Unsetenv("SCHEDULER_CURSOR_SECRET")
+		smc = &fakeSecretsManager{Secret: "from-secrets-manager"}
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_CURSOR_SECRET")
+	})
+
+	It("sets secret of arn", func() {
+		Expect(loadCursorSecret(smc, arn)).To(Succeed())
+
+		Expect(smc.SecretID).To(Equal(arn))
+		Expect(os.Getenv("SCHEDULER_CURSOR_SECRET")).To(
+			Equal("from-secrets-manager"))
+	})
+
+	It("keeps secret that is set", func() {
+		_ = os.Setenv("SCHEDULER_CURSOR_SECRET", "from-environment")
+
+		Expect(loadCursorSecret(smc, arn)).To(Succeed())
+
+		Expect(smc.SecretID).To(BeEmpty())
+		Expect(os.Getenv("SCHEDULER_CURSOR_SECRET")).To(
+			Equal("from-environment"))
+	})
+
+	It("returns error of secrets manager", func() {
+		smc.Error = errors.New("access denied")
+
+		Expect(loadCursorSecret(smc, arn)).To(MatchError("access denied"))
+		Expect(os.Getenv("SCHEDULER_CURSOR_SECRET")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pages", func() {
+	It("serves embedded playground", func() {
+		w := httptest.NewRecorder()
+
+		handlePlayground(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(ContainSubstring(`"/graphql"`))
+	})
+
+	It("serves embedded openapi document", func() {
+		w := httptest.NewRecorder()
+
+		handleOpenAPI(
+			w,
+			httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(ContainSubstring(`"openapi"`))
+	})
+})
+
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	Secret   string
+	Error    error
+	SecretID string
+}
+
+func (fs *fakeSecretsManager) GetSecretValue(
+	input *secretsmanager.GetSecretValueInput) (
+	*secretsmanager.GetSecretValueOutput, error) {
+
+	fs.SecretID = aws.StringValue(input.SecretId)
+
+	if fs.Error != nil {
+		return nil, fs.Error
+	}
+
+	return &secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(fs.Secret),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"os"
 
 	"github.com/aws/aws-lambda-go/lambda"
@@ -9,6 +10,10 @@ import (
 )
 
 func main() {
+	if err := handlers.ConfigureEnvironment(); err != nil {
+		log.Fatalf("configure error: %v", err)
+	}
+
 	if os.Getenv("LAMBDA_TASK_ROOT") != "" {
 		lambda.Start(handlers.Lambda)
 	} else {
@@ -0,0 +1,11 @@
+// Package pages embeds the documents the http server serves, so the binary
+// does not look for them next to its sources or in the working directory.
+package pages
+
+import _ "embed"
+
+//go:embed playground.html
+var Playground string
+
+//go:embed openapi.json
+var OpenAPI []byte
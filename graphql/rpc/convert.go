@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+const (
+	methodPrefix    = "HTTP_METHOD_"
+	statusPrefix    = "SCHEDULE_STATUS_"
+	reasonPrefix    = "SCHEDULE_REASON_"
+	orderPrefix     = "LIST_ORDER_"
+	directionPrefix = "SORT_DIRECTION_"
+)
+
+func toSchedule(s *storage.Schedule) *schedulerpb.Schedule {
+	method := schedulerpb.HTTPMethod_value[methodPrefix+s.Method]
+	status := schedulerpb.ScheduleStatus_value[statusPrefix+s.Status]
+	reason := int32(0)
+
+	if s.Reason != nil {
+		reason = schedulerpb.ScheduleReason_value[reasonPrefix+*s.Reason]
+	}
+
+	return &schedulerpb.Schedule{
+		Id:          s.ID,
+		DueAt:       timestamppb.New(s.DueAt),
+		Url:         s.URL,
+		Method:      schedulerpb.HTTPMethod(method),
+		Headers:     s.Headers,
+		Body:        s.Body,
+		Status:      schedulerpb.ScheduleStatus(status),
+		StartedAt:   timestampOrNil(s.StartedAt),
+		CompletedAt: timestampOrNil(s.CompletedAt),
+		CanceledAt:  timestampOrNil(s.CanceledAt),
+		Result:      s.Result,
+		Reason:      schedulerpb.ScheduleReason(reason),
+		Lag:         s.Lag,
+		Deadline:    timestampOrNil(s.Deadline),
+		CreatedAt:   timestamppb.New(s.CreatedAt),
+	}
+}
+
+// methodName, statusName, orderName and directionName turn an enum into the
+// value the storage keeps, unspecified is the empty string.
+func methodName(method schedulerpb.HTTPMethod) string {
+	return enumName(method.String(), methodPrefix)
+}
+
+func statusName(status schedulerpb.ScheduleStatus) string {
+	return enumName(status.String(), statusPrefix)
+}
+
+func orderName(order schedulerpb.ListOrder) string {
+	return enumName(order.String(), orderPrefix)
+}
+
+func directionName(direction schedulerpb.SortDirection) string {
+	return enumName(direction.String(), directionPrefix)
+}
+
+func enumName(name string, prefix string) string {
+	name = strings.TrimPrefix(name, prefix)
+
+	if name == "UNSPECIFIED" {
+		return ""
+	}
+
+	return name
+}
+
+// openFrom and openTo stand for a missing bound of a range, before and
+// after any time a schedule is due at.
+var (
+	openFrom = time.Unix(0, 0).UTC()
+	openTo   = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// dateRange is nil without either bound, a missing one is open.
+func dateRange(from, to *timestamppb.Timestamp) *storage.DateRange {
+	if from == nil && to == nil {
+		return nil
+	}
+
+	r := storage.DateRange{From: openFrom, To: openTo}
+
+	if from != nil {
+		r.From = from.AsTime()
+	}
+
+	if to != nil {
+		r.To = to.AsTime()
+	}
+
+	return &r
+}
+
+func timeOrNil(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+
+	return &t
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Convert", func() {
+	Describe("toSchedule", func() {
+		It("maps storage values to enums", func() {
+			s := toSchedule(&storage.Schedule{
+				ID:        "1234",
+				Method:    "PATCH",
+				Status:    storage.ScheduleStatusFailed,
+				Reason:    aws.String(storage.ScheduleReasonInterrupted),
+				CreatedAt: time.Unix(1600000000, 0),
+			})
+
+			Expect(s.GetMethod()).To(
+				Equal(schedulerpb.HTTPMethod_HTTP_METHOD_PATCH))
+			Expect(s.GetStatus()).To(
+				Equal(schedulerpb.ScheduleStatus_SCHEDULE_STATUS_FAILED))
+			Expect(s.GetReason()).To(Equal(
+				schedulerpb.ScheduleReason_SCHEDULE_REASON_INTERRUPTED))
+			Expect(s.GetCreatedAt().AsTime().Unix()).To(
+				BeEquivalentTo(1600000000))
+			Expect(s.GetStartedAt()).To(BeNil())
+		})
+	})
+
+	Describe("statusName", func() {
+		It("strips enum prefix", func() {
+			Expect(statusName(
+				schedulerpb.ScheduleStatus_SCHEDULE_STATUS_QUEUED)).To(
+				Equal(storage.ScheduleStatusQueued))
+		})
+
+		It("leaves unspecified empty", func() {
+			Expect(statusName(
+				schedulerpb.ScheduleStatus_SCHEDULE_STATUS_UNSPECIFIED)).To(
+				BeEmpty())
+		})
+	})
+
+	Describe("orderName", func() {
+		It("strips enum prefix", func() {
+			Expect(orderName(
+				schedulerpb.ListOrder_LIST_ORDER_CREATED_AT)).To(
+				Equal(storage.ListOrderCreatedAt))
+		})
+	})
+
+	Describe("dateRange", func() {
+		from := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		to := from.Add(time.Hour)
+
+		It("returns nil without bounds", func() {
+			Expect(dateRange(nil, nil)).To(BeNil())
+		})
+
+		It("keeps both bounds", func() {
+			Expect(dateRange(timestamppb.New(from), timestamppb.New(to))).To(
+				Equal(&storage.DateRange{From: from, To: to}))
+		})
+
+		It("opens missing from", func() {
+			Expect(dateRange(nil, timestamppb.New(to))).To(
+				Equal(&storage.DateRange{From: openFrom, To: to}))
+		})
+
+		It("opens missing to", func() {
+			Expect(dateRange(timestamppb.New(from), nil)).To(
+				Equal(&storage.DateRange{From: from, To: openTo}))
+		})
+	})
+
+	Describe("directionName", func() {
+		It("strips enum prefix", func() {
+			Expect(directionName(
+				schedulerpb.SortDirection_SORT_DIRECTION_ASC)).To(
+				Equal(storage.ListDirectionAsc))
+		})
+	})
+})
@@ -0,0 +1,285 @@
+// Package rpc serves the schedules over gRPC with the same storage and
+// validation as the graphql schema.
+package rpc
+
+//go:generate protoc -I schedulerpb --go_out=schedulerpb --go_opt=paths=source_relative --go-grpc_out=schedulerpb --go-grpc_opt=paths=source_relative scheduler.proto
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/api"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+const (
+	defaultLimit         = 25
+	defaultWatchInterval = 2 * time.Second
+)
+
+type Server struct {
+	schedulerpb.UnimplementedSchedulerServer
+
+	storage storage.Storage
+	clock   clock.Clock
+	blobs   blob.Store
+}
+
+func NewServer(
+	storage storage.Storage,
+	clock clock.Clock,
+	blobs blob.Store) *Server {
+
+	return &Server{storage: storage, clock: clock, blobs: blobs}
+}
+
+func (srv *Server) CreateSchedule(
+	ctx context.Context,
+	req *schedulerpb.CreateScheduleRequest) (
+	*schedulerpb.CreateScheduleResponse, error) {
+
+	if req.GetDueAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "dueAt is required")
+	}
+
+	input := storage.CreateInput{
+		DueAt:       req.GetDueAt().AsTime(),
+		URL:         req.GetUrl(),
+		Method:      methodName(req.GetMethod()),
+		Headers:     req.GetHeaders(),
+		Body:        req.GetBody(),
+		Deadline:    timeOrNil(req.GetDeadline()),
+		MaxLateness: req.MaxLateness,
+	}
+
+	if err := api.ValidateCreate(&input, srv.clock.Now()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id, err := srv.storage.Create(ctx, input)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &schedulerpb.CreateScheduleResponse{Id: id}, nil
+}
+
+func (srv *Server) GetSchedule(
+	ctx context.Context,
+	req *schedulerpb.GetScheduleRequest) (*schedulerpb.Schedule, error) {
+
+	schedule, err := srv.get(ctx, req.GetId())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toSchedule(schedule), nil
+}
+
+func (srv *Server) ListSchedules(
+	ctx context.Context,
+	req *schedulerpb.ListSchedulesRequest) (
+	*schedulerpb.ListSchedulesResponse, error) {
+
+	input := storage.ListInput{
+		Status:    statusName(req.GetStatus()),
+		OrderBy:   orderName(req.GetOrderBy()),
+		DueAt:     dateRange(req.GetDueAtFrom(), req.GetDueAtTo()),
+		Direction: directionName(req.GetDirection()),
+		Limit:     req.GetLimit(),
+	}
+
+	if input.Limit == 0 {
+		input.Limit = defaultLimit
+	}
+
+	if req.GetCursor() != "" {
+		key, err := api.DecodeCursor(input, req.GetCursor())
+
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		input.StartKey = key
+	}
+
+	if err := api.ValidateList(&input); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	list, err := srv.storage.List(ctx, input)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &schedulerpb.ListSchedulesResponse{}
+
+	for _, schedule := range list.Schedules {
+		if err = api.LoadBlobs(ctx, srv.blobs, schedule); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		res.Schedules = append(res.Schedules, toSchedule(schedule))
+	}
+
+	if list.NextKey != nil {
+		if res.Cursor, err = api.EncodeCursor(input, list.NextKey); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return res, nil
+}
+
+func (srv *Server) CancelSchedule(
+	ctx context.Context,
+	req *schedulerpb.CancelScheduleRequest) (
+	*schedulerpb.CancelScheduleResponse, error) {
+
+	if err := api.ValidateID(req.GetId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	canceled, err := srv.storage.Cancel(ctx, req.GetId())
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if !canceled {
+		// Only an idle schedule can be canceled, tell a missing one apart.
+		if _, err = srv.get(ctx, req.GetId()); err != nil {
+			return nil, err
+		}
+
+		return nil, status.Error(
+			codes.FailedPrecondition,
+			"schedule is not idle")
+	}
+
+	return &schedulerpb.CancelScheduleResponse{Canceled: true}, nil
+}
+
+func (srv *Server) WatchSchedule(
+	req *schedulerpb.WatchScheduleRequest,
+	stream schedulerpb.Scheduler_WatchScheduleServer) error {
+
+	ctx := stream.Context()
+	interval := watchInterval()
+	last := ""
+
+	for {
+		// The blobs are only loaded for the schedules that are sent, not on
+		// every poll.
+		schedule, err := srv.find(ctx, req.GetId())
+
+		if err != nil {
+			return err
+		}
+
+		if schedule.Status != last {
+			if err = srv.loadBlobs(ctx, schedule); err != nil {
+				return err
+			}
+
+			if err = stream.Send(toSchedule(schedule)); err != nil {
+				return err
+			}
+
+			last = schedule.Status
+		}
+
+		if terminal(schedule.Status) {
+			return nil
+		}
+
+		srv.clock.Wait(ctx, interval)
+
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+func (srv *Server) get(
+	ctx context.Context,
+	id string) (*storage.Schedule, error) {
+
+	schedule, err := srv.find(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = srv.loadBlobs(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (srv *Server) find(
+	ctx context.Context,
+	id string) (*storage.Schedule, error) {
+
+	if err := api.ValidateID(id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	schedule, err := srv.storage.Get(ctx, id)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if schedule == nil {
+		return nil, status.Error(codes.NotFound, "schedule not found")
+	}
+
+	return schedule, nil
+}
+
+func (srv *Server) loadBlobs(
+	ctx context.Context,
+	schedule *storage.Schedule) error {
+
+	if err := api.LoadBlobs(ctx, srv.blobs, schedule); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+func terminal(status string) bool {
+	switch status {
+	case storage.ScheduleStatusSucceeded,
+		storage.ScheduleStatusFailed,
+		storage.ScheduleStatusExpired,
+		storage.ScheduleStatusCanceled:
+		return true
+	}
+
+	return false
+}
+
+func watchInterval() time.Duration {
+	seconds, err := strconv.Atoi(
+		os.Getenv("SCHEDULER_WATCH_INTERVAL_SECONDS"))
+
+	if err != nil || seconds < 1 {
+		return defaultWatchInterval
+	}
+
+	return time.Duration(seconds) * time.Second
+}
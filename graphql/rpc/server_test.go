@@ -0,0 +1,377 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/rpc/schedulerpb"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	const table = "scheduler_v1"
+
+	var (
+		clk      *clock.Fake
+		db       *storage.Database
+		server   *grpc.Server
+		conn     *grpc.ClientConn
+		client   schedulerpb.SchedulerClient
+		now      time.Time
+		createAt func(time.Duration) string
+	)
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+		now = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		clk = clock.NewFake(now)
+		db = storage.NewDatabase(
+			dynamotest.New(dynamotest.SchedulerTable(table)),
+			clk,
+			nil)
+
+		listener := bufconn.Listen(1024 * 1024)
+		server = grpc.NewServer()
+		schedulerpb.RegisterSchedulerServer(server, NewServer(db, clk, nil))
+
+		go func() { _ = server.Serve(listener) }()
+
+		var err error
+
+		conn, err = grpc.Dial(
+			"bufnet",
+			grpc.WithContextDialer(
+				func(ctx context.Context, _ string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).To(BeNil())
+
+		client = schedulerpb.NewSchedulerClient(conn)
+
+		createAt = func(offset time.Duration) string {
+			res, err := client.CreateSchedule(
+				context.TODO(),
+				&schedulerpb.CreateScheduleRequest{
+					DueAt:  timestamppb.New(now.Add(offset)),
+					Url:    "https://foo.bar/do",
+					Method: schedulerpb.HTTPMethod_HTTP_METHOD_POST,
+					Body:   "{}",
+				})
+			Expect(err).To(BeNil())
+
+			return res.GetId()
+		}
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+		server.Stop()
+		_ = os.Unsetenv("SCHEDULER_TABLE_NAME")
+	})
+
+	Describe("CreateSchedule", func() {
+		It("creates schedule", func() {
+			id := createAt(time.Hour)
+
+			res, err := client.GetSchedule(
+				context.TODO(),
+				&schedulerpb.GetScheduleRequest{Id: id})
+
+			Expect(err).To(BeNil())
+			Expect(res.GetDueAt().AsTime()).To(Equal(now.Add(time.Hour)))
+			Expect(res.GetMethod()).To(
+				Equal(schedulerpb.HTTPMethod_HTTP_METHOD_POST))
+			Expect(res.GetBody()).To(Equal("{}"))
+			Expect(res.GetStatus()).To(
+				Equal(schedulerpb.ScheduleStatus_SCHEDULE_STATUS_IDLE))
+		})
+
+		It("rejects input the resolver rejects", func() {
+			_, err := client.CreateSchedule(
+				context.TODO(),
+				&schedulerpb.CreateScheduleRequest{
+					DueAt:       timestamppb.New(now.Add(time.Hour)),
+					Url:         "https://foo.bar/do",
+					Method:      schedulerpb.HTTPMethod_HTTP_METHOD_GET,
+					MaxLateness: aws.Int64(0),
+				})
+
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(status.Convert(err).Message()).To(
+				Equal("maxLateness must be positive"))
+		})
+
+		It("rejects unspecified method", func() {
+			_, err := client.CreateSchedule(
+				context.TODO(),
+				&schedulerpb.CreateScheduleRequest{
+					DueAt: timestamppb.New(now.Add(time.Hour)),
+					Url:   "https://foo.bar/do",
+				})
+
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("GetSchedule", func() {
+		It("returns not found of missing schedule", func() {
+			_, err := client.GetSchedule(
+				context.TODO(),
+				&schedulerpb.GetScheduleRequest{Id: "1234"})
+
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("ListSchedules", func() {
+		It("pages through cursor", func() {
+			for i := 1; i <= 3; i++ {
+				createAt(time.Duration(i) * time.Hour)
+			}
+
+			req := &schedulerpb.ListSchedulesRequest{
+				Status: schedulerpb.ScheduleStatus_SCHEDULE_STATUS_IDLE,
+				Limit:  2,
+			}
+
+			res, err := client.ListSchedules(context.TODO(), req)
+
+			Expect(err).To(BeNil())
+			Expect(res.GetSchedules()).To(HaveLen(2))
+			Expect(res.GetCursor()).NotTo(BeEmpty())
+
+			req.Cursor = res.GetCursor()
+			res, err = client.ListSchedules(context.TODO(), req)
+
+			Expect(err).To(BeNil())
+			Expect(res.GetSchedules()).To(HaveLen(1))
+			Expect(res.GetCursor()).To(BeEmpty())
+		})
+
+		It("lists earliest due first when ascending", func() {
+			first := createAt(time.Hour)
+			second := createAt(2 * time.Hour)
+
+			res, err := client.ListSchedules(
+				context.TODO(),
+				&schedulerpb.ListSchedulesRequest{
+					OrderBy:   schedulerpb.ListOrder_LIST_ORDER_DUE_AT,
+					Direction: schedulerpb.SortDirection_SORT_DIRECTION_ASC,
+				})
+
+			Expect(err).To(BeNil())
+			Expect(res.GetSchedules()).To(HaveLen(2))
+			Expect(res.GetSchedules()[0].GetId()).To(Equal(first))
+			Expect(res.GetSchedules()[1].GetId()).To(Equal(second))
+		})
+
+		It("leaves missing bound of due range open", func() {
+			createAt(time.Hour)
+			later := createAt(2 * time.Hour)
+
+			res, err := client.ListSchedules(
+				context.TODO(),
+				&schedulerpb.ListSchedulesRequest{
+					DueAtFrom: timestamppb.New(now.Add(90 * time.Minute)),
+				})
+
+			Expect(err).To(BeNil())
+			Expect(res.GetSchedules()).To(HaveLen(1))
+			Expect(res.GetSchedules()[0].GetId()).To(Equal(later))
+		})
+
+		It("rejects cursor of other filters", func() {
+			createAt(time.Hour)
+			createAt(2 * time.Hour)
+
+			req := &schedulerpb.ListSchedulesRequest{
+				Status: schedulerpb.ScheduleStatus_SCHEDULE_STATUS_IDLE,
+				Limit:  1,
+			}
+
+			res, err := client.ListSchedules(context.TODO(), req)
+			Expect(err).To(BeNil())
+
+			req.Status = schedulerpb.ScheduleStatus_SCHEDULE_STATUS_UNSPECIFIED
+			req.Cursor = res.GetCursor()
+			_, err = client.ListSchedules(context.TODO(), req)
+
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("rejects limit out of range", func() {
+			_, err := client.ListSchedules(
+				context.TODO(),
+				&schedulerpb.ListSchedulesRequest{Limit: 101})
+
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("CancelSchedule", func() {
+		It("cancels idle schedule once", func() {
+			id := createAt(time.Hour)
+
+			res, err := client.CancelSchedule(
+				context.TODO(),
+				&schedulerpb.CancelScheduleRequest{Id: id})
+
+			Expect(err).To(BeNil())
+			Expect(res.GetCanceled()).To(BeTrue())
+
+			_, err = client.CancelSchedule(
+				context.TODO(),
+				&schedulerpb.CancelScheduleRequest{Id: id})
+
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
+
+		It("returns not found of missing schedule", func() {
+			_, err := client.CancelSchedule(
+				context.TODO(),
+				&schedulerpb.CancelScheduleRequest{Id: "1234"})
+
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("WatchSchedule", func() {
+		It("streams status changes till completion", func() {
+			id := createAt(time.Hour)
+
+			stream, err := client.WatchSchedule(
+				context.TODO(),
+				&schedulerpb.WatchScheduleRequest{Id: id})
+			Expect(err).To(BeNil())
+
+			first, err := stream.Recv()
+
+			Expect(err).To(BeNil())
+			Expect(first.GetStatus()).To(
+				Equal(schedulerpb.ScheduleStatus_SCHEDULE_STATUS_IDLE))
+
+			Eventually(clk.Waiters).Should(Equal(1))
+
+			canceled, err := db.Cancel(context.TODO(), id)
+			Expect(err).To(BeNil())
+			Expect(canceled).To(BeTrue())
+
+			clk.Advance(defaultWatchInterval)
+
+			last, err := stream.Recv()
+
+			Expect(err).To(BeNil())
+			Expect(last.GetStatus()).To(
+				Equal(schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELED))
+			Expect(last.GetCanceledAt()).NotTo(BeNil())
+
+			_, err = stream.Recv()
+
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("loads blobs only of sent schedules", func() {
+			_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "8")
+			defer func() { _ = os.Unsetenv("SCHEDULER_BLOB_THRESHOLD_BYTES") }()
+
+			dir, err := os.MkdirTemp("", "blobs")
+			Expect(err).To(BeNil())
+
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			blobs := &countingBlobs{Store: blob.NewFileStore(dir)}
+			db = storage.NewDatabase(
+				dynamotest.New(dynamotest.SchedulerTable(table)),
+				clk,
+				blobs)
+
+			id, err := db.Create(context.TODO(), storage.CreateInput{
+				DueAt:  now.Add(time.Hour),
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+				Body:   `{"large": true}`,
+			})
+			Expect(err).To(BeNil())
+
+			stream := &fakeWatchStream{
+				ctx:       context.TODO(),
+				schedules: make(chan *schedulerpb.Schedule, 2),
+			}
+			done := make(chan error, 1)
+
+			go func() {
+				done <- NewServer(db, clk, blobs).WatchSchedule(
+					&schedulerpb.WatchScheduleRequest{Id: id},
+					stream)
+			}()
+
+			Expect((<-stream.schedules).GetBody()).To(Equal(`{"large": true}`))
+
+			for i := 0; i < 2; i++ {
+				Eventually(clk.Waiters).Should(Equal(1))
+				clk.Advance(defaultWatchInterval)
+			}
+
+			Eventually(clk.Waiters).Should(Equal(1))
+
+			_, err = db.Cancel(context.TODO(), id)
+			Expect(err).To(BeNil())
+
+			clk.Advance(defaultWatchInterval)
+
+			Expect(<-done).To(Succeed())
+			Expect((<-stream.schedules).GetStatus()).To(
+				Equal(schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELED))
+			Expect(blobs.Gets).To(Equal(2))
+		})
+	})
+})
+
+type fakeWatchStream struct {
+	schedulerpb.Scheduler_WatchScheduleServer
+
+	ctx       context.Context
+	schedules chan *schedulerpb.Schedule
+}
+
+func (fs *fakeWatchStream) Context() context.Context {
+	return fs.ctx
+}
+
+func (fs *fakeWatchStream) Send(schedule *schedulerpb.Schedule) error {
+	fs.schedules <- schedule
+
+	return nil
+}
+
+// countingBlobs counts the blobs read through it.
+type countingBlobs struct {
+	blob.Store
+
+	Gets int
+}
+
+func (cb *countingBlobs) Get(ctx context.Context, key string) ([]byte, error) {
+	cb.Gets++
+
+	return cb.Store.Get(ctx, key)
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+)
+
+// defaultBlobThreshold leaves room for the body and the result next to each
+// other in the 400 KB item.
+const defaultBlobThreshold = 64 * 1024
+
+func blobThreshold() int {
+	bytes, err := strconv.Atoi(os.Getenv("SCHEDULER_BLOB_THRESHOLD_BYTES"))
+
+	if err != nil || bytes < 1 {
+		return defaultBlobThreshold
+	}
+
+	return bytes
+}
+
+func bodyKey(id string) string {
+	return "bodies/" + id
+}
@@ -8,4 +8,7 @@ type CreateInput struct {
 	Method  string            `json:"method" dynamodbav:"method"`
 	Headers map[string]string `json:"headers,omitempty" dynamodbav:"headers,omitempty"`
 	Body    string            `json:"body,omitempty" dynamodbav:"body,omitempty"`
+
+	Deadline    *time.Time `json:"deadline,omitempty" dynamodbav:"deadline,unixtime,omitempty"`
+	MaxLateness *int64     `json:"maxLateness,omitempty" dynamodbav:"-"`
 }
@@ -2,8 +2,13 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"log"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,6 +18,13 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/matoous/go-nanoid"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
 )
 
 type Storage interface {
@@ -22,18 +34,38 @@ type Storage interface {
 
 	Get(context.Context, string) (*Schedule, error)
 
+	// GetMany answers the schedules in the order of the ids, nil for the
+	// ones that do not exist.
+	GetMany(context.Context, []string) ([]*Schedule, error)
+
 	List(context.Context, ListInput) (*List, error)
+
+	Lateness(context.Context, LatenessInput) (*Lateness, error)
+
+	Stats(context.Context, StatsInput) (*Stats, error)
 }
 
 type Database struct {
 	dynamodb dynamodbiface.DynamoDBAPI
+	clock    clock.Clock
+	blobs    blob.Store
 }
 
-func NewDatabase(dynamodb dynamodbiface.DynamoDBAPI) *Database {
-	return &Database{dynamodb}
+// NewDatabase offloads bodies larger than the blob threshold to the blob
+// store when there is one, they are kept in the item otherwise.
+func NewDatabase(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	clock clock.Clock,
+	blobs blob.Store) *Database {
+
+	return &Database{dynamodb, clock, blobs}
 }
 
-const dummyValue = "-"
+const (
+	dummyValue         = "-"
+	dummyIndexName     = "ix_dummy_dueAt"
+	createdAtIndexName = "ix_dummy_createdAt"
+)
 
 type (
 	idGenerate func() (string, error)
@@ -91,12 +123,39 @@ func (srv *Database) Create(
 	}
 
 	item["id"] = &dynamodb.AttributeValue{S: aws.String(id)}
+
+	if srv.blobs != nil && len(input.Body) > blobThreshold() {
+		key := bodyKey(id)
+
+		if err = srv.blobs.Put(ctx, key, []byte(input.Body)); err != nil {
+			return "", err
+		}
+
+		delete(item, "body")
+		item["bodyRef"] = &dynamodb.AttributeValue{S: aws.String(key)}
+	}
+
 	item["status"] = &dynamodb.AttributeValue{
 		S: aws.String(ScheduleStatusIdle),
 	}
-	item["dummy"] = &dynamodb.AttributeValue{S: aws.String(dummyValue)}
+
+	shards := sharding.Count()
+	shard := sharding.Of(id, shards)
+
+	item["dummy"] = &dynamodb.AttributeValue{
+		S: aws.String(sharding.Key(dummyValue, shard, shards)),
+	}
+
+	if shards > 1 {
+		item["statusShard"] = &dynamodb.AttributeValue{
+			S: aws.String(sharding.Key(ScheduleStatusIdle, shard, shards)),
+		}
+	}
+
+	now := srv.clock.Now()
+
 	item["createdAt"] = &dynamodb.AttributeValue{
-		N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+		N: aws.String(strconv.FormatInt(now.Unix(), 10)),
 	}
 
 	params := &dynamodb.PutItemInput{
@@ -110,6 +169,12 @@ func (srv *Database) Create(
 	}
 
 	if _, err = srv.dynamodb.PutItemWithContext(ctx, params); err != nil {
+		if ref, ok := item["bodyRef"]; ok {
+			if de := srv.blobs.Delete(ctx, *ref.S); de != nil {
+				log.Printf("body delete error: %v", de)
+			}
+		}
+
 		return "", err
 	}
 
@@ -117,6 +182,9 @@ func (srv *Database) Create(
 }
 
 func (srv *Database) Cancel(ctx context.Context, id string) (bool, error) {
+	at := srv.clock.Now()
+	now := at.Unix()
+
 	params := &dynamodb.UpdateItemInput{
 		TableName: aws.String(tableName()),
 		Key: map[string]*dynamodb.AttributeValue{
@@ -131,7 +199,7 @@ func (srv *Database) Cancel(ctx context.Context, id string) (bool, error) {
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":s1": {S: aws.String(ScheduleStatusCanceled)},
 			":s2": {S: aws.String(ScheduleStatusIdle)},
-			":ca": {N: aws.String(strconv.FormatInt(time.Now().Unix(), 10))},
+			":ca": {N: aws.String(strconv.FormatInt(now, 10))},
 		},
 		ReturnItemCollectionMetrics: aws.String(
 			dynamodb.ReturnItemCollectionMetricsNone),
@@ -141,6 +209,30 @@ func (srv *Database) Cancel(ctx context.Context, id string) (bool, error) {
 			dynamodb.ReturnValueNone),
 	}
 
+	if shards := sharding.Count(); shards > 1 {
+		params.UpdateExpression = aws.String("SET #s = :s1, #ss = :ss, #ca = :ca")
+		params.ExpressionAttributeNames["#ss"] = aws.String("statusShard")
+		params.ExpressionAttributeValues[":ss"] = &dynamodb.AttributeValue{
+			S: aws.String(sharding.Key(
+				ScheduleStatusCanceled,
+				sharding.Of(id, shards),
+				shards)),
+		}
+	}
+
+	if seconds := retention(ScheduleStatusCanceled); seconds > 0 {
+		params.UpdateExpression = aws.String(
+			*params.UpdateExpression + ", #t = :t")
+		params.ExpressionAttributeNames["#t"] = aws.String("ttl")
+		params.ExpressionAttributeValues[":t"] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(now+seconds, 10)),
+		}
+	}
+
+	if table := statsTableName(); table != "" {
+		return srv.cancelCounted(ctx, params, table, at)
+	}
+
 	if _, err := srv.dynamodb.UpdateItemWithContext(ctx, params); err != nil {
 		if ccf, ok := err.(awserr.RequestFailure); ok &&
 			ccf.Code() == "ConditionalCheckFailedException" {
@@ -153,6 +245,71 @@ func (srv *Database) Cancel(ctx context.Context, id string) (bool, error) {
 	return true, nil
 }
 
+// cancelCounted cancels in a transaction with the count of the cancel, the
+// url the count needs is read first, it never changes.
+func (srv *Database) cancelCounted(
+	ctx context.Context,
+	params *dynamodb.UpdateItemInput,
+	table string,
+	at time.Time) (bool, error) {
+
+	res, err := srv.dynamodb.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:            params.TableName,
+		Key:                  params.Key,
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#u"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u": aws.String("url"),
+		},
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityNone),
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(res.Item) == 0 {
+		return false, nil
+	}
+
+	var s Schedule
+
+	if err = unmarshalMap(res.Item, &s); err != nil {
+		return false, err
+	}
+
+	err = srv.stats(table).Transact(
+		ctx,
+		[]*dynamodb.TransactWriteItem{{
+			Update: &dynamodb.Update{
+				TableName:                 params.TableName,
+				Key:                       params.Key,
+				UpdateExpression:          params.UpdateExpression,
+				ConditionExpression:       params.ConditionExpression,
+				ExpressionAttributeNames:  params.ExpressionAttributeNames,
+				ExpressionAttributeValues: params.ExpressionAttributeValues,
+			},
+		}},
+		[]stats.Transition{{
+			Status: ScheduleStatusCanceled,
+			URL:    s.URL,
+			At:     at,
+		}})
+
+	if err != nil {
+		if tce, ok := err.(*dynamodb.TransactionCanceledException); ok &&
+			len(tce.CancellationReasons) > 0 &&
+			aws.StringValue(tce.CancellationReasons[0].Code) ==
+				"ConditionalCheckFailed" {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (srv *Database) Get(ctx context.Context, id string) (*Schedule, error) {
 	params := &dynamodb.GetItemInput{
 		TableName: aws.String(tableName()),
@@ -181,22 +338,380 @@ func (srv *Database) Get(ctx context.Context, id string) (*Schedule, error) {
 	return &s, nil
 }
 
+const (
+	maxBatchGets     = 100
+	batchGetBackoff  = 50 * time.Millisecond
+	maxBatchGetRetry = 5
+)
+
+var errUnprocessedKeys = errors.New("schedules left unprocessed")
+
+func (srv *Database) GetMany(
+	ctx context.Context,
+	ids []string) ([]*Schedule, error) {
+
+	unique := distinct(ids)
+	found := make(map[string]*Schedule, len(unique))
+	g, ctx := errgroup.WithContext(ctx)
+	var mutex sync.Mutex
+
+	for start := 0; start < len(unique); start += maxBatchGets {
+		end := start + maxBatchGets
+
+		if end > len(unique) {
+			end = len(unique)
+		}
+
+		chunk := unique[start:end]
+
+		g.Go(func() error {
+			schedules, err := srv.batchGet(ctx, chunk)
+
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for _, s := range schedules {
+				found[s.ID] = s
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*Schedule, len(ids))
+
+	for i, id := range ids {
+		schedules[i] = found[id]
+	}
+
+	return schedules, nil
+}
+
+// batchGet retries the keys DynamoDB leaves unprocessed with an exponential
+// backoff, they are throttled.
+func (srv *Database) batchGet(
+	ctx context.Context,
+	ids []string) ([]*Schedule, error) {
+
+	table := tableName()
+	keys := make([]map[string]*dynamodb.AttributeValue, len(ids))
+
+	for i, id := range ids {
+		keys[i] = map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(id)},
+		}
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	backoff := batchGetBackoff
+
+	for attempt := 0; ; attempt++ {
+		res, err := srv.dynamodb.BatchGetItemWithContext(
+			ctx,
+			&dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					table: {Keys: keys},
+				},
+				ReturnConsumedCapacity: aws.String(
+					dynamodb.ReturnConsumedCapacityNone),
+			})
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, res.Responses[table]...)
+
+		uk, ok := res.UnprocessedKeys[table]
+
+		if !ok || len(uk.Keys) == 0 {
+			break
+		}
+
+		if attempt == maxBatchGetRetry {
+			return nil, errUnprocessedKeys
+		}
+
+		keys = uk.Keys
+		srv.clock.Wait(ctx, backoff)
+		backoff *= 2
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var schedules []*Schedule
+
+	if err := unmarshalListOfMap(items, &schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func distinct(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
 func (srv *Database) List(ctx context.Context, input ListInput) (*List, error) {
+	shards := sharding.Count()
+
+	if shards <= 1 {
+		return srv.list(ctx, input)
+	}
+
+	return srv.listShards(ctx, input, shards)
+}
+
+func (srv *Database) list(ctx context.Context, input ListInput) (*List, error) {
+	params := listParams(input, 0, 1)
+
+	if input.StartKey != nil {
+		startKey, err := marshalStruct(input.StartKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if attr, _ := listPartition(input); attr == "dummy" {
+			startKey["dummy"] = &dynamodb.AttributeValue{
+				S: aws.String(dummyValue),
+			}
+		}
+
+		params.ExclusiveStartKey = startKey
+	}
+
+	items, lastKey, err := srv.readPage(ctx, params, input.Limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []*Schedule
+
+	if err = unmarshalListOfMap(items, &schedules); err != nil {
+		return nil, err
+	}
+
+	var nextKey *ListKey
+
+	if input.Limit > 0 && int64(len(schedules)) > input.Limit {
+		schedules = schedules[:input.Limit]
+		nextKey = input.nextKey(schedules[len(schedules)-1])
+	} else if len(lastKey) > 0 {
+		var nk ListKey
+
+		if err = unmarshalMap(lastKey, &nk); err != nil {
+			return nil, err
+		}
+
+		nextKey = &nk
+	}
+
+	return &List{Schedules: schedules, NextKey: nextKey}, nil
+}
+
+// maxListReads bounds the queries of a filtered page, of every shard, a
+// sparse filter answers a short or even empty page with a next key rather
+// than reading on through the whole index.
+const maxListReads = 5
+
+// readPage queries till the page has limit items, there are no more or a
+// filtered query read maxListReads times, it answers the items, which can
+// be more than the limit, and the key the query stopped at.
+func (srv *Database) readPage(
+	ctx context.Context,
+	params *dynamodb.QueryInput,
+	limit int64) (
+	[]map[string]*dynamodb.AttributeValue,
+	map[string]*dynamodb.AttributeValue,
+	error) {
+
+	var items []map[string]*dynamodb.AttributeValue
+
+	for reads := 1; ; reads++ {
+		res, err := srv.dynamodb.QueryWithContext(ctx, params)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items = append(items, res.Items...)
+
+		if len(res.LastEvaluatedKey) == 0 ||
+			int64(len(items)) >= limit ||
+			(params.FilterExpression != nil && reads >= maxListReads) {
+			return items, res.LastEvaluatedKey, nil
+		}
+
+		params.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+// listShards queries every shard for a full page and merges them in the
+// order of the list, the next key is the last returned schedule which is a
+// valid position in each of the shards. A shard whose filtered reads stopped
+// short of a full page bounds the page at the key it stopped at instead.
+func (srv *Database) listShards(
+	ctx context.Context,
+	input ListInput,
+	shards int) (*List, error) {
+
+	pages := make([][]*Schedule, shards)
+	more := make([]bool, shards)
+	stops := make([]*ListKey, shards)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	for shard := 0; shard < shards; shard++ {
+		index := shard
+
+		g.Go(func() error {
+			params := listParams(input, index, shards)
+
+			if input.StartKey != nil && input.startValue() != nil {
+				attr, value := listPartition(input)
+
+				if attr == "status" {
+					attr = "statusShard"
+				}
+
+				params.ExclusiveStartKey = map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(input.StartKey.ID)},
+					listSort(input): {
+						N: aws.String(
+							strconv.FormatInt(*input.startValue(), 10)),
+					},
+					attr: params.ExpressionAttributeValues[value],
+				}
+			}
+
+			items, lastKey, err := srv.readPage(gctx, params, input.Limit)
+
+			if err != nil {
+				return err
+			}
+
+			if err = unmarshalListOfMap(items, &pages[index]); err != nil {
+				return err
+			}
+
+			more[index] = len(lastKey) > 0
+
+			if !more[index] || int64(len(pages[index])) >= input.Limit {
+				return nil
+			}
+
+			var stop ListKey
+
+			if err = unmarshalMap(lastKey, &stop); err != nil {
+				return err
+			}
+
+			stops[index] = &stop
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var schedules []*Schedule
+	var stop *ListKey
+	hasMore := false
+
+	for shard, page := range pages {
+		schedules = append(schedules, page...)
+		hasMore = hasMore || more[shard]
+
+		if s := stops[shard]; s != nil && (stop == nil || input.precedes(
+			*input.keyValue(s), s.ID,
+			*input.keyValue(stop), stop.ID)) {
+			stop = s
+		}
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return input.precedes(
+			input.orderValue(schedules[i]), schedules[i].ID,
+			input.orderValue(schedules[j]), schedules[j].ID)
+	})
+
+	// The schedules past the stop are left for the next page, the shard
+	// that stopped has not read its own ones before them yet.
+	if stop != nil {
+		for i, s := range schedules {
+			if input.precedes(
+				*input.keyValue(stop), stop.ID,
+				input.orderValue(s), s.ID) {
+				schedules = schedules[:i]
+				break
+			}
+		}
+	}
+
+	var nextKey *ListKey
+
+	if input.Limit > 0 && int64(len(schedules)) > input.Limit {
+		schedules = schedules[:input.Limit]
+		nextKey = input.nextKey(schedules[len(schedules)-1])
+	} else if stop != nil {
+		nextKey = stop
+
+		if input.Status != "" && !input.byCreatedAt() {
+			nextKey.Status = &input.Status
+		}
+	} else if hasMore && len(schedules) > 0 {
+		nextKey = input.nextKey(schedules[len(schedules)-1])
+	}
+
+	return &List{Schedules: schedules, NextKey: nextKey}, nil
+}
+
+func listParams(input ListInput, shard, shards int) *dynamodb.QueryInput {
 	params := &dynamodb.QueryInput{
 		TableName:                 aws.String(tableName()),
-		IndexName:                 aws.String("ix_dummy_dueAt"),
+		IndexName:                 aws.String(dummyIndexName),
 		Limit:                     aws.Int64(input.Limit),
 		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityNone),
 		ExpressionAttributeNames:  map[string]*string{},
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{},
-		ScanIndexForward:          aws.Bool(false),
+		ScanIndexForward:          aws.Bool(input.ascending()),
+	}
+
+	if input.byCreatedAt() {
+		return createdAtParams(params, input, shard, shards)
 	}
 
 	if input.Status != "" {
-		params.IndexName = aws.String("ix_status_dueAt")
-		params.ExpressionAttributeNames["#s"] = aws.String("status")
+		index, attr := sharding.StatusIndex(shards)
+
+		params.IndexName = aws.String(index)
+		params.ExpressionAttributeNames["#s"] = aws.String(attr)
 		params.ExpressionAttributeValues[":s"] = &dynamodb.AttributeValue{
-			S: aws.String(input.Status),
+			S: aws.String(sharding.Key(input.Status, shard, shards)),
 		}
 
 		if input.DueAt == nil {
@@ -215,7 +730,7 @@ func (srv *Database) List(ctx context.Context, input ListInput) (*List, error) {
 	} else if input.DueAt != nil {
 		params.ExpressionAttributeNames["#d"] = aws.String("dummy")
 		params.ExpressionAttributeValues[":d"] = &dynamodb.AttributeValue{
-			S: aws.String(dummyValue),
+			S: aws.String(sharding.Key(dummyValue, shard, shards)),
 		}
 		params.ExpressionAttributeNames["#da"] = aws.String("dueAt")
 		params.ExpressionAttributeValues[":da1"] = &dynamodb.AttributeValue{
@@ -230,53 +745,196 @@ func (srv *Database) List(ctx context.Context, input ListInput) (*List, error) {
 		params.KeyConditionExpression = aws.String("#d = :d")
 		params.ExpressionAttributeNames["#d"] = aws.String("dummy")
 		params.ExpressionAttributeValues[":d"] = &dynamodb.AttributeValue{
-			S: aws.String(dummyValue),
+			S: aws.String(sharding.Key(dummyValue, shard, shards)),
 		}
 	}
 
-	if input.StartKey != nil {
-		startKey, err := marshalStruct(input.StartKey)
+	return params
+}
 
-		if err != nil {
-			return nil, err
+// createdAtParams lists on the createdAt index of every schedule, the
+// status and dueAt are filtered, so a page can have less than the limit.
+func createdAtParams(
+	params *dynamodb.QueryInput,
+	input ListInput,
+	shard, shards int) *dynamodb.QueryInput {
+
+	params.IndexName = aws.String(createdAtIndexName)
+	params.KeyConditionExpression = aws.String("#d = :d")
+	params.ExpressionAttributeNames["#d"] = aws.String("dummy")
+	params.ExpressionAttributeValues[":d"] = &dynamodb.AttributeValue{
+		S: aws.String(sharding.Key(dummyValue, shard, shards)),
+	}
+
+	var filters []string
+
+	if input.Status != "" {
+		params.ExpressionAttributeNames["#s"] = aws.String("status")
+		params.ExpressionAttributeValues[":s"] = &dynamodb.AttributeValue{
+			S: aws.String(input.Status),
 		}
 
-		if *params.IndexName == "ix_dummy_dueAt" {
-			startKey["dummy"] = &dynamodb.AttributeValue{
-				S: aws.String(dummyValue),
-			}
+		filters = append(filters, "#s = :s")
+	}
+
+	if input.DueAt != nil {
+		params.ExpressionAttributeNames["#da"] = aws.String("dueAt")
+		params.ExpressionAttributeValues[":da1"] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(input.DueAt.From.Unix(), 10)),
+		}
+		params.ExpressionAttributeValues[":da2"] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(input.DueAt.To.Unix(), 10)),
 		}
 
-		params.ExclusiveStartKey = startKey
+		filters = append(filters, "#da BETWEEN :da1 AND :da2")
 	}
 
-	res, err := srv.dynamodb.QueryWithContext(ctx, params)
+	if len(filters) > 0 {
+		params.FilterExpression = aws.String(strings.Join(filters, " AND "))
+	}
 
-	if err != nil {
+	return params
+}
+
+// listPartition is the partition key of the index the input is listed on
+// and the placeholder of its value in the query.
+func listPartition(input ListInput) (string, string) {
+	if input.Status != "" && !input.byCreatedAt() {
+		return "status", ":s"
+	}
+
+	return "dummy", ":d"
+}
+
+func listSort(input ListInput) string {
+	if input.byCreatedAt() {
+		return "createdAt"
+	}
+
+	return "dueAt"
+}
+
+func (srv *Database) Lateness(
+	ctx context.Context,
+	input LatenessInput) (*Lateness, error) {
+
+	shards := sharding.Count()
+	lags := make([][]int64, shards)
+	expired := make([]int64, shards)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	for shard := 0; shard < shards; shard++ {
+		index := shard
+
+		g.Go(func() error {
+			params := &dynamodb.QueryInput{
+				TableName: aws.String(tableName()),
+				IndexName: aws.String(dummyIndexName),
+				KeyConditionExpression: aws.String(
+					"#d = :d AND #da BETWEEN :da1 AND :da2"),
+				ProjectionExpression: aws.String("#l, #s"),
+				ExpressionAttributeNames: map[string]*string{
+					"#d":  aws.String("dummy"),
+					"#da": aws.String("dueAt"),
+					"#l":  aws.String("lag"),
+					"#s":  aws.String("status"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":d": {
+						S: aws.String(sharding.Key(dummyValue, index, shards)),
+					},
+					":da1": {
+						N: aws.String(
+							strconv.FormatInt(input.DueAt.From.Unix(), 10)),
+					},
+					":da2": {
+						N: aws.String(
+							strconv.FormatInt(input.DueAt.To.Unix(), 10)),
+					},
+				},
+				ReturnConsumedCapacity: aws.String(
+					dynamodb.ReturnConsumedCapacityNone),
+			}
+
+			for {
+				res, err := srv.dynamodb.QueryWithContext(gctx, params)
+
+				if err != nil {
+					return err
+				}
+
+				var page []latenessRun
+
+				if err = unmarshalListOfMap(res.Items, &page); err != nil {
+					return err
+				}
+
+				for _, run := range page {
+					if run.Status == ScheduleStatusExpired {
+						expired[index]++
+					}
+
+					if run.Lag != nil {
+						lags[index] = append(lags[index], *run.Lag)
+					}
+				}
+
+				if len(res.LastEvaluatedKey) == 0 {
+					return nil
+				}
+
+				params.ExclusiveStartKey = res.LastEvaluatedKey
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
-	var nextKey *ListKey
+	var (
+		all      []int64
+		expiries int64
+	)
 
-	if len(res.LastEvaluatedKey) > 0 {
-		var nk ListKey
+	for shard := range lags {
+		all = append(all, lags[shard]...)
+		expiries += expired[shard]
+	}
 
-		err = unmarshalMap(res.LastEvaluatedKey, &nk)
+	return newLateness(all, expiries), nil
+}
 
-		if err != nil {
-			return nil, err
-		}
+func (srv *Database) Stats(
+	ctx context.Context,
+	input StatsInput) (*Stats, error) {
 
-		nextKey = &nk
+	table := statsTableName()
+
+	if table == "" {
+		return nil, ErrNoStats
 	}
 
-	var schedules []*Schedule
+	counts, err := srv.stats(table).Read(
+		ctx,
+		input.At.From,
+		input.At.To)
 
-	if err = unmarshalListOfMap(res.Items, &schedules); err != nil {
+	if err != nil {
 		return nil, err
 	}
 
-	return &List{Schedules: schedules, NextKey: nextKey}, nil
+	return newStats(counts), nil
+}
+
+func (srv *Database) stats(table string) *stats.Dynamo {
+	return stats.NewDynamo(srv.dynamodb, table, srv.clock)
+}
+
+type latenessRun struct {
+	Lag    *int64 `dynamodbav:"lag"`
+	Status string `dynamodbav:"status"`
 }
 
 func tableName() string {
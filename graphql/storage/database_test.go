@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -14,6 +15,9 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -33,11 +37,13 @@ var _ = Describe("Database", func() {
 		db     *Database
 	)
 
+	now := time.Unix(1600000000, 0)
+
 	BeforeEach(func() {
 		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
 
 		dynamo = fakeDynamoDB{}
-		db = NewDatabase(&dynamo)
+		db = NewDatabase(&dynamo, clock.NewFake(now), nil)
 	})
 
 	Describe("NewDatabase", func() {
@@ -88,8 +94,9 @@ var _ = Describe("Database", func() {
 				Expect(*dynamo.PutInput.Item["dummy"].S).To(Equal(dummyValue))
 			})
 
-			It("includes createdAt in put", func() {
-				Expect(dynamo.PutInput.Item["createdAt"].N).NotTo(Equal(""))
+			It("includes createdAt from clock in put", func() {
+				Expect(*dynamo.PutInput.Item["createdAt"].N).To(
+					Equal(strconv.FormatInt(now.Unix(), 10)))
 			})
 
 			It("sets put from input", func() {
@@ -111,6 +118,102 @@ var _ = Describe("Database", func() {
 			})
 		})
 
+		Describe("sharded", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", "4")
+
+				_, _ = db.Create(context.TODO(), CreateInput{
+					DueAt:  time.Now().Add(time.Minute * 1),
+					URL:    url,
+					Method: method,
+				})
+			})
+
+			It("includes sharded dummy in put", func() {
+				Expect(*dynamo.PutInput.Item["dummy"].S).To(
+					HavePrefix(dummyValue + "#0"))
+			})
+
+			It("includes sharded status in put", func() {
+				Expect(*dynamo.PutInput.Item["statusShard"].S).To(
+					HavePrefix(ScheduleStatusIdle + "#0"))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+		})
+
+		Describe("large body", func() {
+			var (
+				blobs fakeBlobs
+				res   string
+				err   error
+			)
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "8")
+
+				blobs = fakeBlobs{}
+				db = NewDatabase(&dynamo, clock.NewFake(now), &blobs)
+
+				res, err = db.Create(context.TODO(), CreateInput{
+					DueAt:  time.Now().Add(time.Minute * 1),
+					URL:    url,
+					Method: method,
+					Body:   body,
+				})
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_BLOB_THRESHOLD_BYTES")
+			})
+
+			It("puts body to blob store", func() {
+				Expect(string(blobs["bodies/"+res])).To(Equal(body))
+			})
+
+			It("includes body ref instead of body in put", func() {
+				Expect(dynamo.PutInput.Item).NotTo(HaveKey("body"))
+				Expect(*dynamo.PutInput.Item["bodyRef"].S).To(
+					Equal("bodies/" + res))
+			})
+
+			It("keeps body under threshold in put", func() {
+				_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "1024")
+
+				_, _ = db.Create(context.TODO(), CreateInput{
+					DueAt:  time.Now().Add(time.Minute * 1),
+					URL:    url,
+					Method: method,
+					Body:   body,
+				})
+
+				Expect(*dynamo.PutInput.Item["body"].S).To(Equal(body))
+				Expect(dynamo.PutInput.Item).NotTo(HaveKey("bodyRef"))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+
+			It("deletes body of failed put", func() {
+				blobs = fakeBlobs{}
+				db = NewDatabase(&dynamo, clock.NewFake(now), &blobs)
+				dynamo.Error = fmt.Errorf("put error")
+
+				_, err = db.Create(context.TODO(), CreateInput{
+					DueAt:  time.Now().Add(time.Minute * 1),
+					URL:    url,
+					Method: method,
+					Body:   body,
+				})
+
+				Expect(err).NotTo(BeNil())
+				Expect(blobs).To(BeEmpty())
+			})
+		})
+
 		Describe("fail", func() {
 			Context("id generate error", func() {
 				var (
@@ -241,11 +344,11 @@ var _ = Describe("Database", func() {
 					Equal(ScheduleStatusCanceled))
 			})
 
-			It("updates sets canceledAt", func() {
+			It("updates sets canceledAt from clock", func() {
 				Expect(dynamo.UpdateInput.UpdateExpression).NotTo(Equal(""))
 				Expect(
-					dynamo.UpdateInput.ExpressionAttributeValues[":ca"].N).NotTo(
-					Equal(""))
+					*dynamo.UpdateInput.ExpressionAttributeValues[":ca"].N).To(
+					Equal(strconv.FormatInt(now.Unix(), 10)))
 			})
 
 			It("returns success", func() {
@@ -257,6 +360,46 @@ var _ = Describe("Database", func() {
 			})
 		})
 
+		Describe("sharded", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", "4")
+
+				_, _ = db.Cancel(context.TODO(), id)
+			})
+
+			It("updates sharded status to canceled", func() {
+				Expect(
+					*dynamo.UpdateInput.ExpressionAttributeValues[":ss"].S).To(
+					HavePrefix(ScheduleStatusCanceled + "#0"))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+		})
+
+		Describe("with retention", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_RETENTION_CANCELED_SECONDS", "3600")
+
+				_, _ = db.Cancel(context.TODO(), id)
+			})
+
+			It("sets ttl from clock", func() {
+				Expect(*dynamo.UpdateInput.UpdateExpression).To(
+					ContainSubstring("#t = :t"))
+				Expect(*dynamo.UpdateInput.ExpressionAttributeNames["#t"]).To(
+					Equal("ttl"))
+				Expect(
+					*dynamo.UpdateInput.ExpressionAttributeValues[":t"].N).To(
+					Equal(strconv.FormatInt(now.Unix()+3600, 10)))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_RETENTION_CANCELED_SECONDS")
+			})
+		})
+
 		Describe("fail", func() {
 			Context("status is not idle", func() {
 				var (
@@ -464,6 +607,224 @@ var _ = Describe("Database", func() {
 		})
 	})
 
+	Describe("GetMany", func() {
+		var (
+			fake *clock.Fake
+			res  []*Schedule
+			err  error
+		)
+
+		key := func(id string) map[string]*dynamodb.AttributeValue {
+			return map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(id)},
+			}
+		}
+
+		item := func(id string) map[string]*dynamodb.AttributeValue {
+			i, _ := dynamodbattribute.MarshalMap(Schedule{
+				ID:     id,
+				DueAt:  now,
+				URL:    url,
+				Method: method,
+				Status: ScheduleStatusIdle,
+			})
+
+			return i
+		}
+
+		BeforeEach(func() {
+			fake = clock.NewFake(now)
+			db = NewDatabase(&dynamo, fake, nil)
+		})
+
+		Describe("success", func() {
+			BeforeEach(func() {
+				dynamo.BatchGetOutputs = []*dynamodb.BatchGetItemOutput{{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{
+						table: {item("2"), item("1")},
+					},
+				}}
+
+				res, err = db.GetMany(
+					context.TODO(),
+					[]string{"1", "2", "3", "1"})
+			})
+
+			It("reads table name from env", func() {
+				Expect(dynamo.BatchGetInputs[0].RequestItems).To(HaveKey(table))
+			})
+
+			It("gets each id once", func() {
+				Expect(dynamo.BatchGetInputs).To(HaveLen(1))
+				Expect(dynamo.BatchGetInputs[0].RequestItems[table].Keys).To(
+					Equal([]map[string]*dynamodb.AttributeValue{
+						key("1"),
+						key("2"),
+						key("3"),
+					}))
+			})
+
+			It("returns schedules in the order of the ids", func() {
+				Expect(res).To(HaveLen(4))
+				Expect(res[0].ID).To(Equal("1"))
+				Expect(res[1].ID).To(Equal("2"))
+				Expect(res[2]).To(BeNil())
+				Expect(res[3].ID).To(Equal("1"))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("more than a batch", func() {
+			BeforeEach(func() {
+				ids := make([]string, 150)
+
+				for i := range ids {
+					ids[i] = strconv.Itoa(i)
+				}
+
+				res, err = db.GetMany(context.TODO(), ids)
+			})
+
+			It("gets in batches of 100", func() {
+				var sizes []int
+
+				for _, input := range dynamo.BatchGetInputs {
+					sizes = append(sizes, len(input.RequestItems[table].Keys))
+				}
+
+				Expect(sizes).To(ConsistOf(100, 50))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("unprocessed keys", func() {
+			var done chan struct{}
+
+			BeforeEach(func() {
+				dynamo.BatchGetOutputs = []*dynamodb.BatchGetItemOutput{
+					{
+						Responses: map[string][]map[string]*dynamodb.AttributeValue{
+							table: {item("1")},
+						},
+						UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{
+							table: {Keys: []map[string]*dynamodb.AttributeValue{
+								key("2"),
+							}},
+						},
+					},
+					{
+						Responses: map[string][]map[string]*dynamodb.AttributeValue{
+							table: {item("2")},
+						},
+					},
+				}
+
+				done = make(chan struct{})
+
+				go func() {
+					defer close(done)
+
+					res, err = db.GetMany(context.TODO(), []string{"1", "2"})
+				}()
+			})
+
+			It("retries them after a backoff", func() {
+				Eventually(fake.Waiters).Should(Equal(1))
+				Consistently(done).ShouldNot(BeClosed())
+
+				fake.Advance(batchGetBackoff)
+				Eventually(done).Should(BeClosed())
+
+				Expect(dynamo.BatchGetInputs).To(HaveLen(2))
+				Expect(dynamo.BatchGetInputs[1].RequestItems[table].Keys).To(
+					Equal([]map[string]*dynamodb.AttributeValue{key("2")}))
+				Expect(res[0].ID).To(Equal("1"))
+				Expect(res[1].ID).To(Equal("2"))
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			Context("batch get error", func() {
+				BeforeEach(func() {
+					dynamo.Error = awserr.New(
+						"InternalError",
+						"InternalError",
+						nil)
+
+					res, err = db.GetMany(context.TODO(), []string{id})
+				})
+
+				It("does not return any schedule", func() {
+					Expect(res).To(BeNil())
+				})
+
+				It("returns error", func() {
+					Expect(err).NotTo(BeNil())
+				})
+
+				AfterEach(func() {
+					dynamo.Error = nil
+				})
+			})
+
+			Context("keys left unprocessed", func() {
+				BeforeEach(func() {
+					for i := 0; i <= maxBatchGetRetry; i++ {
+						dynamo.BatchGetOutputs = append(
+							dynamo.BatchGetOutputs,
+							&dynamodb.BatchGetItemOutput{
+								UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{
+									table: {Keys: []map[string]*dynamodb.AttributeValue{
+										key(id),
+									}},
+								},
+							})
+					}
+				})
+
+				It("gives up after the retries", func() {
+					done := make(chan struct{})
+
+					go func() {
+						defer close(done)
+
+						res, err = db.GetMany(context.TODO(), []string{id})
+					}()
+
+					for i := 0; i < maxBatchGetRetry; i++ {
+						Eventually(fake.Waiters).Should(Equal(1))
+						fake.Advance(time.Minute)
+					}
+
+					Eventually(done).Should(BeClosed())
+
+					Expect(dynamo.BatchGetInputs).To(
+						HaveLen(maxBatchGetRetry + 1))
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(errUnprocessedKeys))
+				})
+
+				It("stops retrying once the context is done", func() {
+					ctx, cancel := context.WithCancel(context.TODO())
+					cancel()
+
+					res, err = db.GetMany(ctx, []string{id})
+
+					Expect(dynamo.BatchGetInputs).To(HaveLen(1))
+					Expect(res).To(BeNil())
+					Expect(err).To(Equal(context.Canceled))
+				})
+			})
+		})
+	})
+
 	Describe("List", func() {
 		Describe("success", func() {
 			Context("empty condition", func() {
@@ -758,6 +1119,210 @@ var _ = Describe("Database", func() {
 			})
 		})
 
+		Describe("by createdAt", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.QueryOutput = &dynamodb.QueryOutput{}
+				createdAt := time.Now().Add(-time.Hour).Unix()
+
+				_, err = db.List(context.TODO(), ListInput{
+					Status: ScheduleStatusIdle,
+					DueAt: &DateRange{
+						From: time.Now().Add(-time.Hour * 20),
+						To:   time.Now().Add(-time.Hour * 10),
+					},
+					OrderBy:   ListOrderCreatedAt,
+					Direction: ListDirectionAsc,
+					StartKey: &ListKey{
+						ID:        "6568",
+						CreatedAt: &createdAt,
+					},
+				})
+			})
+
+			It("uses ix_dummy_createdAt index", func() {
+				Expect(*dynamo.QueryInput.IndexName).To(
+					Equal("ix_dummy_createdAt"))
+				Expect(*dynamo.QueryInput.KeyConditionExpression).To(
+					Equal("#d = :d"))
+			})
+
+			It("filters status and dueAt", func() {
+				Expect(*dynamo.QueryInput.FilterExpression).To(
+					Equal("#s = :s AND #da BETWEEN :da1 AND :da2"))
+			})
+
+			It("scans forward", func() {
+				Expect(*dynamo.QueryInput.ScanIndexForward).To(BeTrue())
+			})
+
+			It("starts from createdAt key", func() {
+				key := dynamo.QueryInput.ExclusiveStartKey
+
+				Expect(key).To(HaveKey("createdAt"))
+				Expect(key).To(HaveKey("dummy"))
+				Expect(key).NotTo(HaveKey("dueAt"))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("sharded", func() {
+			var (
+				res *List
+				err error
+			)
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", "3")
+
+				now := time.Now()
+				items := make([]map[string]*dynamodb.AttributeValue, 2)
+
+				for i := 0; i < len(items); i++ {
+					item, _ := dynamodbattribute.MarshalMap(Schedule{
+						ID:     strconv.Itoa(i),
+						DueAt:  now.Add(time.Duration(i) * time.Minute),
+						Status: ScheduleStatusIdle,
+					})
+
+					items[i] = item
+				}
+
+				dynamo.QueryOutput = &dynamodb.QueryOutput{Items: items}
+
+				dueAt := now.Add(time.Hour).Unix()
+
+				res, err = db.List(context.TODO(), ListInput{
+					Status: ScheduleStatusIdle,
+					StartKey: &ListKey{
+						ID:    "6568",
+						DueAt: &dueAt,
+					},
+					Limit: 4,
+				})
+			})
+
+			It("queries every shard", func() {
+				var values []string
+
+				for _, input := range dynamo.QueryInputs {
+					values = append(
+						values,
+						*input.ExpressionAttributeValues[":s"].S)
+				}
+
+				Expect(values).To(ConsistOf("IDLE#00", "IDLE#01", "IDLE#02"))
+			})
+
+			It("uses ix_statusShard_dueAt index", func() {
+				for _, input := range dynamo.QueryInputs {
+					Expect(*input.IndexName).To(Equal("ix_statusShard_dueAt"))
+				}
+			})
+
+			It("starts every shard from the given key", func() {
+				for _, input := range dynamo.QueryInputs {
+					Expect(*input.ExclusiveStartKey["id"].S).To(Equal("6568"))
+					Expect(*input.ExclusiveStartKey["statusShard"].S).To(
+						Equal(*input.ExpressionAttributeValues[":s"].S))
+				}
+			})
+
+			It("merges shards by dueAt descending up to limit", func() {
+				Expect(res.Schedules).To(HaveLen(4))
+
+				for i := 1; i < len(res.Schedules); i++ {
+					Expect(res.Schedules[i-1].DueAt).NotTo(
+						BeTemporally("<", res.Schedules[i].DueAt))
+				}
+			})
+
+			It("returns last schedule as next key", func() {
+				last := res.Schedules[len(res.Schedules)-1]
+
+				Expect(res.NextKey.ID).To(Equal(last.ID))
+				Expect(*res.NextKey.DueAt).To(Equal(last.DueAt.Unix()))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+		})
+
+		Describe("sharded by createdAt ascending", func() {
+			var res *List
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", "3")
+
+				now := time.Now()
+				items := make([]map[string]*dynamodb.AttributeValue, 2)
+
+				for i := 0; i < len(items); i++ {
+					item, _ := dynamodbattribute.MarshalMap(Schedule{
+						ID:        strconv.Itoa(i),
+						CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+						Status:    ScheduleStatusIdle,
+					})
+
+					items[i] = item
+				}
+
+				dynamo.QueryOutput = &dynamodb.QueryOutput{Items: items}
+
+				createdAt := now.Add(-time.Hour).Unix()
+
+				res, _ = db.List(context.TODO(), ListInput{
+					Status:    ScheduleStatusIdle,
+					OrderBy:   ListOrderCreatedAt,
+					Direction: ListDirectionAsc,
+					StartKey: &ListKey{
+						ID:        "6568",
+						CreatedAt: &createdAt,
+					},
+					Limit: 4,
+				})
+			})
+
+			It("starts every shard from the given key", func() {
+				for _, input := range dynamo.QueryInputs {
+					Expect(*input.IndexName).To(Equal("ix_dummy_createdAt"))
+					Expect(*input.ExclusiveStartKey["dummy"].S).To(
+						Equal(*input.ExpressionAttributeValues[":d"].S))
+					Expect(input.ExclusiveStartKey).To(HaveKey("createdAt"))
+				}
+			})
+
+			It("merges shards by createdAt ascending up to limit", func() {
+				Expect(res.Schedules).To(HaveLen(4))
+
+				for i := 1; i < len(res.Schedules); i++ {
+					Expect(res.Schedules[i-1].CreatedAt).NotTo(
+						BeTemporally(">", res.Schedules[i].CreatedAt))
+				}
+			})
+
+			It("returns last schedule as next key", func() {
+				last := res.Schedules[len(res.Schedules)-1]
+
+				Expect(res.NextKey.ID).To(Equal(last.ID))
+				Expect(*res.NextKey.CreatedAt).To(Equal(last.CreatedAt.Unix()))
+				Expect(res.NextKey.Status).To(BeNil())
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+		})
+
 		Describe("fail", func() {
 			Context("start key marshal error", func() {
 				var (
@@ -901,6 +1466,105 @@ var _ = Describe("Database", func() {
 			})
 		})
 	})
+
+	Describe("Stats", func() {
+		It("returns error without stats table", func() {
+			_ = os.Unsetenv("SCHEDULER_STATS_TABLE_NAME")
+
+			res, err := db.Stats(context.TODO(), StatsInput{})
+
+			Expect(res).To(BeNil())
+			Expect(err).To(Equal(ErrNoStats))
+		})
+	})
+
+	Describe("Lateness", func() {
+		Describe("success", func() {
+			var (
+				res *Lateness
+				err error
+			)
+
+			BeforeEach(func() {
+				dynamo.QueryOutput = &dynamodb.QueryOutput{
+					Items: []map[string]*dynamodb.AttributeValue{
+						{
+							"lag":    {N: aws.String("1200")},
+							"status": {S: aws.String(ScheduleStatusSucceeded)},
+						},
+						{
+							"lag":    {N: aws.String("800")},
+							"status": {S: aws.String(ScheduleStatusFailed)},
+						},
+						{
+							"status": {S: aws.String(ScheduleStatusExpired)},
+						},
+						{
+							"status": {S: aws.String(ScheduleStatusIdle)},
+						},
+					},
+				}
+
+				res, err = db.Lateness(context.TODO(), LatenessInput{
+					DueAt: DateRange{
+						From: time.Now().Add(-time.Hour),
+						To:   time.Now(),
+					},
+				})
+			})
+
+			It("uses ix_dummy_dueAt index", func() {
+				Expect(*dynamo.QueryInput.IndexName).To(Equal("ix_dummy_dueAt"))
+			})
+
+			It("only reads lag and status", func() {
+				Expect(*dynamo.QueryInput.ProjectionExpression).To(
+					Equal("#l, #s"))
+			})
+
+			It("counts runs with lag", func() {
+				Expect(res.Count).To(BeEquivalentTo(2))
+			})
+
+			It("counts expired", func() {
+				Expect(res.Expired).To(BeEquivalentTo(1))
+			})
+
+			It("returns average and max", func() {
+				Expect(res.Average).To(BeEquivalentTo(1000))
+				Expect(res.Max).To(BeEquivalentTo(1200))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			var (
+				res *Lateness
+				err error
+			)
+
+			BeforeEach(func() {
+				dynamo.Error = fmt.Errorf("query error")
+
+				res, err = db.Lateness(context.TODO(), LatenessInput{})
+			})
+
+			It("does not return any result", func() {
+				Expect(res).To(BeNil())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+
+			AfterEach(func() {
+				dynamo.Error = nil
+			})
+		})
+	})
 })
 
 type fakeDynamoDB struct {
@@ -913,7 +1577,13 @@ type fakeDynamoDB struct {
 	GetInput    *dynamodb.GetItemInput
 	GetOutput   *dynamodb.GetItemOutput
 	QueryInput  *dynamodb.QueryInput
+	QueryInputs []*dynamodb.QueryInput
 	QueryOutput *dynamodb.QueryOutput
+
+	BatchGetInputs  []*dynamodb.BatchGetItemInput
+	BatchGetOutputs []*dynamodb.BatchGetItemOutput
+
+	mutex sync.Mutex
 }
 
 func (db *fakeDynamoDB) PutItemWithContext(
@@ -943,12 +1613,58 @@ func (db *fakeDynamoDB) GetItemWithContext(
 	return db.GetOutput, db.Error
 }
 
+func (db *fakeDynamoDB) BatchGetItemWithContext(
+	_ aws.Context,
+	input *dynamodb.BatchGetItemInput,
+	_ ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	db.BatchGetInputs = append(db.BatchGetInputs, input)
+
+	if db.Error != nil || len(db.BatchGetOutputs) == 0 {
+		return &dynamodb.BatchGetItemOutput{}, db.Error
+	}
+
+	output := db.BatchGetOutputs[0]
+	db.BatchGetOutputs = db.BatchGetOutputs[1:]
+
+	return output, nil
+}
+
 func (db *fakeDynamoDB) QueryWithContext(
 	_ aws.Context,
 	input *dynamodb.QueryInput,
 	_ ...request.Option) (*dynamodb.QueryOutput, error) {
 
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
 	db.QueryInput = input
+	db.QueryInputs = append(db.QueryInputs, input)
 
 	return db.QueryOutput, db.Error
 }
+
+type fakeBlobs map[string][]byte
+
+func (fb fakeBlobs) Put(_ context.Context, key string, body []byte) error {
+	fb[key] = body
+
+	return nil
+}
+
+func (fb fakeBlobs) Get(_ context.Context, key string) ([]byte, error) {
+	if body, found := fb[key]; found {
+		return body, nil
+	}
+
+	return nil, blob.ErrNotFound
+}
+
+func (fb fakeBlobs) Delete(_ context.Context, key string) error {
+	delete(fb, key)
+
+	return nil
+}
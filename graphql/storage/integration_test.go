@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database against dynamotest", func() {
+	const table = "scheduler_v1"
+
+	var (
+		db     *Database
+		dynamo *countingDynamoDB
+		ids    []string
+		now    time.Time
+	)
+
+	create := func(offset time.Duration) string {
+		id, err := db.Create(context.TODO(), CreateInput{
+			DueAt:  now.Add(offset),
+			URL:    "https://foo.bar/do",
+			Method: "POST",
+		})
+
+		Expect(err).To(BeNil())
+
+		return id
+	}
+
+	pages := func(input ListInput) [][]string {
+		var pages [][]string
+
+		for {
+			list, err := db.List(context.TODO(), input)
+			Expect(err).To(BeNil())
+
+			var page []string
+
+			for _, s := range list.Schedules {
+				page = append(page, s.ID)
+			}
+
+			pages = append(pages, page)
+
+			if list.NextKey == nil {
+				return pages
+			}
+
+			input.StartKey = list.NextKey
+		}
+	}
+
+	flatten := func(pages [][]string) []string {
+		var all []string
+
+		for _, page := range pages {
+			all = append(all, page...)
+		}
+
+		return all
+	}
+
+	for _, shards := range []string{"1", "4"} {
+		shardCount := shards
+
+		Context("with "+shardCount+" shard(s)", func() {
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", shardCount)
+
+				schedules := dynamotest.SchedulerTable(table)
+
+				if shardCount != "1" {
+					schedules = dynamotest.ShardedSchedulerTable(table)
+				}
+
+				dynamo = &countingDynamoDB{DynamoDB: dynamotest.New(schedules)}
+				db = NewDatabase(dynamo, clock.System, nil)
+
+				now = time.Now().Truncate(time.Second)
+				ids = []string{
+					create(3 * time.Minute),
+					create(time.Minute),
+					create(2 * time.Minute),
+				}
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+			})
+
+			It("gets created schedule", func() {
+				s, err := db.Get(context.TODO(), ids[0])
+
+				Expect(err).To(BeNil())
+				Expect(s.Status).To(Equal(ScheduleStatusIdle))
+				Expect(s.DueAt.Unix()).To(Equal(now.Add(3 * time.Minute).Unix()))
+			})
+
+			It("lists schedules latest due first across pages", func() {
+				all := flatten(pages(ListInput{Limit: 2}))
+
+				Expect(all).To(Equal([]string{ids[0], ids[2], ids[1]}))
+			})
+
+			It("lists schedules of status", func() {
+				canceled, err := db.Cancel(context.TODO(), ids[2])
+				Expect(err).To(BeNil())
+				Expect(canceled).To(BeTrue())
+
+				Expect(flatten(pages(ListInput{
+					Status: ScheduleStatusIdle,
+					Limit:  1,
+				}))).To(Equal([]string{ids[0], ids[1]}))
+
+				Expect(flatten(pages(ListInput{
+					Status: ScheduleStatusCanceled,
+					Limit:  10,
+				}))).To(Equal([]string{ids[2]}))
+			})
+
+			It("lists schedules due in range", func() {
+				Expect(flatten(pages(ListInput{
+					DueAt: &DateRange{
+						From: now,
+						To:   now.Add(2 * time.Minute),
+					},
+					Limit: 10,
+				}))).To(Equal([]string{ids[2], ids[1]}))
+			})
+
+			It("reads filtered page by createdAt till full", func() {
+				for _, id := range ids[:2] {
+					_, err := db.Cancel(context.TODO(), id)
+					Expect(err).To(BeNil())
+				}
+
+				all := pages(ListInput{
+					Status:  ScheduleStatusCanceled,
+					OrderBy: ListOrderCreatedAt,
+					Limit:   2,
+				})
+
+				Expect(all[0]).To(ConsistOf(ids[0], ids[1]))
+				Expect(flatten(all)).To(HaveLen(2))
+			})
+
+			It("bounds reads of sparse filter by createdAt", func() {
+				for i := 0; i < 40; i++ {
+					create(time.Hour)
+				}
+
+				canceled := create(time.Hour)
+				_, err := db.Cancel(context.TODO(), canceled)
+				Expect(err).To(BeNil())
+
+				input := ListInput{
+					Status:  ScheduleStatusCanceled,
+					OrderBy: ListOrderCreatedAt,
+					Limit:   1,
+				}
+
+				count, _ := strconv.Atoi(shardCount)
+				dynamo.Queries = 0
+				list, err := db.List(context.TODO(), input)
+
+				Expect(err).To(BeNil())
+				Expect(dynamo.Queries).To(BeNumerically(
+					"<=", maxListReads*count))
+				Expect(list.NextKey).NotTo(BeNil())
+
+				Expect(flatten(pages(input))).To(Equal([]string{canceled}))
+			})
+
+			It("cancels idle schedule once", func() {
+				first, err := db.Cancel(context.TODO(), ids[1])
+				Expect(err).To(BeNil())
+
+				second, err := db.Cancel(context.TODO(), ids[1])
+				Expect(err).To(BeNil())
+
+				Expect(first).To(BeTrue())
+				Expect(second).To(BeFalse())
+			})
+
+			It("reports lateness of schedules in range", func() {
+				l, err := db.Lateness(context.TODO(), LatenessInput{
+					DueAt: DateRange{From: now, To: now.Add(time.Hour)},
+				})
+
+				Expect(err).To(BeNil())
+				Expect(l.Count).To(BeZero())
+			})
+		})
+	}
+
+	Context("with stats table", func() {
+		const statsTable = "scheduler_stats_v1"
+
+		BeforeEach(func() {
+			_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+			_ = os.Setenv("SCHEDULER_STATS_TABLE_NAME", statsTable)
+
+			dynamo = &countingDynamoDB{DynamoDB: dynamotest.New(
+				dynamotest.SchedulerTable(table),
+				dynamotest.StatsTable(statsTable))}
+			db = NewDatabase(dynamo, clock.System, nil)
+
+			now = time.Now().Truncate(time.Second)
+			ids = []string{create(time.Minute), create(time.Minute)}
+		})
+
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_STATS_TABLE_NAME")
+		})
+
+		It("counts cancels with their writes", func() {
+			first, err := db.Cancel(context.TODO(), ids[0])
+			Expect(err).To(BeNil())
+
+			second, err := db.Cancel(context.TODO(), ids[0])
+			Expect(err).To(BeNil())
+
+			missing, err := db.Cancel(context.TODO(), "missing")
+			Expect(err).To(BeNil())
+
+			Expect(first).To(BeTrue())
+			Expect(second).To(BeFalse())
+			Expect(missing).To(BeFalse())
+
+			s, err := db.Stats(context.TODO(), StatsInput{
+				At: DateRange{From: now, To: now},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(s.Statuses).To(Equal([]StatusCount{
+				{ScheduleStatusCanceled, 1},
+			}))
+		})
+	})
+})
+
+// countingDynamoDB counts the queries the database reads a list with.
+type countingDynamoDB struct {
+	*dynamotest.DynamoDB
+
+	mutex   sync.Mutex
+	Queries int
+}
+
+func (db *countingDynamoDB) QueryWithContext(
+	ctx aws.Context,
+	input *dynamodb.QueryInput,
+	opts ...request.Option) (*dynamodb.QueryOutput, error) {
+
+	db.mutex.Lock()
+	db.Queries++
+	db.mutex.Unlock()
+
+	return db.DynamoDB.QueryWithContext(ctx, input, opts...)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"math"
+	"sort"
+)
+
+type Lateness struct {
+	Count   int64 `json:"count"`
+	Expired int64 `json:"expired"`
+	Average int64 `json:"average"`
+	P50     int64 `json:"p50"`
+	P95     int64 `json:"p95"`
+	P99     int64 `json:"p99"`
+	Max     int64 `json:"max"`
+}
+
+func newLateness(lags []int64, expired int64) *Lateness {
+	l := Lateness{
+		Count:   int64(len(lags)),
+		Expired: expired,
+	}
+
+	if len(lags) == 0 {
+		return &l
+	}
+
+	sort.Slice(lags, func(i, j int) bool {
+		return lags[i] < lags[j]
+	})
+
+	var sum int64
+
+	for _, lag := range lags {
+		sum += lag
+	}
+
+	l.Average = sum / int64(len(lags))
+	l.P50 = percentile(lags, 50)
+	l.P95 = percentile(lags, 95)
+	l.P99 = percentile(lags, 99)
+	l.Max = lags[len(lags)-1]
+
+	return &l
+}
+
+// percentile uses the nearest rank method on already sorted values.
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
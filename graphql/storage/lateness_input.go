@@ -0,0 +1,5 @@
+package storage
+
+type LatenessInput struct {
+	DueAt DateRange `json:"dueAt"`
+}
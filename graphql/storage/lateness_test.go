@@ -0,0 +1,56 @@
+package storage
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lateness", func() {
+	Context("no lag", func() {
+		var l *Lateness
+
+		BeforeEach(func() {
+			l = newLateness(nil, 2)
+		})
+
+		It("returns zero count", func() {
+			Expect(l.Count).To(BeZero())
+		})
+
+		It("returns expired", func() {
+			Expect(l.Expired).To(BeEquivalentTo(2))
+		})
+	})
+
+	Context("lags", func() {
+		var l *Lateness
+
+		BeforeEach(func() {
+			lags := make([]int64, 100)
+
+			for i := range lags {
+				lags[len(lags)-1-i] = int64(i + 1)
+			}
+
+			l = newLateness(lags, 0)
+		})
+
+		It("returns count", func() {
+			Expect(l.Count).To(BeEquivalentTo(100))
+		})
+
+		It("returns average", func() {
+			Expect(l.Average).To(BeEquivalentTo(50))
+		})
+
+		It("returns percentiles", func() {
+			Expect(l.P50).To(BeEquivalentTo(50))
+			Expect(l.P95).To(BeEquivalentTo(95))
+			Expect(l.P99).To(BeEquivalentTo(99))
+		})
+
+		It("returns max", func() {
+			Expect(l.Max).To(BeEquivalentTo(100))
+		})
+	})
+})
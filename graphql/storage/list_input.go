@@ -1,8 +1,10 @@
 package storage
 
 type ListInput struct {
-	Status   string     `json:"status,omitempty"`
-	DueAt    *DateRange `json:"dueAt,omitempty"`
-	StartKey *ListKey   `json:"startKey,omitempty"`
-	Limit    int64      `json:"limit"`
+	Status    string     `json:"status,omitempty"`
+	DueAt     *DateRange `json:"dueAt,omitempty"`
+	OrderBy   string     `json:"orderBy,omitempty"`
+	Direction string     `json:"direction,omitempty"`
+	StartKey  *ListKey   `json:"startKey,omitempty"`
+	Limit     int64      `json:"limit"`
 }
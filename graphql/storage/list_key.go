@@ -1,7 +1,8 @@
 package storage
 
 type ListKey struct {
-	ID     string  `json:"id" dynamodbav:"id"`
-	DueAt  *int64  `json:"dueAt" dynamodbav:"dueAt"`
-	Status *string `json:"status,omitempty" dynamodbav:"status,omitempty"`
+	ID        string  `json:"id" dynamodbav:"id"`
+	DueAt     *int64  `json:"dueAt" dynamodbav:"dueAt,omitempty"`
+	CreatedAt *int64  `json:"createdAt,omitempty" dynamodbav:"createdAt,omitempty"`
+	Status    *string `json:"status,omitempty" dynamodbav:"status,omitempty"`
 }
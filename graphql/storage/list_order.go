@@ -0,0 +1,83 @@
+package storage
+
+const (
+	ListOrderDueAt     = "DUE_AT"
+	ListOrderCreatedAt = "CREATED_AT"
+
+	ListDirectionDesc = "DESC"
+	ListDirectionAsc  = "ASC"
+)
+
+// byCreatedAt and ascending tell the order of the list, it is dueAt
+// descending when not given.
+func (input ListInput) byCreatedAt() bool {
+	return input.OrderBy == ListOrderCreatedAt
+}
+
+func (input ListInput) ascending() bool {
+	return input.Direction == ListDirectionAsc
+}
+
+// orderValue is the value of the schedule the list is ordered by.
+func (input ListInput) orderValue(s *Schedule) int64 {
+	if input.byCreatedAt() {
+		return s.CreatedAt.Unix()
+	}
+
+	return s.DueAt.Unix()
+}
+
+// startValue is the value of the start key the list is ordered by.
+func (input ListInput) startValue() *int64 {
+	return input.keyValue(input.StartKey)
+}
+
+// keyValue is the value of the key the list is ordered by.
+func (input ListInput) keyValue(key *ListKey) *int64 {
+	if input.byCreatedAt() {
+		return key.CreatedAt
+	}
+
+	return key.DueAt
+}
+
+// precedes tells the position of x comes before the one of y in the list,
+// schedules of the same value are in the order of their id.
+func (input ListInput) precedes(
+	x int64,
+	xID string,
+	y int64,
+	yID string) bool {
+
+	if input.ascending() {
+		if x == y {
+			return xID < yID
+		}
+
+		return x < y
+	}
+
+	if x == y {
+		return xID > yID
+	}
+
+	return x > y
+}
+
+// nextKey is the key a list continues after the schedule with.
+func (input ListInput) nextKey(s *Schedule) *ListKey {
+	value := input.orderValue(s)
+	key := &ListKey{ID: s.ID}
+
+	if input.byCreatedAt() {
+		key.CreatedAt = &value
+	} else {
+		key.DueAt = &value
+	}
+
+	if input.Status != "" && !input.byCreatedAt() {
+		key.Status = &s.Status
+	}
+
+	return key
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// Migrate applies the bundled schema migrations that are not yet recorded in
+// the schema_migrations table, each one in its own transaction.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY
+		)`); err != nil {
+		return err
+	}
+
+	entries, err := postgresMigrations.ReadDir("migrations/postgres")
+
+	if err != nil {
+		return err
+	}
+
+	var versions []string
+
+	for _, entry := range entries {
+		versions = append(versions, entry.Name())
+	}
+
+	sort.Strings(versions)
+
+	for _, version := range versions {
+		if err = migrate(ctx, db, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrate(ctx context.Context, db *sql.DB, version string) error {
+	statement, err := postgresMigrations.ReadFile(
+		path.Join("migrations/postgres", version))
+
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var applied int
+
+	if err = tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = $1",
+		version).Scan(&applied); err != nil {
+		return err
+	}
+
+	if applied > 0 {
+		return nil
+	}
+
+	if _, err = tx.ExecContext(
+		ctx,
+		strings.TrimSpace(string(statement))); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO schema_migrations (version) VALUES ($1)",
+		version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
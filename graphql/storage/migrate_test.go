@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migrate", func() {
+	var (
+		conn *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		conn, mock, err = sqlmock.New()
+		Expect(err).To(BeNil())
+
+		mock.ExpectExec(regexp.QuoteMeta(
+			"CREATE TABLE IF NOT EXISTS schema_migrations")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+	})
+
+	Context("pending migrations", func() {
+		var err error
+
+		BeforeEach(func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"SELECT COUNT(*) FROM schema_migrations")).
+				WithArgs("0001_create_schedules.sql").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"CREATE TABLE IF NOT EXISTS schedules")).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"INSERT INTO schema_migrations")).
+				WithArgs("0001_create_schedules.sql").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"SELECT COUNT(*) FROM schema_migrations")).
+				WithArgs("0002_index_created_at.sql").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"CREATE INDEX IF NOT EXISTS ix_schedules_status_created_at")).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"INSERT INTO schema_migrations")).
+				WithArgs("0002_index_created_at.sql").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"SELECT COUNT(*) FROM schema_migrations")).
+				WithArgs("0003_create_schedule_stats.sql").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"CREATE TABLE IF NOT EXISTS schedule_stats")).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(
+				"INSERT INTO schema_migrations")).
+				WithArgs("0003_create_schedule_stats.sql").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			err = Migrate(context.TODO(), conn)
+		})
+
+		It("applies and records migrations", func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("applied migrations", func() {
+		var err error
+
+		BeforeEach(func() {
+			for i := 0; i < 3; i++ {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT COUNT(*) FROM schema_migrations")).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			}
+
+			err = Migrate(context.TODO(), conn)
+		})
+
+		It("skips migrations", func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+})
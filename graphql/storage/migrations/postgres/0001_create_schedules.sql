@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS schedules (
+  id           TEXT PRIMARY KEY,
+  due_at       BIGINT NOT NULL,
+  url          TEXT NOT NULL,
+  method       TEXT NOT NULL,
+  headers      JSONB,
+  body         TEXT,
+  status       TEXT NOT NULL,
+  started_at   BIGINT,
+  completed_at BIGINT,
+  canceled_at  BIGINT,
+  result       TEXT,
+  reason       TEXT,
+  lag          BIGINT,
+  deadline     BIGINT,
+  created_at   BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ix_schedules_status_due_at
+  ON schedules (status, due_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS ix_schedules_due_at
+  ON schedules (due_at DESC, id DESC);
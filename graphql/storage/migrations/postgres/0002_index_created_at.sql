@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS ix_schedules_status_created_at
+  ON schedules (status, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS ix_schedules_created_at
+  ON schedules (created_at DESC, id DESC);
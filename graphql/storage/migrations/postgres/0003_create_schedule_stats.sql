@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS schedule_stats (
+  bucket BIGINT NOT NULL,
+  status TEXT NOT NULL,
+  host   TEXT NOT NULL,
+  count  BIGINT NOT NULL,
+  PRIMARY KEY (bucket, status, host)
+);
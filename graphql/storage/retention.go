@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+)
+
+// retention is how long a schedule is kept once it reached the terminal
+// status, per SCHEDULER_RETENTION_<STATUS>_SECONDS. Zero keeps it forever.
+func retention(status string) int64 {
+	seconds, err := strconv.ParseInt(
+		os.Getenv("SCHEDULER_RETENTION_"+status+"_SECONDS"), 10, 64)
+
+	if err != nil || seconds < 1 {
+		return 0
+	}
+
+	return seconds
+}
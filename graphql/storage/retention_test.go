@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention", func() {
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_RETENTION_CANCELED_SECONDS")
+	})
+
+	It("keeps forever by default", func() {
+		Expect(retention(ScheduleStatusCanceled)).To(BeZero())
+	})
+
+	It("reads retention of status from env", func() {
+		_ = os.Setenv("SCHEDULER_RETENTION_CANCELED_SECONDS", "3600")
+
+		Expect(retention(ScheduleStatusCanceled)).To(BeEquivalentTo(3600))
+	})
+
+	It("ignores invalid retention", func() {
+		_ = os.Setenv("SCHEDULER_RETENTION_CANCELED_SECONDS", "-1")
+
+		Expect(retention(ScheduleStatusCanceled)).To(BeZero())
+	})
+})
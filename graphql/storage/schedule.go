@@ -14,5 +14,13 @@ type Schedule struct {
 	CompletedAt *time.Time        `json:"completedAt,omitempty" dynamodbav:"completedAt,unixtime,omitempty"`
 	CanceledAt  *time.Time        `json:"canceledAt,omitempty" dynamodbav:"canceledAt,unixtime,omitempty"`
 	Result      *string           `json:"result,omitempty" dynamodbav:"result,omitempty"`
+	Reason      *string           `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	Lag         *int64            `json:"lag,omitempty" dynamodbav:"lag,omitempty"`
+	Deadline    *time.Time        `json:"deadline,omitempty" dynamodbav:"deadline,unixtime,omitempty"`
 	CreatedAt   time.Time         `json:"createdAt" dynamodbav:"createdAt,unixtime"`
+
+	// BodyRef and ResultRef are the blob keys of a body and result that were
+	// too large for the item.
+	BodyRef   *string `json:"-" dynamodbav:"bodyRef,omitempty"`
+	ResultRef *string `json:"-" dynamodbav:"resultRef,omitempty"`
 }
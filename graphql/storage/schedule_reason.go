@@ -0,0 +1,6 @@
+package storage
+
+const (
+	ScheduleReasonDeferred    = "DEFERRED"
+	ScheduleReasonInterrupted = "INTERRUPTED"
+)
@@ -6,4 +6,5 @@ const (
 	ScheduleStatusSucceeded = "SUCCEEDED"
 	ScheduleStatusCanceled  = "CANCELED"
 	ScheduleStatusFailed    = "FAILED"
+	ScheduleStatusExpired   = "EXPIRED"
 )
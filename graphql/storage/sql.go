@@ -0,0 +1,455 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+)
+
+const scheduleColumns = `id, due_at, url, method, headers, body, status,
+	started_at, completed_at, canceled_at, result, reason, lag, deadline,
+	created_at`
+
+type SQL struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+func NewPostgres(db *sql.DB, clock clock.Clock) *SQL {
+	return &SQL{db, clock}
+}
+
+// NewSQLite uses the same statements as Postgres, both understand them.
+func NewSQLite(db *sql.DB, clock clock.Clock) *SQL {
+	return &SQL{db, clock}
+}
+
+func (srv *SQL) Create(
+	ctx context.Context,
+	input CreateInput) (string, error) {
+
+	id, err := generateID()
+
+	if err != nil {
+		return "", err
+	}
+
+	var headers []byte
+
+	if len(input.Headers) > 0 {
+		if headers, err = json.Marshal(input.Headers); err != nil {
+			return "", err
+		}
+	}
+
+	var deadline *int64
+
+	if input.Deadline != nil {
+		value := input.Deadline.Unix()
+		deadline = &value
+	}
+
+	_, err = srv.db.ExecContext(
+		ctx,
+		`INSERT INTO schedules
+			(id, due_at, url, method, headers, body, status, deadline, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		id,
+		input.DueAt.Unix(),
+		input.URL,
+		input.Method,
+		nullableBytes(headers),
+		nullableString(input.Body),
+		ScheduleStatusIdle,
+		deadline,
+		srv.clock.Now().Unix())
+
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (srv *SQL) Cancel(ctx context.Context, id string) (bool, error) {
+	now := srv.clock.Now()
+
+	tx, err := srv.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var url string
+
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE schedules SET status = $1, canceled_at = $2
+		WHERE id = $3 AND status = $4
+		RETURNING url`,
+		ScheduleStatusCanceled,
+		now.Unix(),
+		id,
+		ScheduleStatusIdle).Scan(&url)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err = stats.NewSQL(tx).Add(ctx, []stats.Transition{{
+		Status: ScheduleStatusCanceled,
+		URL:    url,
+		At:     now,
+	}}); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (srv *SQL) Get(ctx context.Context, id string) (*Schedule, error) {
+	row := srv.db.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM schedules WHERE id = $1", scheduleColumns),
+		id)
+
+	s, err := scanSchedule(row)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (srv *SQL) GetMany(
+	ctx context.Context,
+	ids []string) ([]*Schedule, error) {
+
+	unique := distinct(ids)
+	schedules := make([]*Schedule, len(ids))
+
+	if len(unique) == 0 {
+		return schedules, nil
+	}
+
+	placeholders := make([]string, len(unique))
+	args := make([]interface{}, len(unique))
+
+	for i, id := range unique {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	rows, err := srv.db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			"SELECT %s FROM schedules WHERE id IN (%s)",
+			scheduleColumns,
+			strings.Join(placeholders, ", ")),
+		args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	found := make(map[string]*Schedule, len(unique))
+
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		found[s.ID] = s
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		schedules[i] = found[id]
+	}
+
+	return schedules, nil
+}
+
+func (srv *SQL) List(ctx context.Context, input ListInput) (*List, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	arg := func(v interface{}) string {
+		args = append(args, v)
+
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if input.Status != "" {
+		conditions = append(conditions, "status = "+arg(input.Status))
+	}
+
+	if input.DueAt != nil {
+		conditions = append(
+			conditions,
+			fmt.Sprintf(
+				"due_at BETWEEN %s AND %s",
+				arg(input.DueAt.From.Unix()),
+				arg(input.DueAt.To.Unix())))
+	}
+
+	column, direction, comparison := "due_at", "DESC", "<"
+
+	if input.byCreatedAt() {
+		column = "created_at"
+	}
+
+	if input.ascending() {
+		direction, comparison = "ASC", ">"
+	}
+
+	if input.StartKey != nil && input.startValue() != nil {
+		conditions = append(
+			conditions,
+			fmt.Sprintf(
+				"(%s, id) %s (%s, %s)",
+				column,
+				comparison,
+				arg(*input.startValue()),
+				arg(input.StartKey.ID)))
+	}
+
+	statement := fmt.Sprintf("SELECT %s FROM schedules", scheduleColumns)
+
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// One extra row tells whether there is a next page without a count.
+	statement += fmt.Sprintf(
+		" ORDER BY %s %s, id %s LIMIT %s",
+		column,
+		direction,
+		direction,
+		arg(input.Limit+1))
+
+	rows, err := srv.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var schedules []*Schedule
+
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var nextKey *ListKey
+
+	if int64(len(schedules)) > input.Limit {
+		schedules = schedules[:input.Limit]
+		nextKey = input.nextKey(schedules[len(schedules)-1])
+	}
+
+	return &List{Schedules: schedules, NextKey: nextKey}, nil
+}
+
+func (srv *SQL) Lateness(
+	ctx context.Context,
+	input LatenessInput) (*Lateness, error) {
+
+	rows, err := srv.db.QueryContext(
+		ctx,
+		`SELECT lag, status FROM schedules
+		WHERE due_at BETWEEN $1 AND $2 AND (lag IS NOT NULL OR status = $3)`,
+		input.DueAt.From.Unix(),
+		input.DueAt.To.Unix(),
+		ScheduleStatusExpired)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		lags    []int64
+		expired int64
+	)
+
+	for rows.Next() {
+		var (
+			lag    sql.NullInt64
+			status string
+		)
+
+		if err = rows.Scan(&lag, &status); err != nil {
+			return nil, err
+		}
+
+		if status == ScheduleStatusExpired {
+			expired++
+		}
+
+		if lag.Valid {
+			lags = append(lags, lag.Int64)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return newLateness(lags, expired), nil
+}
+
+func (srv *SQL) Stats(ctx context.Context, input StatsInput) (*Stats, error) {
+	counts, err := stats.NewSQL(srv.db).Read(
+		ctx,
+		input.At.From,
+		input.At.To)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newStats(counts), nil
+}
+
+type scanner interface {
+	Scan(...interface{}) error
+}
+
+func scanSchedule(row scanner) (*Schedule, error) {
+	var (
+		s           Schedule
+		dueAt       int64
+		headers     []byte
+		body        sql.NullString
+		startedAt   sql.NullInt64
+		completedAt sql.NullInt64
+		canceledAt  sql.NullInt64
+		result      sql.NullString
+		reason      sql.NullString
+		lag         sql.NullInt64
+		deadline    sql.NullInt64
+		createdAt   int64
+	)
+
+	if err := row.Scan(
+		&s.ID,
+		&dueAt,
+		&s.URL,
+		&s.Method,
+		&headers,
+		&body,
+		&s.Status,
+		&startedAt,
+		&completedAt,
+		&canceledAt,
+		&result,
+		&reason,
+		&lag,
+		&deadline,
+		&createdAt); err != nil {
+		return nil, err
+	}
+
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &s.Headers); err != nil {
+			return nil, err
+		}
+	}
+
+	s.DueAt = time.Unix(dueAt, 0)
+	s.Body = stringOrNil(body)
+	s.StartedAt = timeOrNil(startedAt)
+	s.CompletedAt = timeOrNil(completedAt)
+	s.CanceledAt = timeOrNil(canceledAt)
+	s.Result = stringOrNil(result)
+	s.Reason = stringOrNil(reason)
+	s.Deadline = timeOrNil(deadline)
+	s.CreatedAt = time.Unix(createdAt, 0)
+
+	if lag.Valid {
+		s.Lag = &lag.Int64
+	}
+
+	return &s, nil
+}
+
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullableBytes(value []byte) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+
+	return value
+}
+
+func stringOrNil(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.String
+}
+
+func timeOrNil(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+
+	t := time.Unix(value.Int64, 0)
+
+	return &t
+}
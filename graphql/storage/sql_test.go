@@ -0,0 +1,432 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL", func() {
+	const (
+		id     = "1234567890"
+		url    = "https://foo.bar/do"
+		method = "POST"
+	)
+
+	var (
+		conn *sql.DB
+		mock sqlmock.Sqlmock
+		db   *SQL
+	)
+
+	now := time.Unix(1600000000, 0)
+
+	columns := []string{
+		"id", "due_at", "url", "method", "headers", "body", "status",
+		"started_at", "completed_at", "canceled_at", "result", "reason", "lag",
+		"deadline", "created_at",
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		conn, mock, err = sqlmock.New()
+		Expect(err).To(BeNil())
+
+		db = NewPostgres(conn, clock.NewFake(now))
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		_ = conn.Close()
+	})
+
+	Describe("Create", func() {
+		Describe("success", func() {
+			var (
+				res string
+				err error
+			)
+
+			BeforeEach(func() {
+				dueAt := time.Now().Add(time.Minute)
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schedules")).
+					WithArgs(
+						sqlmock.AnyArg(),
+						dueAt.Unix(),
+						url,
+						method,
+						[]byte(`{"accept":"application/json"}`),
+						sql.NullString{},
+						ScheduleStatusIdle,
+						nil,
+						now.Unix()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				res, err = db.Create(context.TODO(), CreateInput{
+					DueAt:  dueAt,
+					URL:    url,
+					Method: method,
+					Headers: map[string]string{
+						"accept": "application/json",
+					},
+				})
+			})
+
+			It("returns new id", func() {
+				Expect(res).NotTo(Equal(""))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			var (
+				res string
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schedules")).
+					WillReturnError(fmt.Errorf("insert error"))
+
+				res, err = db.Create(context.TODO(), CreateInput{
+					DueAt:  time.Now().Add(time.Minute),
+					URL:    url,
+					Method: method,
+				})
+			})
+
+			It("does not return id", func() {
+				Expect(res).To(Equal(""))
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Cancel", func() {
+		Context("idle schedule", func() {
+			var (
+				res bool
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE schedules")).
+					WithArgs(
+						ScheduleStatusCanceled,
+						now.Unix(),
+						id,
+						ScheduleStatusIdle).
+					WillReturnRows(sqlmock.NewRows([]string{"url"}).AddRow(url))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schedule_stats")).
+					WithArgs(
+						int64(1599998400),
+						ScheduleStatusCanceled,
+						"foo.bar",
+						int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				res, err = db.Cancel(context.TODO(), id)
+			})
+
+			It("returns success", func() {
+				Expect(res).To(BeTrue())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("not idle schedule", func() {
+			var (
+				res bool
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE schedules")).
+					WillReturnRows(sqlmock.NewRows([]string{"url"}))
+				mock.ExpectRollback()
+
+				res, err = db.Cancel(context.TODO(), id)
+			})
+
+			It("returns fail", func() {
+				Expect(res).To(BeFalse())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		Context("existing schedule", func() {
+			var (
+				res *Schedule
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM schedules WHERE id = $1")).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(
+						id, 9876543, url, method,
+						[]byte(`{"accept":"application/json"}`), nil,
+						ScheduleStatusSucceeded, 9876544, 9876545, nil,
+						"dummy result", nil, 1200, nil, 343334232))
+
+				res, err = db.Get(context.TODO(), id)
+			})
+
+			It("returns matching schedule", func() {
+				Expect(res.ID).To(Equal(id))
+				Expect(res.DueAt.Unix()).To(BeEquivalentTo(9876543))
+				Expect(res.Headers["accept"]).To(Equal("application/json"))
+				Expect(res.Body).To(BeNil())
+				Expect(res.Status).To(Equal(ScheduleStatusSucceeded))
+				Expect(res.StartedAt.Unix()).To(BeEquivalentTo(9876544))
+				Expect(res.CanceledAt).To(BeNil())
+				Expect(*res.Result).To(Equal("dummy result"))
+				Expect(*res.Lag).To(BeEquivalentTo(1200))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("non existent schedule", func() {
+			var (
+				res *Schedule
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM schedules WHERE id = $1")).
+					WillReturnRows(sqlmock.NewRows(columns))
+
+				res, err = db.Get(context.TODO(), id)
+			})
+
+			It("does not return any schedule", func() {
+				Expect(res).To(BeNil())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GetMany", func() {
+		Context("some existing schedules", func() {
+			var (
+				res []*Schedule
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectQuery(
+					regexp.QuoteMeta("FROM schedules WHERE id IN ($1, $2)")).
+					WithArgs("1", "2").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(
+						"2", 9876543, url, method, nil, nil,
+						ScheduleStatusIdle, nil, nil, nil, nil, nil, nil, nil,
+						343334232))
+
+				res, err = db.GetMany(context.TODO(), []string{"1", "2", "1"})
+			})
+
+			It("returns schedules in the order of the ids", func() {
+				Expect(res).To(HaveLen(3))
+				Expect(res[0]).To(BeNil())
+				Expect(res[1].ID).To(Equal("2"))
+				Expect(res[2]).To(BeNil())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("no ids", func() {
+			It("does not query", func() {
+				res, err := db.GetMany(context.TODO(), nil)
+
+				Expect(res).To(BeEmpty())
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("List", func() {
+		Context("with status, dueAt and start key", func() {
+			var (
+				res *List
+				err error
+			)
+
+			BeforeEach(func() {
+				from := time.Now().Add(-time.Hour)
+				to := time.Now()
+				startDueAt := int64(9876600)
+
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"WHERE status = $1 AND due_at BETWEEN $2 AND $3 AND "+
+						"(due_at, id) < ($4, $5) "+
+						"ORDER BY due_at DESC, id DESC LIMIT $6")).
+					WithArgs(
+						ScheduleStatusIdle,
+						from.Unix(),
+						to.Unix(),
+						startDueAt,
+						"6568",
+						int64(2)).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(
+							"2", 9876543, url, method, nil, nil,
+							ScheduleStatusIdle, nil, nil, nil, nil, nil, nil,
+							nil, 343334232).
+						AddRow(
+							"1", 9876540, url, method, nil, nil,
+							ScheduleStatusIdle, nil, nil, nil, nil, nil, nil,
+							nil, 343334232))
+
+				res, err = db.List(context.TODO(), ListInput{
+					Status:   ScheduleStatusIdle,
+					DueAt:    &DateRange{From: from, To: to},
+					StartKey: &ListKey{ID: "6568", DueAt: &startDueAt},
+					Limit:    1,
+				})
+			})
+
+			It("returns up to limit", func() {
+				Expect(res.Schedules).To(HaveLen(1))
+			})
+
+			It("returns last schedule as next key", func() {
+				Expect(res.NextKey.ID).To(Equal("2"))
+				Expect(*res.NextKey.DueAt).To(BeEquivalentTo(9876543))
+				Expect(*res.NextKey.Status).To(Equal(ScheduleStatusIdle))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("by createdAt ascending", func() {
+			var res *List
+
+			BeforeEach(func() {
+				startCreatedAt := int64(343334200)
+
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"WHERE (created_at, id) > ($1, $2) "+
+						"ORDER BY created_at ASC, id ASC LIMIT $3")).
+					WithArgs(startCreatedAt, "6568", int64(2)).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(
+							"1", 9876540, url, method, nil, nil,
+							ScheduleStatusIdle, nil, nil, nil, nil, nil, nil,
+							nil, 343334231).
+						AddRow(
+							"2", 9876543, url, method, nil, nil,
+							ScheduleStatusIdle, nil, nil, nil, nil, nil, nil,
+							nil, 343334232))
+
+				res, _ = db.List(context.TODO(), ListInput{
+					OrderBy:   ListOrderCreatedAt,
+					Direction: ListDirectionAsc,
+					StartKey: &ListKey{
+						ID:        "6568",
+						CreatedAt: &startCreatedAt,
+					},
+					Limit: 1,
+				})
+			})
+
+			It("returns last schedule as next key", func() {
+				Expect(res.NextKey.ID).To(Equal("1"))
+				Expect(*res.NextKey.CreatedAt).To(BeEquivalentTo(343334231))
+				Expect(res.NextKey.DueAt).To(BeNil())
+			})
+		})
+
+		Context("last page", func() {
+			var res *List
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"FROM schedules ORDER BY due_at DESC, id DESC LIMIT $1")).
+					WithArgs(int64(26)).
+					WillReturnRows(sqlmock.NewRows(columns))
+
+				res, _ = db.List(context.TODO(), ListInput{Limit: 25})
+			})
+
+			It("does not return next key", func() {
+				Expect(res.NextKey).To(BeNil())
+			})
+		})
+	})
+
+	Describe("Lateness", func() {
+		var (
+			res *Lateness
+			err error
+		)
+
+		BeforeEach(func() {
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"SELECT lag, status FROM schedules")).
+				WithArgs(
+					sqlmock.AnyArg(),
+					sqlmock.AnyArg(),
+					ScheduleStatusExpired).
+				WillReturnRows(sqlmock.NewRows([]string{"lag", "status"}).
+					AddRow(1200, ScheduleStatusSucceeded).
+					AddRow(800, ScheduleStatusFailed).
+					AddRow(nil, ScheduleStatusExpired))
+
+			res, err = db.Lateness(context.TODO(), LatenessInput{
+				DueAt: DateRange{
+					From: time.Now().Add(-time.Hour),
+					To:   time.Now(),
+				},
+			})
+		})
+
+		It("returns statistics", func() {
+			Expect(res.Count).To(BeEquivalentTo(2))
+			Expect(res.Expired).To(BeEquivalentTo(1))
+			Expect(res.Average).To(BeEquivalentTo(1000))
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+)
+
+// ErrNoStats is returned for stats of a storage that does not count them.
+var ErrNoStats = errors.New("stats are not kept")
+
+type StatsInput struct {
+	At DateRange `json:"at"`
+}
+
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+type HostCount struct {
+	Host   string `json:"host"`
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+type BucketCount struct {
+	At     time.Time `json:"at"`
+	Status string    `json:"status"`
+	Count  int64     `json:"count"`
+}
+
+// Stats are the number of schedules that reached each status within a
+// range, in total, per destination host and per hour.
+type Stats struct {
+	Statuses []StatusCount `json:"statuses"`
+	Hosts    []HostCount   `json:"hosts"`
+	Buckets  []BucketCount `json:"buckets"`
+}
+
+func newStats(counts []stats.Count) *Stats {
+	statuses := make(map[string]int64)
+	hosts := make(map[HostCount]int64)
+	buckets := make(map[BucketCount]int64)
+
+	for _, c := range counts {
+		statuses[c.Status] += c.Count
+		hosts[HostCount{Host: c.Host, Status: c.Status}] += c.Count
+		buckets[BucketCount{At: c.Bucket, Status: c.Status}] += c.Count
+	}
+
+	s := Stats{
+		Statuses: []StatusCount{},
+		Hosts:    []HostCount{},
+		Buckets:  []BucketCount{},
+	}
+
+	for status, count := range statuses {
+		s.Statuses = append(s.Statuses, StatusCount{status, count})
+	}
+
+	for h, count := range hosts {
+		h.Count = count
+		s.Hosts = append(s.Hosts, h)
+	}
+
+	for b, count := range buckets {
+		b.Count = count
+		s.Buckets = append(s.Buckets, b)
+	}
+
+	sort.Slice(s.Statuses, func(i, j int) bool {
+		return s.Statuses[i].Status < s.Statuses[j].Status
+	})
+
+	sort.Slice(s.Hosts, func(i, j int) bool {
+		if s.Hosts[i].Host != s.Hosts[j].Host {
+			return s.Hosts[i].Host < s.Hosts[j].Host
+		}
+
+		return s.Hosts[i].Status < s.Hosts[j].Status
+	})
+
+	sort.Slice(s.Buckets, func(i, j int) bool {
+		if !s.Buckets[i].At.Equal(s.Buckets[j].At) {
+			return s.Buckets[i].At.Before(s.Buckets[j].At)
+		}
+
+		return s.Buckets[i].Status < s.Buckets[j].Status
+	})
+
+	return &s
+}
+
+// statsTableName is the DynamoDB table of the counts, none are kept
+// without one.
+func statsTableName() string {
+	return os.Getenv("SCHEDULER_STATS_TABLE_NAME")
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	Context("no counts", func() {
+		var s *Stats
+
+		BeforeEach(func() {
+			s = newStats(nil)
+		})
+
+		It("returns empty counts", func() {
+			Expect(s.Statuses).To(BeEmpty())
+			Expect(s.Hosts).To(BeEmpty())
+			Expect(s.Buckets).To(BeEmpty())
+		})
+	})
+
+	Context("counts", func() {
+		var s *Stats
+
+		first, second := time.Unix(1599998400, 0), time.Unix(1600002000, 0)
+
+		BeforeEach(func() {
+			s = newStats([]stats.Count{
+				{Bucket: first, Status: ScheduleStatusFailed, Host: "foo.bar", Count: 1},
+				{Bucket: first, Status: ScheduleStatusIdle, Host: "baz.qux", Count: 2},
+				{Bucket: first, Status: ScheduleStatusIdle, Host: "foo.bar", Count: 3},
+				{Bucket: second, Status: ScheduleStatusIdle, Host: "foo.bar", Count: 4},
+			})
+		})
+
+		It("sums per status", func() {
+			Expect(s.Statuses).To(Equal([]StatusCount{
+				{ScheduleStatusFailed, 1},
+				{ScheduleStatusIdle, 9},
+			}))
+		})
+
+		It("sums per host and status", func() {
+			Expect(s.Hosts).To(Equal([]HostCount{
+				{"baz.qux", ScheduleStatusIdle, 2},
+				{"foo.bar", ScheduleStatusFailed, 1},
+				{"foo.bar", ScheduleStatusIdle, 7},
+			}))
+		})
+
+		It("sums per bucket and status", func() {
+			Expect(s.Buckets).To(Equal([]BucketCount{
+				{first, ScheduleStatusFailed, 1},
+				{first, ScheduleStatusIdle, 5},
+				{second, ScheduleStatusIdle, 4},
+			}))
+		})
+	})
+})
@@ -0,0 +1,511 @@
+// Package storagetest is a conformance suite every storage.Storage backend
+// runs to prove it behaves like the DynamoDB one.
+//
+//	var _ = storagetest.DescribeStorage("SQL", func() storagetest.Backend {
+//		return storagetest.Backend{
+//			Storage: storage.NewSQLite(db, clock.System),
+//			Collect: collector.NewSQLite(db, clock.System).Update,
+//		}
+//	})
+package storagetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Backend is the storage under test, Collect runs the collector against the
+// same data, the claim specs are skipped without it.
+type Backend struct {
+	Storage storage.Storage
+	Collect func(context.Context) error
+}
+
+// DescribeStorage registers the specs, setup is called before each of them
+// with an empty backend.
+func DescribeStorage(name string, setup func() Backend) bool {
+	return Describe(name+" conformance", func() {
+		var (
+			backend Backend
+			now     time.Time
+		)
+
+		create := func(offset time.Duration) string {
+			id, err := backend.Storage.Create(
+				context.TODO(),
+				storage.CreateInput{
+					DueAt:  now.Add(offset),
+					URL:    "https://foo.bar/do",
+					Method: "POST",
+				})
+
+			Expect(err).To(BeNil())
+			Expect(id).NotTo(BeEmpty())
+
+			return id
+		}
+
+		get := func(id string) *storage.Schedule {
+			s, err := backend.Storage.Get(context.TODO(), id)
+
+			Expect(err).To(BeNil())
+			Expect(s).NotTo(BeNil())
+
+			return s
+		}
+
+		list := func(input storage.ListInput) []string {
+			var ids []string
+
+			for {
+				l, err := backend.Storage.List(context.TODO(), input)
+
+				Expect(err).To(BeNil())
+				Expect(int64(len(l.Schedules))).To(
+					BeNumerically("<=", input.Limit))
+
+				for _, s := range l.Schedules {
+					ids = append(ids, s.ID)
+				}
+
+				if l.NextKey == nil {
+					return ids
+				}
+
+				input.StartKey = l.NextKey
+			}
+		}
+
+		statuses := func() map[string]int64 {
+			stats, err := backend.Storage.Stats(
+				context.TODO(),
+				storage.StatsInput{At: storage.DateRange{
+					From: now.Add(-time.Hour),
+					To:   now.Add(time.Hour),
+				}})
+
+			Expect(err).To(BeNil())
+
+			counts := make(map[string]int64)
+
+			for _, s := range stats.Statuses {
+				counts[s.Status] = s.Count
+			}
+
+			return counts
+		}
+
+		collect := func() {
+			if backend.Collect == nil {
+				Skip("backend has no collector")
+			}
+
+			Expect(backend.Collect(context.TODO())).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			backend = setup()
+			now = time.Now().Truncate(time.Second)
+		})
+
+		Describe("Create", func() {
+			It("round trips through Get", func() {
+				body := "{}"
+				deadline := now.Add(time.Hour)
+
+				id, err := backend.Storage.Create(
+					context.TODO(),
+					storage.CreateInput{
+						DueAt:    now.Add(time.Minute),
+						URL:      "https://foo.bar/do",
+						Method:   "PUT",
+						Headers:  map[string]string{"X-Foo": "bar"},
+						Body:     body,
+						Deadline: &deadline,
+					})
+				Expect(err).To(BeNil())
+
+				s := get(id)
+
+				Expect(s.ID).To(Equal(id))
+				Expect(s.DueAt.Unix()).To(Equal(now.Add(time.Minute).Unix()))
+				Expect(s.URL).To(Equal("https://foo.bar/do"))
+				Expect(s.Method).To(Equal("PUT"))
+				Expect(s.Headers).To(Equal(map[string]string{"X-Foo": "bar"}))
+				Expect(*s.Body).To(Equal(body))
+				Expect(s.Status).To(Equal(storage.ScheduleStatusIdle))
+				Expect(s.Deadline.Unix()).To(Equal(deadline.Unix()))
+				Expect(s.CreatedAt.Unix()).To(BeNumerically(">=", now.Unix()))
+				Expect(s.StartedAt).To(BeNil())
+				Expect(s.CompletedAt).To(BeNil())
+				Expect(s.CanceledAt).To(BeNil())
+			})
+
+			It("generates unique ids", func() {
+				Expect(create(0)).NotTo(Equal(create(0)))
+			})
+		})
+
+		Describe("Get", func() {
+			It("returns nil for unknown schedule", func() {
+				s, err := backend.Storage.Get(context.TODO(), "unknown")
+
+				Expect(err).To(BeNil())
+				Expect(s).To(BeNil())
+			})
+		})
+
+		Describe("GetMany", func() {
+			It("returns schedules in the order of the ids", func() {
+				first, second := create(time.Minute), create(time.Hour)
+
+				schedules, err := backend.Storage.GetMany(
+					context.TODO(),
+					[]string{second, "unknown", first, second})
+
+				Expect(err).To(BeNil())
+				Expect(schedules).To(HaveLen(4))
+				Expect(schedules[0].ID).To(Equal(second))
+				Expect(schedules[1]).To(BeNil())
+				Expect(schedules[2].ID).To(Equal(first))
+				Expect(schedules[3].ID).To(Equal(second))
+				Expect(schedules[2].URL).To(Equal("https://foo.bar/do"))
+			})
+
+			It("returns more than a batch of schedules", func() {
+				ids := make([]string, 120)
+
+				for i := range ids {
+					ids[i] = create(time.Duration(i) * time.Minute)
+				}
+
+				schedules, err := backend.Storage.GetMany(context.TODO(), ids)
+
+				Expect(err).To(BeNil())
+				Expect(schedules).To(HaveLen(len(ids)))
+
+				for i, s := range schedules {
+					Expect(s.ID).To(Equal(ids[i]))
+				}
+			})
+
+			It("returns empty list without ids", func() {
+				schedules, err := backend.Storage.GetMany(context.TODO(), nil)
+
+				Expect(err).To(BeNil())
+				Expect(schedules).To(BeEmpty())
+			})
+		})
+
+		Describe("Cancel", func() {
+			It("cancels idle schedule", func() {
+				id := create(time.Hour)
+
+				canceled, err := backend.Storage.Cancel(context.TODO(), id)
+				Expect(err).To(BeNil())
+				Expect(canceled).To(BeTrue())
+
+				s := get(id)
+
+				Expect(s.Status).To(Equal(storage.ScheduleStatusCanceled))
+				Expect(s.CanceledAt).NotTo(BeNil())
+			})
+
+			It("cancels only once", func() {
+				id := create(time.Hour)
+
+				_, err := backend.Storage.Cancel(context.TODO(), id)
+				Expect(err).To(BeNil())
+
+				canceled, err := backend.Storage.Cancel(context.TODO(), id)
+				Expect(err).To(BeNil())
+				Expect(canceled).To(BeFalse())
+			})
+
+			It("does not cancel unknown schedule", func() {
+				canceled, err := backend.Storage.Cancel(
+					context.TODO(),
+					"unknown")
+
+				Expect(err).To(BeNil())
+				Expect(canceled).To(BeFalse())
+			})
+
+			It("does not cancel queued schedule", func() {
+				id := create(-time.Minute)
+
+				collect()
+
+				canceled, err := backend.Storage.Cancel(context.TODO(), id)
+				Expect(err).To(BeNil())
+				Expect(canceled).To(BeFalse())
+				Expect(get(id).Status).To(Equal(storage.ScheduleStatusQueued))
+			})
+		})
+
+		Describe("List", func() {
+			var ids []string
+
+			BeforeEach(func() {
+				ids = nil
+
+				for i := 0; i < 7; i++ {
+					ids = append(ids, create(time.Duration(i)*time.Minute))
+				}
+			})
+
+			latest := func() []string {
+				return []string{
+					ids[6], ids[5], ids[4], ids[3], ids[2], ids[1], ids[0],
+				}
+			}
+
+			It("lists latest due first", func() {
+				Expect(list(storage.ListInput{Limit: 100})).To(Equal(latest()))
+			})
+
+			It("pages with next key", func() {
+				for _, limit := range []int64{1, 2, 3, 7} {
+					Expect(list(storage.ListInput{Limit: limit})).To(
+						Equal(latest()))
+				}
+			})
+
+			It("pages schedules due at the same second", func() {
+				same := []string{create(time.Hour), create(time.Hour)}
+				all := list(storage.ListInput{Limit: 1})
+
+				Expect(all).To(HaveLen(len(ids) + len(same)))
+				Expect(all[:2]).To(ConsistOf(same))
+				Expect(all[2:]).To(Equal(latest()))
+			})
+
+			It("filters by status", func() {
+				for _, id := range []string{ids[1], ids[4]} {
+					_, err := backend.Storage.Cancel(context.TODO(), id)
+					Expect(err).To(BeNil())
+				}
+
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusCanceled,
+					Limit:  1,
+				})).To(Equal([]string{ids[4], ids[1]}))
+
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusIdle,
+					Limit:  2,
+				})).To(Equal([]string{ids[6], ids[5], ids[3], ids[2], ids[0]}))
+			})
+
+			It("filters by inclusive due range", func() {
+				Expect(list(storage.ListInput{
+					DueAt: &storage.DateRange{
+						From: now.Add(2 * time.Minute),
+						To:   now.Add(4 * time.Minute),
+					},
+					Limit: 2,
+				})).To(Equal([]string{ids[4], ids[3], ids[2]}))
+			})
+
+			It("filters by status and due range", func() {
+				_, err := backend.Storage.Cancel(context.TODO(), ids[3])
+				Expect(err).To(BeNil())
+
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusIdle,
+					DueAt: &storage.DateRange{
+						From: now.Add(2 * time.Minute),
+						To:   now.Add(4 * time.Minute),
+					},
+					Limit: 10,
+				})).To(Equal([]string{ids[4], ids[2]}))
+			})
+
+			It("lists earliest due first when ascending", func() {
+				Expect(list(storage.ListInput{
+					Direction: storage.ListDirectionAsc,
+					Limit:     3,
+				})).To(Equal(ids))
+
+				Expect(list(storage.ListInput{
+					Status:    storage.ScheduleStatusIdle,
+					Direction: storage.ListDirectionAsc,
+					Limit:     3,
+				})).To(Equal(ids))
+			})
+
+			It("pages by createdAt", func() {
+				for _, direction := range []string{
+					storage.ListDirectionDesc,
+					storage.ListDirectionAsc,
+				} {
+					all := list(storage.ListInput{
+						OrderBy:   storage.ListOrderCreatedAt,
+						Direction: direction,
+						Limit:     100,
+					})
+
+					Expect(all).To(ConsistOf(ids))
+
+					for i := 1; i < len(all); i++ {
+						x, y := get(all[i-1]).CreatedAt, get(all[i]).CreatedAt
+
+						if direction == storage.ListDirectionAsc {
+							Expect(x).NotTo(BeTemporally(">", y))
+						} else {
+							Expect(x).NotTo(BeTemporally("<", y))
+						}
+					}
+
+					Expect(list(storage.ListInput{
+						OrderBy:   storage.ListOrderCreatedAt,
+						Direction: direction,
+						Limit:     2,
+					})).To(Equal(all))
+				}
+			})
+
+			It("filters by createdAt", func() {
+				_, err := backend.Storage.Cancel(context.TODO(), ids[3])
+				Expect(err).To(BeNil())
+
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusIdle,
+					DueAt: &storage.DateRange{
+						From: now.Add(2 * time.Minute),
+						To:   now.Add(4 * time.Minute),
+					},
+					OrderBy: storage.ListOrderCreatedAt,
+					Limit:   1,
+				})).To(ConsistOf(ids[4], ids[2]))
+			})
+
+			It("returns empty list without matches", func() {
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusSucceeded,
+					Limit:  10,
+				})).To(BeEmpty())
+			})
+		})
+
+		Describe("Stats", func() {
+			It("counts canceled but not created schedules", func() {
+				create(time.Minute)
+				canceled := create(time.Hour)
+
+				_, err := backend.Storage.Cancel(context.TODO(), canceled)
+				Expect(err).To(BeNil())
+
+				Expect(statuses()).To(Equal(map[string]int64{
+					storage.ScheduleStatusCanceled: 1,
+				}))
+			})
+
+			It("counts per host and hour", func() {
+				_, err := backend.Storage.Cancel(
+					context.TODO(),
+					create(time.Minute))
+				Expect(err).To(BeNil())
+
+				stats, err := backend.Storage.Stats(
+					context.TODO(),
+					storage.StatsInput{At: storage.DateRange{
+						From: now.Add(-time.Hour),
+						To:   now.Add(time.Hour),
+					}})
+
+				Expect(err).To(BeNil())
+				Expect(stats.Hosts).To(Equal([]storage.HostCount{{
+					Host:   "foo.bar",
+					Status: storage.ScheduleStatusCanceled,
+					Count:  1,
+				}}))
+				Expect(stats.Buckets).To(HaveLen(1))
+				Expect(stats.Buckets[0].At).To(
+					BeTemporally("~", now, time.Hour))
+			})
+
+			It("does not count outside range", func() {
+				_, err := backend.Storage.Cancel(
+					context.TODO(),
+					create(time.Minute))
+				Expect(err).To(BeNil())
+
+				stats, err := backend.Storage.Stats(
+					context.TODO(),
+					storage.StatsInput{At: storage.DateRange{
+						From: now.Add(2 * time.Hour),
+						To:   now.Add(3 * time.Hour),
+					}})
+
+				Expect(err).To(BeNil())
+				Expect(stats.Statuses).To(BeEmpty())
+			})
+
+			It("counts cancel once", func() {
+				canceled := create(time.Minute)
+
+				_, err := backend.Storage.Cancel(context.TODO(), canceled)
+				Expect(err).To(BeNil())
+
+				_, err = backend.Storage.Cancel(context.TODO(), canceled)
+				Expect(err).To(BeNil())
+
+				Expect(statuses()).To(HaveKeyWithValue(
+					storage.ScheduleStatusCanceled,
+					int64(1)))
+			})
+		})
+
+		Describe("Collect", func() {
+			var due, future, canceled string
+
+			BeforeEach(func() {
+				due = create(-time.Minute)
+				future = create(24 * time.Hour)
+				canceled = create(-time.Minute)
+
+				_, err := backend.Storage.Cancel(context.TODO(), canceled)
+				Expect(err).To(BeNil())
+
+				collect()
+			})
+
+			It("queues due schedule", func() {
+				Expect(get(due).Status).To(Equal(storage.ScheduleStatusQueued))
+			})
+
+			It("leaves future schedule idle", func() {
+				Expect(get(future).Status).To(Equal(storage.ScheduleStatusIdle))
+			})
+
+			It("leaves canceled schedule", func() {
+				Expect(get(canceled).Status).To(
+					Equal(storage.ScheduleStatusCanceled))
+			})
+
+			It("lists queued schedule by status", func() {
+				Expect(list(storage.ListInput{
+					Status: storage.ScheduleStatusQueued,
+					Limit:  10,
+				})).To(Equal([]string{due}))
+			})
+
+			It("does not count queued schedule", func() {
+				Expect(statuses()).NotTo(HaveKey(storage.ScheduleStatusQueued))
+			})
+
+			It("does not claim queued schedule again", func() {
+				collect()
+
+				Expect(get(due).Status).To(Equal(storage.ScheduleStatusQueued))
+				Expect(get(future).Status).To(Equal(storage.ScheduleStatusIdle))
+			})
+		})
+	})
+}
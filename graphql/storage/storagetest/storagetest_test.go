@@ -0,0 +1,44 @@
+package storagetest
+
+import (
+	"os"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+)
+
+const (
+	table      = "scheduler_v1"
+	statsTable = "scheduler_stats_v1"
+)
+
+var _ = DescribeStorage("Database", func() Backend {
+	_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+	_ = os.Setenv("SCHEDULER_STATS_TABLE_NAME", statsTable)
+	_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+
+	return Backend{
+		Storage: storage.NewDatabase(
+			dynamotest.New(
+				dynamotest.SchedulerTable(table),
+				dynamotest.StatsTable(statsTable)),
+			clock.System,
+			nil),
+	}
+})
+
+var _ = DescribeStorage("Sharded Database", func() Backend {
+	_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+	_ = os.Setenv("SCHEDULER_STATS_TABLE_NAME", statsTable)
+	_ = os.Setenv("SCHEDULER_SHARD_COUNT", "4")
+
+	return Backend{
+		Storage: storage.NewDatabase(
+			dynamotest.New(
+				dynamotest.ShardedSchedulerTable(table),
+				dynamotest.StatsTable(statsTable)),
+			clock.System,
+			nil),
+	}
+})
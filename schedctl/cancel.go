@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// runCancel cancels the schedules, one that is no longer idle fails the
+// command after the rest are canceled.
+func runCancel(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("cancel")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: id is required", errUsage)
+	}
+
+	var failed []string
+
+	for _, id := range fs.Args() {
+		canceled, err := e.client.Cancel(ctx, id)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+
+		if !canceled {
+			failed = append(failed, id)
+			continue
+		}
+
+		_, _ = fmt.Fprintln(e.out, id)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("not idle: %v", failed)
+	}
+
+	return nil
+}
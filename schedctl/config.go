@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+const defaultEndpoint = "http://localhost:8080/graphql"
+
+// config is the file of the profiles, e.g.
+//
+//	default: prod
+//	profiles:
+//	  prod:
+//	    endpoint: https://example.com/v1/graphql
+//	    token: secret
+type config struct {
+	Default  string              `yaml:"default"`
+	Profiles map[string]*profile `yaml:"profiles"`
+}
+
+type profile struct {
+	Endpoint string `yaml:"endpoint"`
+	// Token is sent as the bearer authorization.
+	Token   string            `yaml:"token"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+func configPath() string {
+	if path := os.Getenv("SCHEDCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "schedctl", "config.yaml")
+}
+
+// loadProfile reads the named profile, the default one when there is no
+// name. Without a config file it is the local scheduler.
+func loadProfile(path string, name string) (*profile, error) {
+	var c config
+	buff, err := os.ReadFile(path)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(buff, &c); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = c.Default
+	}
+
+	if name == "" {
+		return &profile{Endpoint: defaultEndpoint}, nil
+	}
+
+	p, ok := c.Profiles[name]
+
+	if !ok {
+		return nil, fmt.Errorf("no profile %q", name)
+	}
+
+	if p.Endpoint == "" {
+		p.Endpoint = defaultEndpoint
+	}
+
+	return p, nil
+}
+
+func (p *profile) client() *client.Client {
+	var options []client.Option
+
+	for name, value := range p.Headers {
+		options = append(options, client.WithHeader(name, value))
+	}
+
+	if p.Token != "" {
+		options = append(
+			options,
+			client.WithHeader("Authorization", "Bearer "+p.Token))
+	}
+
+	return client.New(p.Endpoint, options...)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var path string
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "schedctl")
+		Expect(err).To(BeNil())
+
+		path = filepath.Join(dir, "config.yaml")
+
+		Expect(os.WriteFile(path, []byte(`default: local
+profiles:
+  local: {}
+  prod:
+    endpoint: https://foo.bar/v1/graphql
+    token: secret
+    headers:
+      x-team: ops
+`), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(filepath.Dir(path))
+	})
+
+	It("reads named profile", func() {
+		p, err := loadProfile(path, "prod")
+
+		Expect(err).To(BeNil())
+		Expect(p.Endpoint).To(Equal("https://foo.bar/v1/graphql"))
+		Expect(p.Token).To(Equal("secret"))
+		Expect(p.Headers).To(HaveKeyWithValue("x-team", "ops"))
+	})
+
+	It("reads default profile", func() {
+		p, err := loadProfile(path, "")
+
+		Expect(err).To(BeNil())
+		Expect(p.Endpoint).To(Equal(defaultEndpoint))
+	})
+
+	It("falls back to local without file", func() {
+		p, err := loadProfile(filepath.Join(filepath.Dir(path), "missing.yaml"), "")
+
+		Expect(err).To(BeNil())
+		Expect(p.Endpoint).To(Equal(defaultEndpoint))
+	})
+
+	It("fails on missing profile", func() {
+		_, err := loadProfile(path, "staging")
+
+		Expect(err).To(MatchError(`no profile "staging"`))
+	})
+})
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+// runCreate creates a schedule from the flags, or every schedule of a JSON
+// file of one input or a list of them, and prints their ids.
+func runCreate(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("create")
+	headers := headerFlag{}
+	dueAt := timeFlag{now: e.clock.Now}
+	deadline := timeFlag{now: e.clock.Now}
+
+	file := fs.String("f", "", "JSON file of the input, - for stdin")
+	url := fs.String("url", "", "url to send the request to")
+	method := fs.String("method", "POST", "method of the request")
+	body := fs.String("body", "", "body of the request")
+	bodyFile := fs.String("body-file", "", "file of the body of the request")
+	maxLateness := fs.Int64("max-lateness", 0,
+		"seconds after dueAt the schedule may still fire")
+	fs.Var(&dueAt, "due-at", "time or duration from now to send at")
+	fs.Var(&deadline, "deadline", "time or duration from now to give up at")
+	fs.Var(headers, "header", "name:value header of the request, repeatable")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	var inputs []client.CreateInput
+
+	if *file != "" {
+		var err error
+
+		if inputs, err = readInputs(e, *file); err != nil {
+			return err
+		}
+	} else {
+		if dueAt.value == nil {
+			return fmt.Errorf("%w: -due-at is required", errUsage)
+		}
+
+		input := client.CreateInput{
+			DueAt:    *dueAt.value,
+			URL:      *url,
+			Method:   strings.ToUpper(*method),
+			Headers:  headers,
+			Body:     *body,
+			Deadline: deadline.value,
+		}
+
+		if len(headers) == 0 {
+			input.Headers = nil
+		}
+
+		if *bodyFile != "" {
+			buff, err := os.ReadFile(*bodyFile)
+
+			if err != nil {
+				return err
+			}
+
+			input.Body = string(buff)
+		}
+
+		if *maxLateness > 0 {
+			input.MaxLateness = maxLateness
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	for _, input := range inputs {
+		id, err := e.client.Create(ctx, input)
+
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(e.out, id)
+	}
+
+	return nil
+}
+
+func readInputs(e *env, file string) ([]client.CreateInput, error) {
+	var r io.Reader = e.in
+
+	if file != "-" {
+		f, err := os.Open(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		r = f
+	}
+
+	buff, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(buff)), "[") {
+		var inputs []client.CreateInput
+
+		if err = json.Unmarshal(buff, &inputs); err != nil {
+			return nil, err
+		}
+
+		return inputs, nil
+	}
+
+	var input client.CreateInput
+
+	if err = json.Unmarshal(buff, &input); err != nil {
+		return nil, err
+	}
+
+	return []client.CreateInput{input}, nil
+}
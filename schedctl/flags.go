@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+// headerFlag collects the repeated name:value headers.
+type headerFlag map[string]string
+
+func (h headerFlag) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+
+	if !ok {
+		return fmt.Errorf("header must be name:value")
+	}
+
+	h[strings.TrimSpace(name)] = strings.TrimSpace(v)
+
+	return nil
+}
+
+// timeFlag is either an RFC 3339 time or a duration from now.
+type timeFlag struct {
+	now   func() time.Time
+	value *time.Time
+}
+
+func (t *timeFlag) String() string {
+	if t.value == nil {
+		return ""
+	}
+
+	return t.value.Format(time.RFC3339)
+}
+
+func (t *timeFlag) Set(value string) error {
+	if d, err := time.ParseDuration(value); err == nil {
+		v := t.now().Add(d)
+		t.value = &v
+
+		return nil
+	}
+
+	v, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return fmt.Errorf("time must be RFC 3339 or a duration from now")
+	}
+
+	t.value = &v
+
+	return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	return fs
+}
+
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	return nil
+}
+
+// filterFlags are the status and dueAt filters and the order of list and
+// export.
+type filterFlags struct {
+	status    string
+	from      timeFlag
+	to        timeFlag
+	orderBy   string
+	direction string
+}
+
+func addFilterFlags(fs *flag.FlagSet, e *env) *filterFlags {
+	f := &filterFlags{
+		from: timeFlag{now: e.clock.Now},
+		to:   timeFlag{now: e.clock.Now},
+	}
+
+	fs.StringVar(&f.status, "status", "", "status of the schedules")
+	fs.Var(&f.from, "from", "dueAt from, a time or a duration from now")
+	fs.Var(&f.to, "to", "dueAt to, a time or a duration from now")
+	fs.StringVar(&f.orderBy, "order-by", "dueAt", "dueAt or createdAt")
+	fs.StringVar(&f.direction, "direction", "desc", "desc or asc")
+
+	return f
+}
+
+func (f *filterFlags) input() (client.ListInput, error) {
+	input := client.ListInput{Status: strings.ToUpper(f.status)}
+
+	if (f.from.value == nil) != (f.to.value == nil) {
+		return input, fmt.Errorf("from and to go together")
+	}
+
+	if f.from.value != nil {
+		input.DueAt = &client.DateRange{From: *f.from.value, To: *f.to.value}
+	}
+
+	switch strings.ToLower(f.orderBy) {
+	case "dueat":
+		input.OrderBy = client.OrderDueAt
+	case "createdat":
+		input.OrderBy = client.OrderCreatedAt
+	default:
+		return input, fmt.Errorf("order-by is dueAt or createdAt")
+	}
+
+	switch strings.ToLower(f.direction) {
+	case "desc":
+		input.Direction = client.DirectionDesc
+	case "asc":
+		input.Direction = client.DirectionAsc
+	default:
+		return input, fmt.Errorf("direction is desc or asc")
+	}
+
+	return input, nil
+}
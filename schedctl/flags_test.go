@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flags", func() {
+	Describe("timeFlag", func() {
+		now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+		It("parses duration from now", func() {
+			t := timeFlag{now: func() time.Time { return now }}
+
+			Expect(t.Set("90m")).To(Succeed())
+			Expect(*t.value).To(Equal(now.Add(90 * time.Minute)))
+		})
+
+		It("parses time", func() {
+			t := timeFlag{now: func() time.Time { return now }}
+
+			Expect(t.Set("2024-06-02T10:00:00Z")).To(Succeed())
+			Expect(*t.value).To(Equal(now.Add(24 * time.Hour)))
+		})
+
+		It("rejects anything else", func() {
+			t := timeFlag{now: func() time.Time { return now }}
+
+			Expect(t.Set("tomorrow")).NotTo(Succeed())
+		})
+	})
+
+	Describe("headerFlag", func() {
+		It("collects repeated headers", func() {
+			h := headerFlag{}
+
+			Expect(h.Set("accept: application/json")).To(Succeed())
+			Expect(h.Set("x-id:1")).To(Succeed())
+			Expect(h).To(Equal(headerFlag{
+				"accept": "application/json",
+				"x-id":   "1",
+			}))
+		})
+
+		It("rejects header without value", func() {
+			Expect(headerFlag{}.Set("accept")).NotTo(Succeed())
+		})
+	})
+})
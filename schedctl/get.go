@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+func runGet(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("get")
+	format := fs.String("o", formatTable, "output, table, json or yaml")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := validFormat(*format); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: id is required", errUsage)
+	}
+
+	var schedules []*client.Schedule
+
+	for _, id := range fs.Args() {
+		s, err := e.client.Get(ctx, id)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+
+		schedules = append(schedules, s)
+	}
+
+	return write(e.out, *format, schedules)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+const pageSize = 100
+
+func runList(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("list")
+	filter := addFilterFlags(fs, e)
+	limit := fs.Int("limit", 25, "most schedules to list, 0 for all")
+	format := fs.String("o", formatTable, "output, table, json or yaml")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := validFormat(*format); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	input, err := filter.input()
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	input.Limit = pageSize
+
+	if *limit > 0 && *limit < pageSize {
+		input.Limit = *limit
+	}
+
+	schedules := []*client.Schedule{}
+	it := e.client.List(input)
+
+	for (*limit == 0 || len(schedules) < *limit) && it.Next(ctx) {
+		schedules = append(schedules, it.Schedule())
+	}
+
+	if err = it.Err(); err != nil {
+		return err
+	}
+
+	return write(e.out, *format, schedules)
+}
@@ -0,0 +1,136 @@
+// Command schedctl operates the scheduler through its graphql api.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+// env is what a command runs with.
+type env struct {
+	client *client.Client
+	clock  clock.Clock
+	in     io.Reader
+	out    io.Writer
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"create": {"create [flags] | create -f file.json", runCreate},
+	"get":    {"get [-o format] id...", runGet},
+	"list":   {"list [-status s] [-from t -to t] [-limit n] [-o format]", runList},
+	"cancel": {"cancel id...", runCancel},
+	"tail":   {"tail [-interval d] id...", runTail},
+	"export": {"export [-status s] [-from t -to t] [-f file]", runExport},
+	"import": {"import [-f file] [-ids file]", runImport},
+}
+
+// errUsage wraps the error of a command line the command could not make
+// sense of.
+var errUsage = errors.New("usage")
+
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "usage: schedctl [-profile name] [-endpoint url] "+
+		"command [flags] [args]")
+	_, _ = fmt.Fprintln(w, "\ncommands:")
+
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+// run executes the command line and returns the exit code.
+func run(
+	ctx context.Context,
+	args []string,
+	in io.Reader,
+	out io.Writer,
+	errOut io.Writer) int {
+
+	fs := flag.NewFlagSet("schedctl", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.Usage = func() { usage(errOut) }
+
+	profile := fs.String("profile", os.Getenv("SCHEDCTL_PROFILE"),
+		"profile of the config file")
+	endpoint := fs.String("endpoint", "", "graphql endpoint, over the profile")
+	config := fs.String("config", configPath(), "config file")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		usage(errOut)
+		return 2
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+
+	if !ok {
+		_, _ = fmt.Fprintf(errOut, "unknown command %q\n", fs.Arg(0))
+		usage(errOut)
+		return 2
+	}
+
+	p, err := loadProfile(*config, *profile)
+
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "config error: %v\n", err)
+		return 1
+	}
+
+	if *endpoint != "" {
+		p.Endpoint = *endpoint
+	}
+
+	e := &env{
+		client: p.client(),
+		clock:  clk,
+		in:     in,
+		out:    out,
+	}
+
+	if err = cmd.run(ctx, e, fs.Args()[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			_, _ = fmt.Fprintf(errOut, "%v\nusage: schedctl %s\n", err, cmd.usage)
+			return 2
+		}
+
+		_, _ = fmt.Fprintf(errOut, "%s error: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	return 0
+}
+
+var clk clock.Clock = clock.System
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+
+	os.Exit(code)
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("schedctl", func() {
+	var (
+		stdin  string
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+		dir    string
+	)
+
+	schedctl := func(args ...string) int {
+		stdout.Reset()
+		stderr.Reset()
+
+		return run(
+			context.TODO(),
+			append([]string{"-endpoint", endpoint}, args...),
+			strings.NewReader(stdin),
+			stdout,
+			stderr)
+	}
+
+	create := func(in string) string {
+		Expect(schedctl(
+			"create",
+			"-due-at", in,
+			"-url", "https://foo.bar/do",
+			"-header", "accept: application/json",
+			"-body", "{}")).To(Equal(0))
+
+		return strings.TrimSpace(stdout.String())
+	}
+
+	BeforeEach(func() {
+		configure()
+
+		stdin = ""
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+
+		var err error
+
+		dir, err = os.MkdirTemp("", "schedctl")
+		Expect(err).To(BeNil())
+
+		_ = os.Setenv("SCHEDCTL_CONFIG", filepath.Join(dir, "config.yaml"))
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDCTL_CONFIG")
+		_ = os.RemoveAll(dir)
+	})
+
+	Describe("create", func() {
+		It("creates from flags", func() {
+			id := create("1h")
+
+			Expect(schedctl("get", "-o", "json", id)).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(1))
+			Expect(schedules[0].Method).To(Equal("POST"))
+			Expect(schedules[0].Headers).To(
+				HaveKeyWithValue("accept", "application/json"))
+			Expect(*schedules[0].Body).To(Equal("{}"))
+		})
+
+		It("creates every input of file", func() {
+			dueAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+			stdin = `[
+				{"dueAt": "` + dueAt + `", "url": "https://foo.bar/a",
+					"method": "GET"},
+				{"dueAt": "` + dueAt + `", "url": "https://foo.bar/b",
+					"method": "PUT"}
+			]`
+
+			Expect(schedctl("create", "-f", "-")).To(Equal(0))
+			Expect(strings.Fields(stdout.String())).To(HaveLen(2))
+		})
+
+		It("fails on rejected input", func() {
+			Expect(schedctl(
+				"create",
+				"-due-at", "-1h",
+				"-url", "https://foo.bar/do")).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("dueAt must be in future"))
+		})
+
+		It("fails without dueAt", func() {
+			Expect(schedctl("create", "-url", "https://foo.bar/do")).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("usage: schedctl create"))
+		})
+	})
+
+	Describe("get", func() {
+		It("prints table", func() {
+			id := create("1h")
+
+			Expect(schedctl("get", id)).To(Equal(0))
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+			Expect(lines).To(HaveLen(2))
+			Expect(strings.Fields(lines[0])).To(Equal(
+				[]string{"ID", "STATUS", "DUE", "AT", "METHOD", "URL"}))
+			Expect(strings.Fields(lines[1])[0]).To(Equal(id))
+			Expect(strings.Fields(lines[1])[1]).To(Equal(client.StatusIdle))
+		})
+
+		It("prints yaml", func() {
+			id := create("1h")
+
+			Expect(schedctl("get", "-o", "yaml", id)).To(Equal(0))
+
+			var schedules []map[string]interface{}
+			Expect(yaml.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules[0]["id"]).To(Equal(id))
+			Expect(schedules[0]).To(HaveKey("dueAt"))
+		})
+
+		It("fails on missing schedule", func() {
+			Expect(schedctl("get", "1234")).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("schedule not found"))
+		})
+	})
+
+	Describe("list", func() {
+		BeforeEach(func() {
+			create("1h")
+			create("2h")
+			create("3h")
+		})
+
+		It("lists up to limit", func() {
+			Expect(schedctl("list", "-status", "idle", "-limit", "2",
+				"-o", "json")).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(2))
+		})
+
+		It("filters by dueAt", func() {
+			Expect(schedctl("list", "-from", "90m", "-to", "4h",
+				"-o", "json")).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(2))
+		})
+
+		It("lists in order", func() {
+			Expect(schedctl("list", "-order-by", "createdAt",
+				"-direction", "asc", "-o", "json")).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(3))
+		})
+
+		It("fails on unknown order", func() {
+			Expect(schedctl("list", "-order-by", "url")).To(Equal(2))
+		})
+
+		It("fails on half a range", func() {
+			Expect(schedctl("list", "-from", "1h")).To(Equal(2))
+		})
+	})
+
+	Describe("cancel", func() {
+		It("cancels idle schedules", func() {
+			id := create("1h")
+
+			Expect(schedctl("cancel", id)).To(Equal(0))
+			Expect(schedctl("cancel", id)).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("not idle"))
+		})
+	})
+
+	Describe("tail", func() {
+		var realClock clock.Clock
+
+		BeforeEach(func() {
+			realClock = clk
+		})
+
+		AfterEach(func() {
+			clk = realClock
+		})
+
+		It("prints status changes till canceled", func() {
+			fake := clock.NewFake(time.Now())
+			clk = fake
+			id := create("1h")
+
+			done := make(chan int)
+
+			go func() {
+				defer GinkgoRecover()
+				done <- schedctl("tail", "-interval", "1s", id)
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+
+			_, err := client.New(endpoint).Cancel(context.TODO(), id)
+			Expect(err).To(BeNil())
+
+			fake.Advance(time.Second)
+
+			Eventually(done).Should(Receive(Equal(0)))
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(HaveSuffix(id + " " + client.StatusIdle))
+			Expect(lines[1]).To(HaveSuffix(id + " " + client.StatusCanceled))
+		})
+	})
+
+	Describe("export and import", func() {
+		It("recreates idle schedules", func() {
+			create("1h")
+			canceled := create("2h")
+			Expect(schedctl("cancel", canceled)).To(Equal(0))
+
+			file := filepath.Join(dir, "schedules.jsonl")
+			Expect(schedctl("export", "-f", file)).To(Equal(0))
+
+			buff, err := os.ReadFile(file)
+			Expect(err).To(BeNil())
+			Expect(strings.Split(strings.TrimSpace(string(buff)), "\n")).To(
+				HaveLen(2))
+
+			configure()
+
+			Expect(schedctl("import", "-f", file)).To(Equal(0))
+			Expect(strings.Split(strings.TrimSpace(stdout.String()), "\n")).To(
+				HaveLen(1))
+
+			Expect(schedctl("list", "-o", "json")).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(1))
+			Expect(*schedules[0].Body).To(Equal("{}"))
+		})
+
+		It("imports past failed lines and reports them", func() {
+			due := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			stdin = "{\n" +
+				`{"id":"past","dueAt":"2000-01-01T00:00:00Z",` +
+				`"url":"https://foo.bar/do","method":"POST"}` + "\n" +
+				`{"id":"next","dueAt":"` + due + `",` +
+				`"url":"https://foo.bar/do","method":"POST"}` + "\n"
+
+			Expect(schedctl("import")).To(Equal(1))
+			Expect(stdout.String()).To(HavePrefix("next "))
+			Expect(stderr.String()).To(ContainSubstring("2 line(s) failed"))
+			Expect(stderr.String()).To(ContainSubstring("line 1: "))
+			Expect(stderr.String()).To(
+				ContainSubstring("line 2: dueAt must be in future"))
+		})
+
+		It("skips schedules imported before", func() {
+			due := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			stdin = `{"id":"old","dueAt":"` + due + `",` +
+				`"url":"https://foo.bar/do","method":"POST"}` + "\n"
+			ids := filepath.Join(dir, "ids")
+
+			Expect(schedctl("import", "-ids", ids)).To(Equal(0))
+			Expect(schedctl("import", "-ids", ids)).To(Equal(0))
+			Expect(stdout.String()).To(BeEmpty())
+
+			buff, err := os.ReadFile(ids)
+			Expect(err).To(BeNil())
+			Expect(string(buff)).To(HavePrefix("old "))
+
+			Expect(schedctl("list", "-o", "json")).To(Equal(0))
+
+			var schedules []*client.Schedule
+			Expect(json.Unmarshal(stdout.Bytes(), &schedules)).To(Succeed())
+
+			Expect(schedules).To(HaveLen(1))
+		})
+	})
+
+	Describe("profiles", func() {
+		It("uses endpoint of profile", func() {
+			Expect(os.WriteFile(
+				os.Getenv("SCHEDCTL_CONFIG"),
+				[]byte("default: local\nprofiles:\n  local:\n    endpoint: "+
+					endpoint+"\n"),
+				0o600)).To(Succeed())
+
+			Expect(run(
+				context.TODO(),
+				[]string{"list"},
+				strings.NewReader(""),
+				stdout,
+				stderr)).To(Equal(0))
+		})
+
+		It("fails on unknown profile", func() {
+			Expect(schedctl("-profile", "prod", "list")).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring(`no profile "prod"`))
+		})
+	})
+
+	It("fails on unknown command", func() {
+		Expect(schedctl("foo")).To(Equal(2))
+	})
+})
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func validFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// write prints the schedules as a table, or as a JSON or YAML list.
+func write(out io.Writer, format string, schedules []*client.Schedule) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(schedules)
+	case formatYAML:
+		// Going through JSON keeps the field names and their order.
+		buff, err := json.Marshal(schedules)
+
+		if err != nil {
+			return err
+		}
+
+		var node yaml.Node
+
+		if err = yaml.Unmarshal(buff, &node); err != nil {
+			return err
+		}
+
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+
+		if err = enc.Encode(&node); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tSTATUS\tDUE AT\tMETHOD\tURL")
+
+	for _, s := range schedules {
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\n",
+			s.ID,
+			s.Status,
+			s.DueAt.Format(time.RFC3339),
+			s.Method,
+			s.URL)
+	}
+
+	return tw.Flush()
+}
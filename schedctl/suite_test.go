@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/handlers"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const table = "scheduler_v1"
+
+// endpoint is the graphql of the local http server the suite runs on.
+var endpoint string
+
+func Test(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Schedctl Suite")
+}
+
+var _ = BeforeSuite(func() {
+	_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	Expect(listener.Close()).To(Succeed())
+
+	_ = os.Setenv("PORT", fmt.Sprint(port))
+	endpoint = fmt.Sprintf("http://127.0.0.1:%d/graphql", port)
+
+	configure()
+
+	go handlers.Http()
+
+	Eventually(func() error {
+		res, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+
+		if err == nil {
+			_ = res.Body.Close()
+		}
+
+		return err
+	}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+})
+
+// configure points the server to an empty table.
+func configure() {
+	Expect(handlers.Configure(
+		storage.NewDatabase(
+			dynamotest.New(dynamotest.SchedulerTable(table)),
+			clock.System,
+			nil),
+		nil,
+		clock.System)).To(Succeed())
+}
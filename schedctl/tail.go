@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+// runTail prints a line whenever the status of one of the schedules
+// changes, starting with the current one, till every one is completed or
+// canceled.
+func runTail(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("tail")
+	interval := fs.Duration("interval", 5*time.Second, "time between checks")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: id is required", errUsage)
+	}
+
+	statuses := make(map[string]string)
+
+	for _, id := range fs.Args() {
+		statuses[id] = ""
+	}
+
+	for {
+		for _, id := range fs.Args() {
+			last := statuses[id]
+
+			if terminal(last) {
+				continue
+			}
+
+			s, err := e.client.Get(ctx, id)
+
+			if err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+
+			if s.Status != last {
+				statuses[id] = s.Status
+				_, _ = fmt.Fprintf(
+					e.out,
+					"%s %s %s\n",
+					e.clock.Now().Format(time.RFC3339),
+					id,
+					s.Status)
+			}
+		}
+
+		done := true
+
+		for _, status := range statuses {
+			done = done && terminal(status)
+		}
+
+		if done {
+			return nil
+		}
+
+		e.clock.Wait(ctx, *interval)
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func terminal(status string) bool {
+	switch status {
+	case client.StatusSucceeded,
+		client.StatusFailed,
+		client.StatusExpired,
+		client.StatusCanceled:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/client"
+)
+
+// runExport writes the schedules as JSON Lines, one schedule a line.
+func runExport(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("export")
+	filter := addFilterFlags(fs, e)
+	file := fs.String("f", "-", "file to write, - for stdout")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	input, err := filter.input()
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	input.Limit = pageSize
+
+	out := e.out
+
+	if *file != "-" {
+		f, err := os.Create(*file)
+
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	it := e.client.List(input)
+
+	for it.Next(ctx) {
+		if err = enc.Encode(it.Schedule()); err != nil {
+			return err
+		}
+	}
+
+	if err = it.Err(); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// runImport creates a schedule for every idle schedule of an export, the
+// rest already ran or were canceled, and prints the old and the new id. A
+// line that fails is reported at the end instead of stopping the import,
+// with -ids the pairs are also appended to a file whose old ids are skipped
+// by the next run, so it only imports what failed before.
+func runImport(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("import")
+	file := fs.String("f", "-", "file to read, - for stdin")
+	ids := fs.String("ids", "", "file of the imported ids, skipped again")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	var in io.Reader = e.in
+
+	if *file != "-" {
+		f, err := os.Open(*file)
+
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		in = f
+	}
+
+	imported := map[string]bool{}
+	out := e.out
+
+	if *ids != "" {
+		var err error
+
+		if imported, err = readImported(*ids); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(*ids, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		out = io.MultiWriter(e.out, f)
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var failed []error
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var s client.Schedule
+
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			failed = append(failed, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+
+		if (s.Status != "" && s.Status != client.StatusIdle) ||
+			(s.ID != "" && imported[s.ID]) {
+			continue
+		}
+
+		input := client.CreateInput{
+			DueAt:    s.DueAt,
+			URL:      s.URL,
+			Method:   s.Method,
+			Headers:  s.Headers,
+			Deadline: s.Deadline,
+		}
+
+		if s.Body != nil {
+			input.Body = *s.Body
+		}
+
+		id, err := e.client.Create(ctx, input)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			failed = append(failed, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+
+		if _, err = fmt.Fprintf(out, "%s %s\n", s.ID, id); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf(
+			"%d line(s) failed\n%w",
+			len(failed),
+			errors.Join(failed...))
+	}
+
+	return nil
+}
+
+// readImported reads the old ids of the pairs an import wrote, a file that
+// does not exist yet has none.
+func readImported(path string) (map[string]bool, error) {
+	imported := map[string]bool{}
+	f, err := os.Open(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return imported, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+			imported[fields[0]] = true
+		}
+	}
+
+	return imported, scanner.Err()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	collectorStorage "github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	graphqlStorage "github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage/storagetest"
+
+	. "github.com/onsi/gomega"
+)
+
+var _ = storagetest.DescribeStorage("SQLite", func() storagetest.Backend {
+	db, err := sql.Open("sqlite", ":memory:")
+	Expect(err).To(BeNil())
+
+	db.SetMaxOpenConns(1)
+
+	Expect(graphqlStorage.Migrate(context.TODO(), db)).To(Succeed())
+
+	return storagetest.Backend{
+		Storage: graphqlStorage.NewSQLite(db, clock.System),
+		Collect: collectorStorage.NewSQLite(db, clock.System).Update,
+	}
+})
+
+var _ = storagetest.DescribeStorage("DynamoDB", func() storagetest.Backend {
+	const (
+		table      = "scheduler_v1"
+		statsTable = "scheduler_stats_v1"
+	)
+
+	_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+	_ = os.Setenv("SCHEDULER_STATS_TABLE_NAME", statsTable)
+
+	dynamo := dynamotest.New(
+		dynamotest.SchedulerTable(table),
+		dynamotest.StatsTable(statsTable))
+
+	return storagetest.Backend{
+		Storage: graphqlStorage.NewDatabase(dynamo, clock.System, nil),
+		Collect: collectorStorage.NewDatabase(dynamo, clock.System, nil).Update,
+	}
+})
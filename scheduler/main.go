@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	_ "modernc.org/sqlite"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	collectorStorage "github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/graphql/handlers"
+	graphqlStorage "github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
+)
+
+const (
+	defaultDatabase    = "scheduler.db"
+	defaultPort        = "8080"
+	defaultInterval    = 10
+	defaultConcurrency = 16
+	loadBatchSize      = 100
+)
+
+var (
+	collector   *collectorStorage.SQL
+	worker      *services.SQL
+	httpClient  services.Client
+	clk         clock.Clock = clock.System
+	concurrency             = defaultConcurrency
+)
+
+// collect claims the due schedules and hands them over to the workers, it
+// blocks while every worker is busy and returns how many were handed over.
+func collect(
+	ctx context.Context,
+	queue chan<- *services.UpdateInput) (int, error) {
+
+	ids, err := collector.Claim(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	handed := 0
+
+	for start := 0; start < len(ids); start += loadBatchSize {
+		end := start + loadBatchSize
+
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		uis, err := worker.Load(ctx, ids[start:end])
+
+		if err != nil {
+			return handed, err
+		}
+
+		for _, ui := range uis {
+			select {
+			case queue <- ui:
+				handed++
+			case <-ctx.Done():
+				return handed, ctx.Err()
+			}
+		}
+	}
+
+	return handed, nil
+}
+
+func work(ctx context.Context, ui *services.UpdateInput) error {
+	ui = services.Dispatch(
+		ctx,
+		clk,
+		httpClient,
+		services.NewRequestInput(ui),
+		ui)
+
+	return worker.Update(ctx, []*services.UpdateInput{ui})
+}
+
+// startWorkers works the schedules sent to the queue, the returned group is
+// done once the queue is closed and drained.
+func startWorkers(
+	ctx context.Context,
+	queue <-chan *services.UpdateInput) *sync.WaitGroup {
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for ui := range queue {
+				if err := work(ctx, ui); err != nil {
+					log.Printf("work error: %v", err)
+				}
+			}
+		}()
+	}
+
+	return &wg
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+
+	return fallback
+}
+
+func main() {
+	ctx := context.Background()
+
+	dataSource := os.Getenv("SCHEDULER_DATABASE_URL")
+
+	if dataSource == "" {
+		dataSource = defaultDatabase
+	}
+
+	simulation := os.Getenv("SCHEDULER_SIMULATION") == "true"
+
+	if simulation {
+		simulated = clock.NewFake(time.Now())
+		clk = simulated
+
+		// Nothing ahead of the simulated time is claimed, so advancing it
+		// never leaves a schedule held by a worker.
+		_ = os.Setenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS", "0")
+	}
+
+	db, err := sql.Open("sqlite", dataSource)
+
+	if err != nil {
+		log.Fatalf("storage create error: %v", err)
+		return
+	}
+
+	// SQLite allows a single writer, sharing one connection keeps the api,
+	// collector and workers from failing each other with a busy database.
+	db.SetMaxOpenConns(1)
+
+	if err = graphqlStorage.Migrate(ctx, db); err != nil {
+		log.Fatalf("storage migrate error: %v", err)
+		return
+	}
+
+	err = handlers.Configure(graphqlStorage.NewSQLite(db, clk), nil, clk)
+
+	if err != nil {
+		log.Fatalf("schema create error: %v", err)
+		return
+	}
+
+	collector = collectorStorage.NewSQLite(db, clk)
+	worker = services.NewSQLite(db)
+	httpClient = services.NewHttpClient(
+		retryablehttp.NewClient().StandardClient())
+
+	// Schedules claimed by a previous run never reached a worker.
+	if err = collector.Requeue(ctx); err != nil {
+		log.Fatalf("storage requeue error: %v", err)
+		return
+	}
+
+	concurrency = envInt("SCHEDULER_WORKER_CONCURRENCY", defaultConcurrency)
+
+	if simulation {
+		// Time only moves through the admin endpoint, which collects itself.
+		http.HandleFunc("/admin/time", handleTime)
+	} else {
+		queue := make(chan *services.UpdateInput)
+		startWorkers(ctx, queue)
+
+		interval := time.Duration(envInt(
+			"SCHEDULER_COLLECTOR_INTERVAL_SECONDS",
+			defaultInterval)) * time.Second
+
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				if _, err := collect(ctx, queue); err != nil {
+					log.Printf("collect error: %v", err)
+				}
+
+				<-ticker.C
+			}
+		}()
+	}
+
+	if os.Getenv("PORT") == "" {
+		_ = os.Setenv("PORT", defaultPort)
+	}
+
+	handlers.Http()
+}
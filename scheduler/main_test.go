@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	collectorStorage "github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
+	graphqlStorage "github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduler", func() {
+	var (
+		db       *sql.DB
+		server   *httptest.Server
+		requests int
+		api      *graphqlStorage.SQL
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		db, err = sql.Open("sqlite", ":memory:")
+		Expect(err).To(BeNil())
+
+		db.SetMaxOpenConns(1)
+
+		Expect(graphqlStorage.Migrate(context.TODO(), db)).To(Succeed())
+
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+			}))
+
+		clk = clock.System
+		api = graphqlStorage.NewSQLite(db, clk)
+		collector = collectorStorage.NewSQLite(db, clk)
+		worker = services.NewSQLite(db)
+		httpClient = services.NewHttpClient(server.Client())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = db.Close()
+	})
+
+	Context("due schedule", func() {
+		var (
+			id  string
+			err error
+		)
+
+		BeforeEach(func() {
+			id, err = api.Create(context.TODO(), graphqlStorage.CreateInput{
+				DueAt:  time.Now().Add(-time.Second),
+				URL:    server.URL,
+				Method: http.MethodPost,
+				Body:   "{}",
+			})
+			Expect(err).To(BeNil())
+
+			queue := make(chan *services.UpdateInput, 1)
+
+			handed, err := collect(context.TODO(), queue)
+			Expect(err).To(BeNil())
+			Expect(handed).To(Equal(1))
+
+			Expect(work(context.TODO(), <-queue)).To(Succeed())
+		})
+
+		It("sends request", func() {
+			Expect(requests).To(Equal(1))
+		})
+
+		It("marks schedule succeeded", func() {
+			schedule, err := api.Get(context.TODO(), id)
+
+			Expect(err).To(BeNil())
+			Expect(schedule.Status).To(
+				Equal(graphqlStorage.ScheduleStatusSucceeded))
+			Expect(schedule.CompletedAt).NotTo(BeNil())
+		})
+	})
+
+	Context("future schedule", func() {
+		var queue chan *services.UpdateInput
+
+		BeforeEach(func() {
+			_, err := api.Create(context.TODO(), graphqlStorage.CreateInput{
+				DueAt:  time.Now().Add(time.Hour),
+				URL:    server.URL,
+				Method: http.MethodPost,
+			})
+			Expect(err).To(BeNil())
+
+			queue = make(chan *services.UpdateInput, 1)
+
+			_, err = collect(context.TODO(), queue)
+			Expect(err).To(BeNil())
+		})
+
+		It("is not handed to workers", func() {
+			Expect(queue).To(BeEmpty())
+		})
+	})
+
+	Context("schedule left queued", func() {
+		var id string
+
+		BeforeEach(func() {
+			var err error
+
+			id, err = api.Create(context.TODO(), graphqlStorage.CreateInput{
+				DueAt:  time.Now().Add(-time.Second),
+				URL:    server.URL,
+				Method: http.MethodPost,
+			})
+			Expect(err).To(BeNil())
+
+			_, err = collector.Claim(context.TODO())
+			Expect(err).To(BeNil())
+
+			Expect(collector.Requeue(context.TODO())).To(Succeed())
+		})
+
+		It("is put back to idle", func() {
+			schedule, err := api.Get(context.TODO(), id)
+
+			Expect(err).To(BeNil())
+			Expect(schedule.Status).To(
+				Equal(graphqlStorage.ScheduleStatusIdle))
+		})
+
+		It("is counted once completed", func() {
+			queue := make(chan *services.UpdateInput, 1)
+
+			_, err := collect(context.TODO(), queue)
+			Expect(err).To(BeNil())
+			Expect(work(context.TODO(), <-queue)).To(Succeed())
+
+			now := time.Now()
+			stats, err := api.Stats(context.TODO(), graphqlStorage.StatsInput{
+				At: graphqlStorage.DateRange{From: now, To: now},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(stats.Statuses).To(Equal([]graphqlStorage.StatusCount{{
+				Status: graphqlStorage.ScheduleStatusSucceeded,
+				Count:  1,
+			}}))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
+)
+
+// simulated is the clock of the simulation mode, it only moves when the
+// admin endpoint advances it.
+var (
+	simulated    *clock.Fake
+	simulationMu sync.Mutex
+)
+
+type timeInput struct {
+	Seconds int64 `json:"seconds"`
+}
+
+type timeOutput struct {
+	Now        time.Time `json:"now"`
+	Dispatched int       `json:"dispatched"`
+}
+
+// advance moves the simulated clock and dispatches every schedule that
+// became due, it returns once all of them are completed.
+func advance(ctx context.Context, d time.Duration) (int, error) {
+	simulationMu.Lock()
+	defer simulationMu.Unlock()
+
+	simulated.Advance(d)
+
+	queue := make(chan *services.UpdateInput)
+	wg := startWorkers(ctx, queue)
+
+	dispatched, err := collect(ctx, queue)
+
+	close(queue)
+	wg.Wait()
+
+	return dispatched, err
+}
+
+func handleTime(w http.ResponseWriter, r *http.Request) {
+	var output timeOutput
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var input timeInput
+
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil ||
+			input.Seconds < 0 {
+			http.Error(
+				w,
+				"seconds must be a non negative number",
+				http.StatusBadRequest)
+			return
+		}
+
+		// Dispatching is not tied to the request, a client that gives up
+		// does not interrupt the schedules.
+		dispatched, err := advance(
+			context.Background(),
+			time.Duration(input.Seconds)*time.Second)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		output.Dispatched = dispatched
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	output.Now = simulated.Now().UTC()
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	_ = json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	collectorStorage "github.com/kazimanzurrashid/aws-scheduler-go/collector/storage"
+	graphqlStorage "github.com/kazimanzurrashid/aws-scheduler-go/graphql/storage"
+	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("simulation", func() {
+	var (
+		db       *sql.DB
+		server   *httptest.Server
+		requests int
+		api      *graphqlStorage.SQL
+		start    time.Time
+	)
+
+	request := func(method, body string) (*timeOutput, int) {
+		rec := httptest.NewRecorder()
+
+		handleTime(rec, httptest.NewRequest(
+			method,
+			"/admin/time",
+			strings.NewReader(body)))
+
+		if rec.Code != http.StatusOK {
+			return nil, rec.Code
+		}
+
+		var output timeOutput
+		Expect(json.Unmarshal(rec.Body.Bytes(), &output)).To(Succeed())
+
+		return &output, rec.Code
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		db, err = sql.Open("sqlite", ":memory:")
+		Expect(err).To(BeNil())
+
+		db.SetMaxOpenConns(1)
+
+		Expect(graphqlStorage.Migrate(context.TODO(), db)).To(Succeed())
+
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+			}))
+
+		_ = os.Setenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS", "0")
+
+		start = time.Unix(1600000000, 0)
+		simulated = clock.NewFake(start)
+		clk = simulated
+		concurrency = 2
+
+		api = graphqlStorage.NewSQLite(db, clk)
+		collector = collectorStorage.NewSQLite(db, clk)
+		worker = services.NewSQLite(db)
+		httpClient = services.NewHttpClient(server.Client())
+
+		for _, offset := range []time.Duration{time.Minute, time.Hour} {
+			_, err = api.Create(context.TODO(), graphqlStorage.CreateInput{
+				DueAt:  start.Add(offset),
+				URL:    server.URL,
+				Method: http.MethodPost,
+			})
+			Expect(err).To(BeNil())
+		}
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS")
+
+		clk = clock.System
+		concurrency = defaultConcurrency
+
+		server.Close()
+		_ = db.Close()
+	})
+
+	It("tells simulated time", func() {
+		output, code := request(http.MethodGet, "")
+
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(output.Now.Unix()).To(Equal(start.Unix()))
+	})
+
+	It("does not dispatch before due", func() {
+		output, _ := request(http.MethodPost, `{"seconds": 59}`)
+
+		Expect(output.Now.Unix()).To(Equal(start.Unix() + 59))
+		Expect(output.Dispatched).To(BeZero())
+		Expect(requests).To(BeZero())
+	})
+
+	It("dispatches schedules that became due", func() {
+		output, _ := request(http.MethodPost, `{"seconds": 60}`)
+
+		Expect(output.Dispatched).To(Equal(1))
+		Expect(requests).To(Equal(1))
+
+		list, err := api.List(context.TODO(), graphqlStorage.ListInput{
+			Status: graphqlStorage.ScheduleStatusSucceeded,
+			Limit:  10,
+		})
+		Expect(err).To(BeNil())
+		Expect(list.Schedules).To(HaveLen(1))
+		Expect(list.Schedules[0].CompletedAt.Unix()).To(
+			Equal(start.Unix() + 60))
+		Expect(*list.Schedules[0].Lag).To(BeZero())
+	})
+
+	It("dispatches every schedule once", func() {
+		first, _ := request(http.MethodPost, `{"seconds": 3600}`)
+		second, _ := request(http.MethodPost, `{"seconds": 3600}`)
+
+		Expect(first.Dispatched).To(Equal(2))
+		Expect(second.Dispatched).To(BeZero())
+		Expect(requests).To(Equal(2))
+	})
+
+	It("rejects going back in time", func() {
+		_, code := request(http.MethodPost, `{"seconds": -1}`)
+
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("rejects other methods", func() {
+		_, code := request(http.MethodDelete, "")
+
+		Expect(code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
@@ -0,0 +1,63 @@
+// Package sharding spreads the status and due partitions of the schedule
+// table over SCHEDULER_SHARD_COUNT shards. The graphql writes the keys the
+// collector, worker and archiver read, they all hash the same way.
+//
+// Shards are only baked into the partition keys when more than one is
+// configured, a single shard keeps the original IDLE/"-" keys on the
+// ix_status_dueAt and ix_dummy_dueAt indexes. A sharded table has no
+// ix_status_dueAt, every idle schedule would share its partition. Changing
+// the count requires existing items to be rewritten with their new keys.
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+)
+
+const (
+	StatusIndexName      = "ix_status_dueAt"
+	StatusShardIndexName = "ix_statusShard_dueAt"
+)
+
+// Count is the configured number of shards, at least one.
+func Count() int {
+	count, err := strconv.Atoi(os.Getenv("SCHEDULER_SHARD_COUNT"))
+
+	if err != nil || count < 1 {
+		return 1
+	}
+
+	return count
+}
+
+// Of is the shard of the id.
+func Of(id string, count int) int {
+	if count <= 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return int(h.Sum32() % uint32(count))
+}
+
+// Key is the partition key of the prefix in the shard.
+func Key(prefix string, shard, count int) string {
+	if count <= 1 {
+		return prefix
+	}
+
+	return fmt.Sprintf("%s#%02d", prefix, shard)
+}
+
+// StatusIndex answers the index of the status and its partition attribute.
+func StatusIndex(count int) (string, string) {
+	if count <= 1 {
+		return StatusIndexName, "status"
+	}
+
+	return StatusShardIndexName, "statusShard"
+}
@@ -0,0 +1,74 @@
+package sharding
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sharding", func() {
+	Describe("Count", func() {
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+		})
+
+		It("defaults to single shard", func() {
+			Expect(Count()).To(Equal(1))
+		})
+
+		It("reads count from env", func() {
+			_ = os.Setenv("SCHEDULER_SHARD_COUNT", "16")
+
+			Expect(Count()).To(Equal(16))
+		})
+
+		It("ignores invalid count", func() {
+			_ = os.Setenv("SCHEDULER_SHARD_COUNT", "0")
+
+			Expect(Count()).To(Equal(1))
+		})
+	})
+
+	Describe("Of", func() {
+		It("is stable for same id", func() {
+			Expect(Of("1234567890", 8)).To(Equal(Of("1234567890", 8)))
+		})
+
+		It("is within count", func() {
+			for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+				Expect(Of(id, 4)).To(BeNumerically("<", 4))
+			}
+		})
+
+		It("is zero for single shard", func() {
+			Expect(Of("1234567890", 1)).To(BeZero())
+		})
+	})
+
+	Describe("Key", func() {
+		It("keeps prefix for single shard", func() {
+			Expect(Key("IDLE", 0, 1)).To(Equal("IDLE"))
+		})
+
+		It("appends padded shard", func() {
+			Expect(Key("IDLE", 7, 16)).To(Equal("IDLE#07"))
+		})
+	})
+
+	Describe("StatusIndex", func() {
+		It("uses status index for single shard", func() {
+			index, attr := StatusIndex(1)
+
+			Expect(index).To(Equal("ix_status_dueAt"))
+			Expect(attr).To(Equal("status"))
+		})
+
+		It("uses status shard index for many shards", func() {
+			index, attr := StatusIndex(4)
+
+			Expect(index).To(Equal("ix_statusShard_dueAt"))
+			Expect(attr).To(Equal("statusShard"))
+		})
+	})
+})
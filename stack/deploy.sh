@@ -13,6 +13,10 @@ cd ../worker || exit
 mkdir -p dist
 CGO_ENABLED=0 GOOS=linux go build -o dist/main
 
+cd ../archiver || exit
+mkdir -p dist
+CGO_ENABLED=0 GOOS=linux go build -o dist/main
+
 cd ../stack || exit
 cdk bootstrap
 cdk deploy --require-approval never
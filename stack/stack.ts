@@ -17,13 +17,24 @@ import {
 
 import {
   Code,
+  FilterCriteria,
+  FilterRule,
   Function,
   Runtime,
   StartingPosition,
   Tracing
 } from 'aws-cdk-lib/aws-lambda';
 
-import { DynamoEventSource } from 'aws-cdk-lib/aws-lambda-event-sources';
+import {
+  DynamoEventSource,
+  SqsEventSource
+} from 'aws-cdk-lib/aws-lambda-event-sources';
+
+import { Queue } from 'aws-cdk-lib/aws-sqs';
+
+import { Bucket, BucketEncryption } from 'aws-cdk-lib/aws-s3';
+
+import { Secret } from 'aws-cdk-lib/aws-secretsmanager';
 
 import { Rule, RuleTargetInput, Schedule } from 'aws-cdk-lib/aws-events';
 
@@ -36,6 +47,13 @@ import { HttpLambdaIntegration } from '@aws-cdk/aws-apigatewayv2-integrations-al
 interface SchedulerProps extends StackProps {
   name: string;
   version: string;
+  workQueue: boolean;
+  retentionDays?: number;
+  blobs: boolean;
+  cursorSecretArn?: string;
+  lookaheadSeconds: number;
+  shardCount: number;
+  bothStatusIndexes: boolean;
 }
 
 class SchedulerStack extends Stack {
@@ -51,13 +69,51 @@ class SchedulerStack extends Stack {
         name: 'id',
         type: AttributeType.STRING
       },
-      stream: StreamViewType.NEW_IMAGE
+      timeToLiveAttribute: 'ttl',
+      // The worker reads the new image of the queued schedules and the
+      // archiver the old image of the ones the time to live deleted.
+      stream: StreamViewType.NEW_AND_OLD_IMAGES
     });
 
+    // A sharded table only indexes the status along with its shard, every
+    // idle schedule would share one partition of ix_status_dueAt otherwise.
+    // The count is fixed once the table has schedules, they are not rekeyed.
+    // CloudFormation creates or deletes one index per update, so moving
+    // between one and many shards keeps both indexes for a deploy first.
+    const sharded = props.shardCount > 1;
+
+    if (!sharded || props.bothStatusIndexes) {
+      schedulerTable.addGlobalSecondaryIndex({
+        indexName: 'ix_status_dueAt',
+        partitionKey: {
+          name: 'status',
+          type: AttributeType.STRING
+        },
+        sortKey: {
+          name: 'dueAt',
+          type: AttributeType.NUMBER
+        }
+      });
+    }
+
+    if (sharded || props.bothStatusIndexes) {
+      schedulerTable.addGlobalSecondaryIndex({
+        indexName: 'ix_statusShard_dueAt',
+        partitionKey: {
+          name: 'statusShard',
+          type: AttributeType.STRING
+        },
+        sortKey: {
+          name: 'dueAt',
+          type: AttributeType.NUMBER
+        }
+      });
+    }
+
     schedulerTable.addGlobalSecondaryIndex({
-      indexName: 'ix_status_dueAt',
+      indexName: 'ix_dummy_dueAt',
       partitionKey: {
-        name: 'status',
+        name: 'dummy',
         type: AttributeType.STRING
       },
       sortKey: {
@@ -66,18 +122,119 @@ class SchedulerStack extends Stack {
       }
     });
 
+    // list orders by createdAt on this one, filtering status and dueAt.
     schedulerTable.addGlobalSecondaryIndex({
-      indexName: 'ix_dummy_dueAt',
+      indexName: 'ix_dummy_createdAt',
       partitionKey: {
         name: 'dummy',
         type: AttributeType.STRING
       },
       sortKey: {
-        name: 'dueAt',
+        name: 'createdAt',
         type: AttributeType.NUMBER
       }
     });
 
+    const shardCount = `${props.shardCount}`;
+
+    const circuitTable = new Table(this, 'CircuitTable', {
+      tableName: `${props.name}-circuit-${props.version}`,
+      removalPolicy: RemovalPolicy.DESTROY,
+      billingMode: BillingMode.PAY_PER_REQUEST,
+      partitionKey: {
+        name: 'host',
+        type: AttributeType.STRING
+      }
+    });
+
+    // The counts of a bucket are spread over items keyed bucket#NN.
+    const statsTable = new Table(this, 'StatsTable', {
+      tableName: `${props.name}-stats-${props.version}`,
+      removalPolicy: RemovalPolicy.DESTROY,
+      billingMode: BillingMode.PAY_PER_REQUEST,
+      partitionKey: {
+        name: 'bucket',
+        type: AttributeType.STRING
+      }
+    });
+
+    const workerTimeout = Duration.minutes(15);
+
+    const workQueue = props.workQueue ?
+      new Queue(this, 'WorkQueue', {
+        queueName: `${props.name}-work-${props.version}`,
+        visibilityTimeout: workerTimeout,
+        deadLetterQueue: {
+          maxReceiveCount: 5,
+          queue: new Queue(this, 'WorkDeadLetterQueue', {
+            queueName: `${props.name}-work-dlq-${props.version}`,
+            retentionPeriod: Duration.days(14)
+          })
+        }
+      }) :
+      undefined;
+
+    const queueEnvironment: { [key: string]: string } = workQueue ?
+      { SCHEDULER_QUEUE_URL: workQueue.queueUrl } :
+      {};
+
+    // Terminal schedules are only given a ttl with a retention, the time to
+    // live of the table deletes them once it passed and the archiver exports
+    // them from the stream.
+    const retentionEnvironment: { [key: string]: string } = {};
+
+    if (props.retentionDays) {
+      const seconds = `${Duration.days(props.retentionDays).toSeconds()}`;
+
+      for (const status of ['SUCCEEDED', 'FAILED', 'EXPIRED', 'CANCELED']) {
+        retentionEnvironment[`SCHEDULER_RETENTION_${status}_SECONDS`] = seconds;
+      }
+    }
+
+    // Bodies and results over the threshold go to the bucket instead of the
+    // item, which is limited to 400 KB. The archiver deletes them with their
+    // schedule, a result is only written once a schedule is done, so the
+    // ones it missed expire a day after the retention.
+    const blobBucket = props.blobs ?
+      new Bucket(this, 'BlobBucket', {
+        bucketName: `${props.name}-blob-${props.version}-${this.account}`,
+        removalPolicy: RemovalPolicy.RETAIN,
+        encryption: BucketEncryption.S3_MANAGED,
+        lifecycleRules: props.retentionDays ?
+          [{
+            prefix: 'results/',
+            expiration: Duration.days(props.retentionDays + 1)
+          }] :
+          []
+      }) :
+      undefined;
+
+    const blobEnvironment: { [key: string]: string } = blobBucket ?
+      { SCHEDULER_BLOB_BUCKET: blobBucket.bucketName } :
+      {};
+
+    // Every instance signs and verifies the list cursors with the same secret,
+    // the lambda refuses to start without one, so one is generated and kept
+    // in Secrets Manager unless given. Only its arn is in the environment,
+    // the lambda reads the secret at cold start.
+    const cursorSecret = props.cursorSecretArn ?
+      Secret.fromSecretCompleteArn(
+        this,
+        'CursorSecret',
+        props.cursorSecretArn) :
+      new Secret(this, 'CursorSecret', {
+        secretName: `${props.name}-cursor-${props.version}`,
+        generateSecretString: {
+          passwordLength: 64,
+          excludePunctuation: true
+        }
+      });
+
+    // Schedules due within the lookahead are queued early and held by the
+    // worker till their second, they can no longer be canceled once queued.
+    // It matches the one minute rule of the collector, 0 turns it off.
+    const lookaheadSeconds = `${props.lookaheadSeconds}`;
+
     const graphqlLambda = new Function(this, 'GraphQLFunction', {
       functionName: `${props.name}-graphql-${props.version}`,
       handler: 'main',
@@ -87,11 +244,20 @@ class SchedulerStack extends Stack {
       tracing: Tracing.ACTIVE,
       code: Code.fromAsset(`./../graphql/dist`),
       environment: {
-        SCHEDULER_TABLE_NAME: schedulerTable.tableName
+        SCHEDULER_TABLE_NAME: schedulerTable.tableName,
+        SCHEDULER_SHARD_COUNT: shardCount,
+        SCHEDULER_STATS_TABLE_NAME: statsTable.tableName,
+        ...retentionEnvironment,
+        ...blobEnvironment,
+        SCHEDULER_CURSOR_SECRET_ARN: cursorSecret.secretArn
       }
     });
 
+    cursorSecret.grantRead(graphqlLambda);
+
     schedulerTable.grantReadWriteData(graphqlLambda);
+    statsTable.grantReadWriteData(graphqlLambda);
+    blobBucket?.grantReadWrite(graphqlLambda);
 
     const integration = new HttpLambdaIntegration('LambdaIntegration', graphqlLambda);
 
@@ -100,7 +266,9 @@ class SchedulerStack extends Stack {
       corsPreflight: {
         allowOrigins: ['*'],
         allowMethods: [
-          CorsHttpMethod.POST
+          CorsHttpMethod.GET,
+          CorsHttpMethod.POST,
+          CorsHttpMethod.DELETE
         ],
         allowHeaders: [
           'Content-Type'
@@ -122,6 +290,24 @@ class SchedulerStack extends Stack {
       path: '/graphql'
     });
 
+    api.addRoutes({
+      integration,
+      methods: [HttpMethod.GET, HttpMethod.POST],
+      path: '/schedules'
+    });
+
+    api.addRoutes({
+      integration,
+      methods: [HttpMethod.GET, HttpMethod.DELETE],
+      path: '/schedules/{id}'
+    });
+
+    api.addRoutes({
+      integration,
+      methods: [HttpMethod.GET],
+      path: '/openapi.json'
+    });
+
     api.addStage('ApiStage', {
       stageName: props.version,
       autoDeploy: true
@@ -136,11 +322,15 @@ class SchedulerStack extends Stack {
       tracing: Tracing.ACTIVE,
       code: Code.fromAsset(`./../collector/dist`),
       environment: {
-        SCHEDULER_TABLE_NAME: schedulerTable.tableName
+        SCHEDULER_TABLE_NAME: schedulerTable.tableName,
+        SCHEDULER_SHARD_COUNT: shardCount,
+        SCHEDULER_COLLECTOR_LOOKAHEAD_SECONDS: lookaheadSeconds,
+        ...queueEnvironment
       }
     });
 
     schedulerTable.grantReadWriteData(collectorLambda);
+    workQueue?.grantSendMessages(collectorLambda);
 
     new Rule(this, 'SchedulerRule', {
       schedule: Schedule.rate(Duration.minutes(1)),
@@ -155,20 +345,84 @@ class SchedulerStack extends Stack {
       handler: 'main',
       runtime: Runtime.GO_1_X,
       memorySize: 1024,
-      timeout: Duration.minutes(15),
+      timeout: workerTimeout,
       tracing: Tracing.ACTIVE,
       code: Code.fromAsset(`./../worker/dist`),
       environment: {
-        SCHEDULER_TABLE_NAME: schedulerTable.tableName
+        SCHEDULER_TABLE_NAME: schedulerTable.tableName,
+        SCHEDULER_SHARD_COUNT: shardCount,
+        SCHEDULER_STATS_TABLE_NAME: statsTable.tableName,
+        SCHEDULER_CIRCUIT_TABLE_NAME: circuitTable.tableName,
+        SCHEDULER_CIRCUIT_FAILURE_THRESHOLD: '5',
+        SCHEDULER_CIRCUIT_COOLDOWN_SECONDS: '60',
+        SCHEDULER_WORKER_RESERVE_SECONDS: '10',
+        ...queueEnvironment,
+        ...retentionEnvironment,
+        ...blobEnvironment
       }
     });
 
-    workerLambda.addEventSource(new DynamoEventSource(schedulerTable, {
-      startingPosition: StartingPosition.LATEST
-    }));
+    if (workQueue) {
+      workerLambda.addEventSource(new SqsEventSource(workQueue, {
+        batchSize: 10
+      }));
+
+      schedulerTable.grantReadWriteData(workerLambda);
+    } else {
+      workerLambda.addEventSource(new DynamoEventSource(schedulerTable, {
+        startingPosition: StartingPosition.LATEST
+      }));
+
+      schedulerTable.grantStreamRead(workerLambda);
+      schedulerTable.grantWriteData(workerLambda);
+    }
+
+    circuitTable.grantReadWriteData(workerLambda);
+    statsTable.grantWriteData(workerLambda);
+    blobBucket?.grantReadWrite(workerLambda);
+
+    if (props.retentionDays) {
+      const archiveBucket = new Bucket(this, 'ArchiveBucket', {
+        bucketName: `${props.name}-archive-${props.version}-${this.account}`,
+        removalPolicy: RemovalPolicy.RETAIN,
+        encryption: BucketEncryption.S3_MANAGED
+      });
+
+      const archiverLambda = new Function(this, 'ArchiverFunction', {
+        functionName: `${props.name}-archiver-${props.version}`,
+        handler: 'main',
+        runtime: Runtime.GO_1_X,
+        memorySize: 512,
+        timeout: Duration.minutes(15),
+        tracing: Tracing.ACTIVE,
+        code: Code.fromAsset(`./../archiver/dist`),
+        environment: {
+          SCHEDULER_TABLE_NAME: schedulerTable.tableName,
+          SCHEDULER_SHARD_COUNT: shardCount,
+          SCHEDULER_ARCHIVE_BUCKET: archiveBucket.bucketName,
+          ...blobEnvironment
+        }
+      });
+
+      // Only the removes of the time to live reach it, which DynamoDB makes
+      // as a service, the stream retries a batch that failed to export.
+      archiverLambda.addEventSource(new DynamoEventSource(schedulerTable, {
+        startingPosition: StartingPosition.TRIM_HORIZON,
+        batchSize: 100,
+        filters: [FilterCriteria.filter({
+          eventName: FilterRule.isEqual('REMOVE'),
+          userIdentity: {
+            type: FilterRule.isEqual('Service'),
+            principalId: FilterRule.isEqual('dynamodb.amazonaws.com')
+          }
+        })]
+      }));
 
-    schedulerTable.grantStreamRead(workerLambda);
-    schedulerTable.grantWriteData(workerLambda);
+      schedulerTable.grantStreamRead(archiverLambda);
+      archiveBucket.grantPut(archiverLambda);
+      blobBucket?.grantRead(archiverLambda);
+      blobBucket?.grantDelete(archiverLambda);
+    }
   }
 }
 
@@ -180,7 +434,15 @@ new SchedulerStack(app, 'scheduler-v1', {
     region: process.env.CDK_DEFAULT_REGION
   },
   name: 'scheduler',
-  version: 'v1'
+  version: 'v1',
+  workQueue: app.node.tryGetContext('workQueue') === 'sqs',
+  retentionDays: Number(app.node.tryGetContext('retentionDays')) || undefined,
+  blobs: app.node.tryGetContext('blobs') === 's3',
+  cursorSecretArn: app.node.tryGetContext('cursorSecretArn'),
+  lookaheadSeconds: app.node.tryGetContext('lookaheadSeconds') === undefined ?
+    60 : Number(app.node.tryGetContext('lookaheadSeconds')),
+  shardCount: Number(app.node.tryGetContext('shardCount')) || 1,
+  bothStatusIndexes: app.node.tryGetContext('statusIndexes') === 'both'
 });
 
 app.synth();
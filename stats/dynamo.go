@@ -0,0 +1,330 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+)
+
+const (
+	bucketAttribute  = "bucket"
+	maxBatchGets     = 100
+	batchGetBackoff  = 50 * time.Millisecond
+	maxBatchGetRetry = 5
+	transactBackoff  = 50 * time.Millisecond
+	maxTransactRetry = 5
+
+	// counterShards spreads a bucket over that many items, a write adds to
+	// a random one and a read sums them, so the writers of the same hour
+	// rarely meet on one item.
+	counterShards = 8
+)
+
+var errUnprocessedKeys = errors.New("stats left unprocessed")
+
+// Dynamo keeps the counts of a bucket in the items of a table with a string
+// bucket partition key, the start of the bucket and a counter shard like
+// 1600002000#03. Its counts are the attributes named status#host.
+type Dynamo struct {
+	dynamodb dynamodbiface.DynamoDBAPI
+	table    string
+	clock    clock.Clock
+}
+
+func NewDynamo(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	table string,
+	clock clock.Clock) *Dynamo {
+
+	return &Dynamo{dynamodb, table, clock}
+}
+
+func (d *Dynamo) Add(ctx context.Context, transitions []Transition) error {
+	for _, u := range d.updates(transitions) {
+		_, err := d.dynamodb.UpdateItemWithContext(
+			ctx,
+			&dynamodb.UpdateItemInput{
+				TableName:                 u.TableName,
+				Key:                       u.Key,
+				UpdateExpression:          u.UpdateExpression,
+				ExpressionAttributeNames:  u.ExpressionAttributeNames,
+				ExpressionAttributeValues: u.ExpressionAttributeValues,
+				ReturnConsumedCapacity: aws.String(
+					dynamodb.ReturnConsumedCapacityNone),
+				ReturnValues: aws.String(dynamodb.ReturnValueNone),
+			})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updates adds the counts of each bucket to a random counter shard of it.
+func (d *Dynamo) updates(transitions []Transition) []*dynamodb.Update {
+	counts := group(transitions)
+	keys := sortedKeys(counts)
+	var updates []*dynamodb.Update
+
+	for start := 0; start < len(keys); {
+		end := start + 1
+
+		for end < len(keys) && keys[end].bucket == keys[start].bucket {
+			end++
+		}
+
+		updates = append(updates, d.update(
+			keys[start:end],
+			counts,
+			rand.Intn(counterShards)))
+
+		start = end
+	}
+
+	return updates
+}
+
+// update adds the counts of the keys to the shard of the bucket they are in.
+func (d *Dynamo) update(
+	keys []key,
+	counts map[key]int64,
+	shard int) *dynamodb.Update {
+
+	names := make(map[string]*string)
+	values := make(map[string]*dynamodb.AttributeValue)
+	adds := make([]string, len(keys))
+
+	for i, k := range keys {
+		adds[i] = fmt.Sprintf("#a%d :a%d", i, i)
+		names[fmt.Sprintf("#a%d", i)] = aws.String(k.status + "#" + k.host)
+		values[fmt.Sprintf(":a%d", i)] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(counts[k], 10)),
+		}
+	}
+
+	return &dynamodb.Update{
+		TableName: aws.String(d.table),
+		Key: map[string]*dynamodb.AttributeValue{
+			bucketAttribute: {S: aws.String(counterKey(keys[0].bucket, shard))},
+		},
+		UpdateExpression: aws.String(
+			"ADD " + strings.Join(adds, ", ")),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+}
+
+func counterKey(bucket int64, shard int) string {
+	return sharding.Key(strconv.FormatInt(bucket, 10), shard, counterShards)
+}
+
+// Transact writes the items and counts the transitions in one transaction,
+// so the counts are only added along with the changes they count. It adds
+// an item per bucket to the transaction, which can hold 100. A transaction
+// canceled by a conflicting one is retried with a backoff, on other counter
+// shards.
+func (d *Dynamo) Transact(
+	ctx context.Context,
+	items []*dynamodb.TransactWriteItem,
+	transitions []Transition) error {
+
+	backoff := transactBackoff
+
+	for attempt := 0; ; attempt++ {
+		input := &dynamodb.TransactWriteItemsInput{
+			TransactItems: items,
+			ReturnConsumedCapacity: aws.String(
+				dynamodb.ReturnConsumedCapacityNone),
+		}
+
+		for _, u := range d.updates(transitions) {
+			input.TransactItems = append(
+				input.TransactItems,
+				&dynamodb.TransactWriteItem{Update: u})
+		}
+
+		_, err := d.dynamodb.TransactWriteItemsWithContext(ctx, input)
+
+		if err == nil || !conflicted(err) || attempt == maxTransactRetry {
+			return err
+		}
+
+		d.clock.Wait(ctx, backoff)
+		backoff *= 2
+
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// conflicted tells a transaction was canceled by another one only.
+func conflicted(err error) bool {
+	tce, ok := err.(*dynamodb.TransactionCanceledException)
+
+	if !ok {
+		return false
+	}
+
+	found := false
+
+	for _, reason := range tce.CancellationReasons {
+		switch aws.StringValue(reason.Code) {
+		case "", "None":
+		case "TransactionConflict":
+			found = true
+		default:
+			return false
+		}
+	}
+
+	return found
+}
+
+func (d *Dynamo) Read(
+	ctx context.Context,
+	from time.Time,
+	to time.Time) ([]Count, error) {
+
+	var keys []map[string]*dynamodb.AttributeValue
+
+	for _, bucket := range buckets(from, to) {
+		for shard := 0; shard < counterShards; shard++ {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				bucketAttribute: {S: aws.String(counterKey(bucket, shard))},
+			})
+		}
+	}
+
+	sums := make(map[key]int64)
+	g, ctx := errgroup.WithContext(ctx)
+	var mutex sync.Mutex
+
+	for start := 0; start < len(keys); start += maxBatchGets {
+		end := start + maxBatchGets
+
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		chunk := keys[start:end]
+
+		g.Go(func() error {
+			items, err := d.load(ctx, chunk)
+
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for _, item := range items {
+				addItemCounts(sums, item)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	counts := make([]Count, 0, len(sums))
+
+	for k, n := range sums {
+		counts = append(counts, Count{
+			Bucket: time.Unix(k.bucket, 0),
+			Status: k.status,
+			Host:   k.host,
+			Count:  n,
+		})
+	}
+
+	sortCounts(counts)
+
+	return counts, nil
+}
+
+func (d *Dynamo) load(
+	ctx context.Context,
+	keys []map[string]*dynamodb.AttributeValue) (
+	[]map[string]*dynamodb.AttributeValue, error) {
+
+	var items []map[string]*dynamodb.AttributeValue
+	backoff := batchGetBackoff
+
+	for attempt := 0; ; attempt++ {
+		res, err := d.dynamodb.BatchGetItemWithContext(
+			ctx,
+			&dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					d.table: {Keys: keys},
+				},
+				ReturnConsumedCapacity: aws.String(
+					dynamodb.ReturnConsumedCapacityNone),
+			})
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, res.Responses[d.table]...)
+
+		uk, ok := res.UnprocessedKeys[d.table]
+
+		if !ok || len(uk.Keys) == 0 {
+			return items, nil
+		}
+
+		if attempt == maxBatchGetRetry {
+			return nil, errUnprocessedKeys
+		}
+
+		keys = uk.Keys
+		d.clock.Wait(ctx, backoff)
+		backoff *= 2
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// addItemCounts sums the counts of a counter shard into the ones of its
+// bucket.
+func addItemCounts(
+	sums map[key]int64,
+	item map[string]*dynamodb.AttributeValue) {
+
+	start, _, _ := strings.Cut(aws.StringValue(item[bucketAttribute].S), "#")
+	bucket, _ := strconv.ParseInt(start, 10, 64)
+
+	for attribute, value := range item {
+		status, host, found := strings.Cut(attribute, "#")
+
+		if !found || value.N == nil {
+			continue
+		}
+
+		n, _ := strconv.ParseInt(*value.N, 10, 64)
+		sums[key{bucket, status, host}] += n
+	}
+}
@@ -0,0 +1,361 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dynamo", func() {
+	const table = "scheduler-stats-v1"
+
+	var (
+		db    *dynamotest.DynamoDB
+		fake  *clock.Fake
+		store *Dynamo
+	)
+
+	at := time.Unix(1600003200, 0)
+
+	BeforeEach(func() {
+		db = dynamotest.New(
+			dynamotest.StatsTable(table),
+			dynamotest.Table{Name: "scheduler-v1", HashKey: "id"})
+		fake = clock.NewFake(at)
+		store = NewDynamo(db, table, fake)
+	})
+
+	It("reads added counts", func() {
+		Expect(store.Add(context.TODO(), []Transition{
+			{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+			{Status: "IDLE", URL: "https://baz.qux/do", At: at},
+			{Status: "FAILED", URL: "https://foo.bar/do", At: at},
+			{
+				Status: "IDLE",
+				URL:    "https://foo.bar/do",
+				At:     at.Add(time.Hour),
+			},
+		})).To(Succeed())
+
+		Expect(store.Add(context.TODO(), []Transition{
+			{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+		})).To(Succeed())
+
+		Expect(store.Read(context.TODO(), at, at.Add(time.Hour))).To(
+			Equal([]Count{
+				{time.Unix(1600002000, 0), "FAILED", "foo.bar", 1},
+				{time.Unix(1600002000, 0), "IDLE", "baz.qux", 1},
+				{time.Unix(1600002000, 0), "IDLE", "foo.bar", 2},
+				{time.Unix(1600005600, 0), "IDLE", "foo.bar", 1},
+			}))
+	})
+
+	It("reads only buckets of range", func() {
+		Expect(store.Add(context.TODO(), []Transition{
+			{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+		})).To(Succeed())
+
+		Expect(store.Read(
+			context.TODO(),
+			at.Add(time.Hour),
+			at.Add(2*time.Hour))).To(BeEmpty())
+	})
+
+	It("reads more buckets than a batch", func() {
+		Expect(store.Add(context.TODO(), []Transition{
+			{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+			{
+				Status: "IDLE",
+				URL:    "https://foo.bar/do",
+				At:     at.Add(200 * time.Hour),
+			},
+		})).To(Succeed())
+
+		Expect(store.Read(
+			context.TODO(),
+			at,
+			at.Add(200*time.Hour))).To(HaveLen(2))
+	})
+
+	It("adds nothing without transitions", func() {
+		Expect(store.Add(context.TODO(), nil)).To(Succeed())
+	})
+
+	Context("transact", func() {
+		put := func(cond string) *dynamodb.TransactWriteItem {
+			p := &dynamodb.Put{
+				TableName: aws.String("scheduler-v1"),
+				Item: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+			}
+
+			if cond != "" {
+				p.ConditionExpression = aws.String(cond)
+			}
+
+			return &dynamodb.TransactWriteItem{Put: p}
+		}
+
+		It("writes items with counts", func() {
+			Expect(store.Transact(
+				context.TODO(),
+				[]*dynamodb.TransactWriteItem{put("")},
+				[]Transition{
+					{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+					{
+						Status: "IDLE",
+						URL:    "https://foo.bar/do",
+						At:     at.Add(time.Hour),
+					},
+				})).To(Succeed())
+
+			Expect(store.Read(context.TODO(), at, at.Add(time.Hour))).To(
+				HaveLen(2))
+
+			res, _ := db.GetItem(&dynamodb.GetItemInput{
+				TableName: aws.String("scheduler-v1"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String("1")},
+				},
+			})
+
+			Expect(res.Item).NotTo(BeNil())
+		})
+
+		It("counts nothing when items fail", func() {
+			Expect(store.Transact(
+				context.TODO(),
+				[]*dynamodb.TransactWriteItem{put("")},
+				nil)).To(Succeed())
+
+			err := store.Transact(
+				context.TODO(),
+				[]*dynamodb.TransactWriteItem{
+					put("attribute_not_exists(id)"),
+				},
+				[]Transition{
+					{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+				})
+
+			Expect(err).To(
+				BeAssignableToTypeOf(&dynamodb.TransactionCanceledException{}))
+			Expect(store.Read(context.TODO(), at, at)).To(BeEmpty())
+		})
+
+		It("spreads concurrent writers of the same hour", func() {
+			contended := &contendedDynamoDB{
+				DynamoDBAPI: db,
+				inFlight:    make(map[string]bool),
+			}
+			store = NewDynamo(contended, table, clock.System)
+
+			const writers = 16
+			errs := make(chan error, writers)
+
+			for i := 0; i < writers; i++ {
+				go func() {
+					errs <- store.Transact(context.TODO(), nil, []Transition{{
+						Status: "SUCCEEDED",
+						URL:    "https://foo.bar/do",
+						At:     at,
+					}})
+				}()
+			}
+
+			for i := 0; i < writers; i++ {
+				Eventually(errs, 5*time.Second).Should(Receive(BeNil()))
+			}
+
+			Expect(store.Read(context.TODO(), at, at)).To(Equal([]Count{
+				{time.Unix(1600002000, 0), "SUCCEEDED", "foo.bar", writers},
+			}))
+		})
+
+		It("retries conflicts with backoff", func() {
+			conflicting := &conflictingDynamoDB{DynamoDBAPI: db, conflicts: 1}
+			store = NewDynamo(conflicting, table, fake)
+
+			done := make(chan error, 1)
+
+			go func() {
+				done <- store.Transact(context.TODO(), nil, []Transition{
+					{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+				})
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+			fake.Advance(transactBackoff)
+
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(store.Read(context.TODO(), at, at)).To(HaveLen(1))
+		})
+	})
+
+	Context("unprocessed keys", func() {
+		It("retries them with backoff", func() {
+			Expect(store.Add(context.TODO(), []Transition{
+				{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+			})).To(Succeed())
+
+			store = NewDynamo(
+				&unprocessedDynamoDB{DynamoDBAPI: db, unprocessed: 1},
+				table,
+				fake)
+
+			done := make(chan []Count, 1)
+
+			go func() {
+				defer GinkgoRecover()
+
+				counts, err := store.Read(context.TODO(), at, at)
+
+				Expect(err).NotTo(HaveOccurred())
+				done <- counts
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+			fake.Advance(batchGetBackoff)
+
+			Eventually(done).Should(Receive(HaveLen(1)))
+		})
+
+		It("fails after retries", func() {
+			store = NewDynamo(
+				&unprocessedDynamoDB{DynamoDBAPI: db, unprocessed: -1},
+				table,
+				fake)
+
+			done := make(chan error, 1)
+
+			go func() {
+				_, err := store.Read(context.TODO(), at, at)
+				done <- err
+			}()
+
+			backoff := batchGetBackoff
+
+			for i := 0; i < maxBatchGetRetry; i++ {
+				Eventually(fake.Waiters).Should(Equal(1))
+				fake.Advance(backoff)
+				backoff *= 2
+			}
+
+			Eventually(done).Should(Receive(Equal(errUnprocessedKeys)))
+		})
+	})
+})
+
+// contendedDynamoDB cancels a transaction updating an item another one in
+// flight updates, like DynamoDB does.
+type contendedDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mutex    sync.Mutex
+	inFlight map[string]bool
+}
+
+func (db *contendedDynamoDB) TransactWriteItemsWithContext(
+	ctx aws.Context,
+	input *dynamodb.TransactWriteItemsInput,
+	opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+
+	var keys []string
+
+	for _, item := range input.TransactItems {
+		if item.Update != nil {
+			keys = append(keys, aws.StringValue(item.Update.Key["bucket"].S))
+		}
+	}
+
+	db.mutex.Lock()
+
+	for _, k := range keys {
+		if db.inFlight[k] {
+			db.mutex.Unlock()
+
+			return nil, &dynamodb.TransactionCanceledException{
+				CancellationReasons: []*dynamodb.CancellationReason{
+					{Code: aws.String("TransactionConflict")},
+				},
+			}
+		}
+	}
+
+	for _, k := range keys {
+		db.inFlight[k] = true
+	}
+
+	db.mutex.Unlock()
+
+	defer func() {
+		db.mutex.Lock()
+		defer db.mutex.Unlock()
+
+		for _, k := range keys {
+			delete(db.inFlight, k)
+		}
+	}()
+
+	// Holds the items long enough for the other writers to meet them.
+	time.Sleep(5 * time.Millisecond)
+
+	return db.DynamoDBAPI.TransactWriteItemsWithContext(ctx, input, opts...)
+}
+
+type conflictingDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	conflicts int
+}
+
+func (db *conflictingDynamoDB) TransactWriteItemsWithContext(
+	ctx aws.Context,
+	input *dynamodb.TransactWriteItemsInput,
+	opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+
+	if db.conflicts > 0 {
+		db.conflicts--
+
+		return nil, &dynamodb.TransactionCanceledException{
+			CancellationReasons: []*dynamodb.CancellationReason{
+				{Code: aws.String("TransactionConflict")},
+			},
+		}
+	}
+
+	return db.DynamoDBAPI.TransactWriteItemsWithContext(ctx, input, opts...)
+}
+
+// unprocessedDynamoDB leaves all keys unprocessed the given times, always
+// when negative.
+type unprocessedDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	unprocessed int
+}
+
+func (db *unprocessedDynamoDB) BatchGetItemWithContext(
+	ctx aws.Context,
+	input *dynamodb.BatchGetItemInput,
+	opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+
+	if db.unprocessed != 0 {
+		db.unprocessed--
+
+		return &dynamodb.BatchGetItemOutput{
+			UnprocessedKeys: input.RequestItems,
+		}, nil
+	}
+
+	return db.DynamoDBAPI.BatchGetItemWithContext(ctx, input, opts...)
+}
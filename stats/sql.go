@@ -0,0 +1,101 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Querier is a database or a transaction of one.
+type Querier interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}
+
+// SQL keeps the counts in the schedule_stats table of the graphql
+// migrations, given the transaction of a transition they are added with it.
+type SQL struct {
+	db Querier
+}
+
+func NewSQL(db Querier) *SQL {
+	return &SQL{db}
+}
+
+func (s *SQL) Add(ctx context.Context, transitions []Transition) error {
+	counts := group(transitions)
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	var (
+		rows []string
+		args []interface{}
+	)
+
+	for _, k := range sortedKeys(counts) {
+		index := len(args)
+		rows = append(rows, fmt.Sprintf(
+			"($%d, $%d, $%d, $%d)",
+			index+1,
+			index+2,
+			index+3,
+			index+4))
+		args = append(args, k.bucket, k.status, k.host, counts[k])
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO schedule_stats (bucket, status, host, count)
+			VALUES %s
+			ON CONFLICT (bucket, status, host)
+			DO UPDATE SET count = schedule_stats.count + excluded.count`,
+			strings.Join(rows, ", ")),
+		args...)
+
+	return err
+}
+
+func (s *SQL) Read(
+	ctx context.Context,
+	from time.Time,
+	to time.Time) ([]Count, error) {
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT bucket, status, host, count FROM schedule_stats
+		WHERE bucket BETWEEN $1 AND $2
+		ORDER BY bucket, status, host`,
+		bucketOf(from),
+		bucketOf(to))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var counts []Count
+
+	for rows.Next() {
+		var (
+			c      Count
+			bucket int64
+		)
+
+		if err = rows.Scan(&bucket, &c.Status, &c.Host, &c.Count); err != nil {
+			return nil, err
+		}
+
+		c.Bucket = time.Unix(bucket, 0)
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL", func() {
+	var (
+		conn  *sql.DB
+		mock  sqlmock.Sqlmock
+		store *SQL
+	)
+
+	at := time.Unix(1600003200, 0)
+
+	BeforeEach(func() {
+		var err error
+
+		conn, mock, err = sqlmock.New()
+		Expect(err).To(BeNil())
+
+		store = NewSQL(conn)
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		_ = conn.Close()
+	})
+
+	Describe("Add", func() {
+		It("upserts summed counts in order", func() {
+			mock.ExpectExec(regexp.QuoteMeta(
+				"VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)")).
+				WithArgs(
+					int64(1600002000), "FAILED", "foo.bar", int64(1),
+					int64(1600002000), "IDLE", "foo.bar", int64(2)).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			Expect(store.Add(context.TODO(), []Transition{
+				{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+				{Status: "FAILED", URL: "https://foo.bar/do", At: at},
+				{Status: "IDLE", URL: "https://foo.bar/do", At: at},
+			})).To(Succeed())
+		})
+
+		It("adds nothing without transitions", func() {
+			Expect(store.Add(context.TODO(), nil)).To(Succeed())
+		})
+	})
+
+	Describe("Read", func() {
+		It("returns counts of buckets of range", func() {
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"WHERE bucket BETWEEN $1 AND $2")).
+				WithArgs(int64(1600002000), int64(1600005600)).
+				WillReturnRows(sqlmock.NewRows(
+					[]string{"bucket", "status", "host", "count"}).
+					AddRow(1600002000, "IDLE", "foo.bar", 3))
+
+			Expect(store.Read(context.TODO(), at, at.Add(time.Hour))).To(
+				Equal([]Count{
+					{time.Unix(1600002000, 0), "IDLE", "foo.bar", 3},
+				}))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+// Package stats counts the schedules reaching a final status per
+// destination host and hour, so the counts of a range are read without
+// scanning the schedules.
+package stats
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// BucketSize is the span of time a count covers.
+const BucketSize = time.Hour
+
+// Transition is a schedule of the url reaching the status at the time.
+type Transition struct {
+	Status string
+	URL    string
+	At     time.Time
+}
+
+// Count is the number of schedules of the host that reached the status
+// within the bucket starting at the time.
+type Count struct {
+	Bucket time.Time
+	Status string
+	Host   string
+	Count  int64
+}
+
+type Store interface {
+	// Add counts the transitions, atomically for each bucket.
+	Add(context.Context, []Transition) error
+
+	// Read returns the counts of the buckets the range touches, ordered by
+	// bucket, status and host.
+	Read(ctx context.Context, from, to time.Time) ([]Count, error)
+}
+
+type key struct {
+	bucket int64
+	status string
+	host   string
+}
+
+// group sums the transitions of the same bucket, status and host.
+func group(transitions []Transition) map[key]int64 {
+	counts := make(map[key]int64)
+
+	for _, t := range transitions {
+		counts[key{bucketOf(t.At), t.Status, hostOf(t.URL)}]++
+	}
+
+	return counts
+}
+
+// sortedKeys orders the keys, so concurrent writers lock the same rows in
+// the same order.
+func sortedKeys(counts map[key]int64) []key {
+	keys := make([]key, 0, len(counts))
+
+	for k := range counts {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+
+		if a.bucket != b.bucket {
+			return a.bucket < b.bucket
+		}
+
+		if a.status != b.status {
+			return a.status < b.status
+		}
+
+		return a.host < b.host
+	})
+
+	return keys
+}
+
+func bucketOf(t time.Time) int64 {
+	return t.Truncate(BucketSize).Unix()
+}
+
+// buckets are the starts of the buckets from and to are in.
+func buckets(from, to time.Time) []int64 {
+	var starts []int64
+	step := int64(BucketSize.Seconds())
+
+	for b := bucketOf(from); b <= bucketOf(to); b += step {
+		starts = append(starts, b)
+	}
+
+	return starts
+}
+
+// hostOf is the host name of the url without the port, empty when it is not
+// a url.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+func sortCounts(counts []Count) {
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+
+		if !a.Bucket.Equal(b.Bucket) {
+			return a.Bucket.Before(b.Bucket)
+		}
+
+		if a.Status != b.Status {
+			return a.Status < b.Status
+		}
+
+		return a.Host < b.Host
+	})
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	at := time.Unix(1600003200, 0)
+
+	Describe("group", func() {
+		It("sums transitions of same bucket, status and host", func() {
+			counts := group([]Transition{
+				{Status: "FAILED", URL: "https://foo.bar/do", At: at},
+				{Status: "FAILED", URL: "https://foo.bar:8443/x", At: at},
+				{Status: "FAILED", URL: "https://baz.qux/do", At: at},
+				{
+					Status: "FAILED",
+					URL:    "https://foo.bar/do",
+					At:     at.Add(time.Hour),
+				},
+			})
+
+			Expect(counts).To(Equal(map[key]int64{
+				{1600002000, "FAILED", "foo.bar"}: 2,
+				{1600002000, "FAILED", "baz.qux"}: 1,
+				{1600005600, "FAILED", "foo.bar"}: 1,
+			}))
+		})
+	})
+
+	Describe("buckets", func() {
+		It("returns every bucket the range touches", func() {
+			Expect(buckets(at, at.Add(2*time.Hour))).To(Equal([]int64{
+				1600002000,
+				1600005600,
+				1600009200,
+			}))
+		})
+	})
+
+	Describe("hostOf", func() {
+		It("returns empty host of invalid url", func() {
+			Expect(hostOf("%%")).To(BeEmpty())
+		})
+	})
+})
@@ -34,15 +34,15 @@ const Api = {
         variables.dueAt = model.dueAt;
       }
 
-      if (model.startKey) {
-        variables.startKey = model.startKey;
+      if (model.cursor) {
+        variables.cursor = model.cursor;
       }
     }
 
     const body = {
       query: `
-        query List($status: ScheduleStatus, $dueAt: DateRange, $startKey: ScheduleListStartKey) {
-          list(status: $status, dueAt: $dueAt, startKey: $startKey) {
+        query List($status: ScheduleStatus, $dueAt: DateRange, $cursor: String) {
+          list(status: $status, dueAt: $dueAt, cursor: $cursor) {
             schedules {
               id
               dueAt
@@ -50,11 +50,7 @@ const Api = {
               method
               status
             }
-            nextKey {
-              id,
-              dueAt,
-              status
-            }
+            cursor
           }
         }
       `,
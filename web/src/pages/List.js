@@ -60,7 +60,7 @@ const List = () => {
   const [sortColumn, setSortColumn] = useState('dueAt');
   const [sortDirection, setSortDirection] = useState('desc');
   const [list, setList] = useState(null);
-  const [startKey, setStartKey] = useState(null);
+  const [cursor, setCursor] = useState(null);
   const table = useRef();
 
   const sort = (target, column, direction) => {
@@ -81,9 +81,9 @@ const List = () => {
 
   useEffect(() => {
     (async () => {
-      const { schedules, nextKey } = await Api.list();
+      const { schedules, cursor: nextCursor } = await Api.list();
       setList(schedules);
-      setStartKey(nextKey);
+      setCursor(nextCursor);
     })();
   }, []);
 
@@ -125,9 +125,9 @@ const List = () => {
         };
       }
 
-      const { schedules, nextKey } = await Api.list(model);
+      const { schedules, cursor: nextCursor } = await Api.list(model);
       sort(schedules, { column: sortColumn, direction: sortDirection });
-      setStartKey(nextKey);
+      setCursor(nextCursor);
     }
   });
 
@@ -148,9 +148,9 @@ const List = () => {
     await setFieldValue('from', null, false);
     await setFieldValue('to', null, false);
 
-    const { schedules, nextKey } = await Api.list();
+    const { schedules, cursor: nextCursor } = await Api.list();
     sort(schedules, { column: sortColumn, direction: sortDirection });
-    setStartKey(nextKey);
+    setCursor(nextCursor);
   };
 
   const handleSort = (column) => () => {
@@ -167,7 +167,7 @@ const List = () => {
   const handleScroll = debounce((e) => {
     const [rowHeight, noOfRows] = [72, 3];
 
-    if (!startKey) {
+    if (!cursor) {
       return;
     }
 
@@ -183,7 +183,7 @@ const List = () => {
 
     (async () => {
       const model = {
-        startKey
+        cursor
       };
 
       if (values.status !== Statuses[0]) {
@@ -197,10 +197,10 @@ const List = () => {
         };
       }
 
-      const { schedules, nextKey } = await Api.list(model);
+      const { schedules, cursor: nextCursor } = await Api.list(model);
       const updatedList = [...list, ...schedules];
       sort(updatedList, sortColumn, sortDirection);
-      setStartKey(nextKey);
+      setCursor(nextCursor);
     })();
   }, 400);
 
@@ -3,10 +3,17 @@ module github.com/kazimanzurrashid/aws-scheduler-go/worker
 go 1.20
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/aws/aws-lambda-go v1.47.0
 	github.com/aws/aws-sdk-go v1.53.14
 	github.com/aws/aws-xray-sdk-go v1.8.4
 	github.com/hashicorp/go-retryablehttp v0.7.7
+	github.com/kazimanzurrashid/aws-scheduler-go/blob v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/clock v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/dynamotest v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/sharding v0.0.0
+	github.com/kazimanzurrashid/aws-scheduler-go/stats v0.0.0
+	github.com/lib/pq v1.10.9
 	github.com/onsi/ginkgo v1.16.5
 	github.com/onsi/gomega v1.33.1
 	golang.org/x/sync v0.7.0
@@ -34,3 +41,11 @@ require (
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/kazimanzurrashid/aws-scheduler-go/blob => ../blob
+	github.com/kazimanzurrashid/aws-scheduler-go/clock => ../clock
+	github.com/kazimanzurrashid/aws-scheduler-go/dynamotest => ../dynamotest
+	github.com/kazimanzurrashid/aws-scheduler-go/sharding => ../sharding
+	github.com/kazimanzurrashid/aws-scheduler-go/stats => ../stats
+)
@@ -2,24 +2,45 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-xray-sdk-go/xray"
 
 	"github.com/hashicorp/go-retryablehttp"
+	_ "github.com/lib/pq"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
 	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
 )
 
+const (
+	defaultReserve     = 10 * time.Second
+	defaultTimeout     = 60
+	defaultConcurrency = 4
+)
+
 var (
+	ses        *session.Session
 	httpClient services.Client
 	database   services.Storage
+	blobs      blob.Store
+	reserve    = defaultReserve
 )
 
 func handler(ctx context.Context, e events.DynamoDBEvent) error {
@@ -41,46 +62,209 @@ func handler(ctx context.Context, e events.DynamoDBEvent) error {
 		return nil
 	}
 
-	var wg sync.WaitGroup
+	ris := make([]*services.RequestInput, len(queuedRecords))
 	uis := make([]*services.UpdateInput, len(queuedRecords))
 
 	for i, record := range queuedRecords {
-		wg.Add(1)
+		ris[i] = services.CreateRequestInput(record.Change.NewImage)
+		uis[i] = services.CreateUpdateInput(record.Change.NewImage)
+	}
 
-		go func(attrs map[string]events.DynamoDBAttributeValue, index int) {
-			defer wg.Done()
+	return dispatch(ctx, ris, uis)
+}
+
+// queueHandler works the schedules whose ids the collector sent to the
+// queue, the ones that are no longer queued were already worked.
+func queueHandler(ctx context.Context, e events.SQSEvent) error {
+	ids := make([]string, len(e.Records))
+
+	for i, record := range e.Records {
+		ids[i] = record.Body
+	}
+
+	uis, err := database.Load(ctx, ids)
+
+	if err != nil {
+		return err
+	}
+
+	if len(uis) == 0 {
+		return nil
+	}
+
+	ris := make([]*services.RequestInput, len(uis))
 
-			ri := services.CreateRequestInput(attrs)
-			ui := services.CreateUpdateInput(attrs)
+	for i, ui := range uis {
+		ris[i] = services.NewRequestInput(ui)
+	}
+
+	return dispatch(ctx, ris, uis)
+}
 
-			ui.StartedAt = aws.Int64(time.Now().Unix())
+func dispatch(
+	ctx context.Context,
+	ris []*services.RequestInput,
+	uis []*services.UpdateInput) error {
 
-			ro := httpClient.Request(ctx, ri)
+	for i := range uis {
+		if err := services.LoadBody(ctx, blobs, ris[i], uis[i]); err != nil {
+			return err
+		}
+	}
 
-			ui.Status = ro.Status
-			ui.Result = aws.String(ro.Result)
-			ui.CompletedAt = aws.Int64(time.Now().Unix())
+	workCtx, cancel := services.WithReserve(ctx, reserve)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	for i := range uis {
+		wg.Add(1)
 
-			uis[index] = ui
-		}(record.Change.NewImage, i)
+		go func(index int) {
+			defer wg.Done()
+
+			uis[index] = services.Dispatch(
+				workCtx,
+				clock.System,
+				httpClient,
+				ris[index],
+				uis[index])
+		}(i)
 	}
 
 	wg.Wait()
 
+	// Results are persisted with the invocation context, the reserved time
+	// is what is left for this write once the work context is done.
 	return database.Update(ctx, uis)
 }
 
+func daemonMode() bool {
+	return os.Getenv("SCHEDULER_WORKER_DAEMON") == "true"
+}
+
+// trace records the calls of the client in the lambda segment, a daemon has
+// no segment to record them in.
+func trace(c *client.Client) {
+	if !daemonMode() {
+		xray.AWS(c)
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+
+	return fallback
+}
+
 func init() {
-	ses := session.Must(session.NewSession())
+	ses = session.Must(session.NewSession())
 
 	ddbc := dynamodb.New(ses)
-	xray.AWS(ddbc.Client)
+	trace(ddbc.Client)
+
+	if seconds, err := strconv.ParseInt(
+		os.Getenv("SCHEDULER_WORKER_RESERVE_SECONDS"), 10, 64); err == nil &&
+		seconds >= 0 {
+		reserve = time.Duration(seconds) * time.Second
+	}
+
+	if bucket := os.Getenv("SCHEDULER_BLOB_BUCKET"); bucket != "" {
+		s3c := s3.New(ses)
+		trace(s3c.Client)
+
+		blobs = blob.NewS3Store(s3c, bucket)
+	} else if dir := os.Getenv("SCHEDULER_BLOB_DIR"); dir != "" {
+		blobs = blob.NewFileStore(dir)
+	}
+
+	if os.Getenv("SCHEDULER_STORAGE") == "postgres" {
+		db, err := sql.Open("postgres", os.Getenv("SCHEDULER_DATABASE_URL"))
+
+		if err != nil {
+			log.Fatalf("storage create error: %v", err)
+			return
+		}
+
+		database = services.NewPostgres(db)
+	} else {
+		database = services.NewDatabase(ddbc, clock.System, blobs)
+	}
+
+	standard := retryablehttp.NewClient().StandardClient()
+
+	if !daemonMode() {
+		standard = xray.Client(standard)
+	}
+
+	httpClient = services.NewHttpClient(standard)
 
-	database = services.NewDatabase(ddbc)
-	httpClient = services.NewHttpClient(
-		xray.Client(retryablehttp.NewClient().StandardClient()))
+	if os.Getenv("SCHEDULER_CIRCUIT_TABLE_NAME") != "" {
+		threshold, _ := strconv.ParseInt(
+			os.Getenv("SCHEDULER_CIRCUIT_FAILURE_THRESHOLD"), 10, 64)
+		cooldown, _ := strconv.ParseInt(
+			os.Getenv("SCHEDULER_CIRCUIT_COOLDOWN_SECONDS"), 10, 64)
+
+		httpClient = services.NewBreakerClient(
+			httpClient,
+			services.NewCircuitBreaker(
+				ddbc,
+				clock.System,
+				threshold,
+				cooldown))
+	}
+}
+
+// serve runs the worker as a long running process instead of a lambda, it
+// polls the queue and on SIGTERM stops receiving and finishes the batches in
+// flight before it exits.
+func serve() {
+	url := os.Getenv("SCHEDULER_QUEUE_URL")
+
+	if url == "" {
+		log.Fatalf("worker daemon requires SCHEDULER_QUEUE_URL")
+		return
+	}
+
+	config := aws.NewConfig()
+
+	if endpoint := os.Getenv("SCHEDULER_QUEUE_ENDPOINT"); endpoint != "" {
+		config = config.WithEndpoint(endpoint)
+	}
+
+	p := &poller{
+		sqs:   sqs.New(ses, config),
+		clock: clock.System,
+		url:   url,
+		timeout: time.Duration(envInt(
+			"SCHEDULER_WORKER_TIMEOUT_SECONDS",
+			defaultTimeout)) * time.Second,
+		handle: queueHandler,
+	}
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM)
+	defer stop()
+
+	p.run(ctx, envInt("SCHEDULER_WORKER_CONCURRENCY", defaultConcurrency))
 }
 
 func main() {
+	if daemonMode() {
+		serve()
+		return
+	}
+
+	// The collector sends to the queue instead of the table stream feeding
+	// the worker when a queue is configured.
+	if os.Getenv("SCHEDULER_QUEUE_URL") != "" {
+		lambda.Start(queueHandler)
+		return
+	}
+
 	lambda.Start(handler)
 }
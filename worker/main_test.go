@@ -2,8 +2,17 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
 
 	"github.com/kazimanzurrashid/aws-scheduler-go/worker/services"
 
@@ -112,27 +121,563 @@ var _ = Describe("handler", func() {
 		}
 	})
 
+	It("records firing lag of completed schedules", func() {
+		for _, input := range fs.Inputs {
+			Expect(*input.Lag).To(BeNumerically(">", 0))
+		}
+	})
+
+	It("does not return error", func() {
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("handler with open circuit", func() {
+	var (
+		fs  fakeStorage
+		err error
+	)
+
+	BeforeEach(func() {
+		httpClient = &fakeClient{
+			Output: &services.ResponseOutput{
+				Status:        services.ScheduleStatusIdle,
+				Reason:        services.ScheduleReasonDeferred,
+				DeferredUntil: 9876600,
+			},
+		}
+
+		fs = fakeStorage{}
+		database = &fs
+
+		err = handler(context.TODO(), events.DynamoDBEvent{
+			Records: []events.DynamoDBEventRecord{
+				{
+					EventName: "MODIFY",
+					Change: events.DynamoDBStreamRecord{
+						NewImage: map[string]events.DynamoDBAttributeValue{
+							"id":    events.NewStringAttribute("1234"),
+							"dueAt": events.NewNumberAttribute("9876543"),
+							"url": events.NewStringAttribute(
+								"https://foo.bar/do"),
+							"method":    events.NewStringAttribute("POST"),
+							"createdAt": events.NewNumberAttribute("343334232"),
+							"status": events.NewStringAttribute(
+								services.ScheduleStatusQueued),
+						},
+					},
+				},
+			},
+		})
+	})
+
+	It("puts schedule back to idle", func() {
+		Expect(fs.Inputs[0].Status).To(Equal(services.ScheduleStatusIdle))
+	})
+
+	It("moves dueAt to when circuit closes", func() {
+		Expect(fs.Inputs[0].DueAt).To(BeEquivalentTo(9876600))
+	})
+
+	It("sets deferred reason", func() {
+		Expect(*fs.Inputs[0].Reason).To(Equal(services.ScheduleReasonDeferred))
+	})
+
+	It("does not set startedAt, completedAt and lag", func() {
+		Expect(fs.Inputs[0].StartedAt).To(BeNil())
+		Expect(fs.Inputs[0].CompletedAt).To(BeNil())
+		Expect(fs.Inputs[0].Lag).To(BeNil())
+	})
+
+	It("does not return error", func() {
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("handler with missed deadline", func() {
+	var (
+		fc  fakeClient
+		fs  fakeStorage
+		err error
+	)
+
+	BeforeEach(func() {
+		fc = fakeClient{}
+		httpClient = &fc
+
+		fs = fakeStorage{}
+		database = &fs
+
+		err = handler(context.TODO(), events.DynamoDBEvent{
+			Records: []events.DynamoDBEventRecord{
+				{
+					EventName: "MODIFY",
+					Change: events.DynamoDBStreamRecord{
+						NewImage: map[string]events.DynamoDBAttributeValue{
+							"id":    events.NewStringAttribute("1234"),
+							"dueAt": events.NewNumberAttribute("9876543"),
+							"url": events.NewStringAttribute(
+								"https://foo.bar/do"),
+							"method":    events.NewStringAttribute("POST"),
+							"createdAt": events.NewNumberAttribute("343334232"),
+							"deadline":  events.NewNumberAttribute("9876603"),
+							"status": events.NewStringAttribute(
+								services.ScheduleStatusQueued),
+						},
+					},
+				},
+			},
+		})
+	})
+
+	It("does not fire the schedule", func() {
+		Expect(fc.Called).To(BeFalse())
+	})
+
+	It("marks schedule expired", func() {
+		Expect(fs.Inputs[0].Status).To(Equal(services.ScheduleStatusExpired))
+	})
+
+	It("records firing lag", func() {
+		Expect(*fs.Inputs[0].Lag).To(BeNumerically(">", 60000))
+	})
+
+	It("does not return error", func() {
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("handler near invocation deadline", func() {
+	var (
+		fc  fakeClient
+		fs  fakeStorage
+		err error
+	)
+
+	BeforeEach(func() {
+		fc = fakeClient{}
+		httpClient = &fc
+
+		fs = fakeStorage{}
+		database = &fs
+
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+		defer cancel()
+
+		err = handler(ctx, events.DynamoDBEvent{
+			Records: []events.DynamoDBEventRecord{
+				{
+					EventName: "MODIFY",
+					Change: events.DynamoDBStreamRecord{
+						NewImage: map[string]events.DynamoDBAttributeValue{
+							"id":    events.NewStringAttribute("1234"),
+							"dueAt": events.NewNumberAttribute("9876543"),
+							"url": events.NewStringAttribute(
+								"https://foo.bar/do"),
+							"method":    events.NewStringAttribute("POST"),
+							"createdAt": events.NewNumberAttribute("343334232"),
+							"status": events.NewStringAttribute(
+								services.ScheduleStatusQueued),
+						},
+					},
+				},
+			},
+		})
+	})
+
+	It("does not fire the schedule", func() {
+		Expect(fc.Called).To(BeFalse())
+	})
+
+	It("puts schedule back to idle", func() {
+		Expect(fs.Inputs[0].Status).To(Equal(services.ScheduleStatusIdle))
+	})
+
+	It("keeps dueAt", func() {
+		Expect(fs.Inputs[0].DueAt).To(BeEquivalentTo(9876543))
+	})
+
+	It("sets interrupted reason", func() {
+		Expect(*fs.Inputs[0].Reason).To(
+			Equal(services.ScheduleReasonInterrupted))
+	})
+
 	It("does not return error", func() {
 		Expect(err).To(BeNil())
 	})
 })
 
+var _ = Describe("queueHandler", func() {
+	var (
+		fc  fakeClient
+		fs  fakeStorage
+		err error
+	)
+
+	event := func(ids ...string) events.SQSEvent {
+		var e events.SQSEvent
+
+		for _, id := range ids {
+			e.Records = append(e.Records, events.SQSMessage{Body: id})
+		}
+
+		return e
+	}
+
+	BeforeEach(func() {
+		fc = fakeClient{
+			Output: &services.ResponseOutput{
+				Status: services.ScheduleStatusSucceeded,
+				Result: "dummy result",
+			},
+		}
+		fs = fakeStorage{}
+
+		httpClient = &fc
+		database = &fs
+	})
+
+	Context("queued schedules", func() {
+		BeforeEach(func() {
+			fs.LoadOutput = []*services.UpdateInput{
+				{
+					ID:     "1234",
+					DueAt:  9876543,
+					URL:    "https://foo.bar/do",
+					Method: "POST",
+				},
+			}
+
+			err = queueHandler(context.TODO(), event("1234", "5678"))
+		})
+
+		It("loads schedules of message ids", func() {
+			Expect(fs.LoadIDs).To(Equal([]string{"1234", "5678"}))
+		})
+
+		It("sends request", func() {
+			Expect(fc.Called).To(BeTrue())
+		})
+
+		It("updates storage", func() {
+			Expect(fs.Inputs).To(HaveLen(1))
+			Expect(fs.Inputs[0].Status).To(
+				Equal(services.ScheduleStatusSucceeded))
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("no longer queued schedules", func() {
+		BeforeEach(func() {
+			err = queueHandler(context.TODO(), event("1234"))
+		})
+
+		It("does not send request", func() {
+			Expect(fc.Called).To(BeFalse())
+		})
+
+		It("does not update storage", func() {
+			Expect(fs.Inputs).To(BeNil())
+		})
+
+		It("does not return error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("load error", func() {
+		BeforeEach(func() {
+			fs.LoadError = fmt.Errorf("load error")
+
+			err = queueHandler(context.TODO(), event("1234"))
+		})
+
+		It("returns error for the messages to be retried", func() {
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
+
+var _ = Describe("handler against dynamotest", func() {
+	const table = "scheduler_v1"
+
+	var dynamo *dynamotest.DynamoDB
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+		dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+
+		httpClient = &fakeClient{
+			Output: &services.ResponseOutput{
+				Status: services.ScheduleStatusSucceeded,
+				Result: "dummy result",
+			},
+		}
+		database = services.NewDatabase(dynamo, clock.System, nil)
+
+		_, err := dynamo.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(table),
+			Item: map[string]*dynamodb.AttributeValue{
+				"id":        {S: aws.String("1234")},
+				"dueAt":     {N: aws.String("9876543")},
+				"url":       {S: aws.String("https://foo.bar/do")},
+				"method":    {S: aws.String("POST")},
+				"createdAt": {N: aws.String("343334232")},
+				"status":    {S: aws.String(services.ScheduleStatusIdle)},
+				"dummy":     {S: aws.String("-")},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		_, err = dynamo.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("1234")},
+			},
+			UpdateExpression: aws.String("SET #s = :s"),
+			ExpressionAttributeNames: map[string]*string{
+				"#s": aws.String("status"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":s": {S: aws.String(services.ScheduleStatusQueued)},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		Expect(handler(context.TODO(), dynamo.Stream(table))).To(Succeed())
+	})
+
+	It("completes queued schedule", func() {
+		res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("1234")},
+			},
+		})
+
+		Expect(err).To(BeNil())
+		Expect(*res.Item["status"].S).To(
+			Equal(services.ScheduleStatusSucceeded))
+		Expect(*res.Item["result"].S).To(Equal("dummy result"))
+		Expect(*res.Item["dummy"].S).To(Equal("-"))
+	})
+
+	It("does not feed completed schedule back to worker", func() {
+		records := dynamo.Stream(table).Records
+
+		Expect(records).To(HaveLen(1))
+		Expect(handler(context.TODO(), events.DynamoDBEvent{
+			Records: records,
+		})).To(Succeed())
+		Expect(dynamo.Stream(table).Records).To(BeEmpty())
+	})
+})
+
+var _ = Describe("handler with blobs against dynamotest", func() {
+	const table = "scheduler_v1"
+
+	var (
+		dynamo *dynamotest.DynamoDB
+		fc     fakeClient
+		dir    string
+	)
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+		_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "8")
+
+		var err error
+
+		dir, err = os.MkdirTemp("", "blobs")
+		Expect(err).To(BeNil())
+
+		blobs = blob.NewFileStore(dir)
+		Expect(blobs.Put(
+			context.TODO(),
+			"bodies/1234",
+			[]byte("large body"))).To(Succeed())
+
+		dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+
+		fc = fakeClient{
+			Output: &services.ResponseOutput{
+				Status: services.ScheduleStatusSucceeded,
+				Result: "large result",
+			},
+		}
+		httpClient = &fc
+		database = services.NewDatabase(dynamo, clock.System, blobs)
+
+		_, err = dynamo.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(table),
+			Item: map[string]*dynamodb.AttributeValue{
+				"id":        {S: aws.String("1234")},
+				"dueAt":     {N: aws.String("9876543")},
+				"url":       {S: aws.String("https://foo.bar/do")},
+				"method":    {S: aws.String("POST")},
+				"bodyRef":   {S: aws.String("bodies/1234")},
+				"createdAt": {N: aws.String("343334232")},
+				"status":    {S: aws.String(services.ScheduleStatusIdle)},
+				"dummy":     {S: aws.String("-")},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		_, err = dynamo.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("1234")},
+			},
+			UpdateExpression: aws.String("SET #s = :s"),
+			ExpressionAttributeNames: map[string]*string{
+				"#s": aws.String("status"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":s": {S: aws.String(services.ScheduleStatusQueued)},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		Expect(handler(context.TODO(), dynamo.Stream(table))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_BLOB_THRESHOLD_BYTES")
+		_ = os.RemoveAll(dir)
+		blobs = nil
+	})
+
+	It("sends offloaded body", func() {
+		Expect(fc.Input.Body).To(Equal("large body"))
+	})
+
+	It("offloads large result", func() {
+		res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String("1234")},
+			},
+		})
+
+		Expect(err).To(BeNil())
+		Expect(res.Item).NotTo(HaveKey("result"))
+		Expect(*res.Item["resultRef"].S).To(Equal("results/1234"))
+		Expect(*res.Item["bodyRef"].S).To(Equal("bodies/1234"))
+
+		result, err := blobs.Get(context.TODO(), "results/1234")
+
+		Expect(err).To(BeNil())
+		Expect(string(result)).To(Equal("large result"))
+	})
+})
+
+var _ = Describe("queueHandler against dynamotest", func() {
+	const table = "scheduler_v1"
+
+	var dynamo *dynamotest.DynamoDB
+
+	status := func(id string) string {
+		res, err := dynamo.GetItem(&dynamodb.GetItemInput{
+			TableName: aws.String(table),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(id)},
+			},
+		})
+		Expect(err).To(BeNil())
+
+		return *res.Item["status"].S
+	}
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+		dynamo = dynamotest.New(dynamotest.SchedulerTable(table))
+
+		httpClient = &fakeClient{
+			Output: &services.ResponseOutput{
+				Status: services.ScheduleStatusSucceeded,
+				Result: "dummy result",
+			},
+		}
+		database = services.NewDatabase(dynamo, clock.System, nil)
+
+		for id, s := range map[string]string{
+			"1": services.ScheduleStatusQueued,
+			"2": services.ScheduleStatusSucceeded,
+		} {
+			_, err := dynamo.PutItem(&dynamodb.PutItemInput{
+				TableName: aws.String(table),
+				Item: map[string]*dynamodb.AttributeValue{
+					"id":        {S: aws.String(id)},
+					"dueAt":     {N: aws.String("9876543")},
+					"url":       {S: aws.String("https://foo.bar/do")},
+					"method":    {S: aws.String("POST")},
+					"createdAt": {N: aws.String("343334232")},
+					"status":    {S: aws.String(s)},
+					"dummy":     {S: aws.String("-")},
+				},
+			})
+			Expect(err).To(BeNil())
+		}
+
+		Expect(queueHandler(context.TODO(), events.SQSEvent{
+			Records: []events.SQSMessage{
+				{Body: "1"}, {Body: "2"}, {Body: "1"}, {Body: "3"},
+			},
+		})).To(Succeed())
+	})
+
+	It("completes queued schedule", func() {
+		Expect(status("1")).To(Equal(services.ScheduleStatusSucceeded))
+	})
+
+	It("skips duplicate and no longer queued messages", func() {
+		records := dynamo.Stream(table).Records
+
+		Expect(records).To(HaveLen(3))
+		Expect(records[2].EventName).To(Equal("MODIFY"))
+		Expect(records[2].Change.Keys["id"].String()).To(Equal("1"))
+	})
+})
+
 type fakeClient struct {
 	services.Client
 
+	Called bool
+	Input  *services.RequestInput
 	Output *services.ResponseOutput
 }
 
 func (fc *fakeClient) Request(
 	_ context.Context,
-	_ *services.RequestInput) *services.ResponseOutput {
+	input *services.RequestInput) *services.ResponseOutput {
+
+	fc.Called = true
+	fc.Input = input
+
 	return fc.Output
 }
 
 type fakeStorage struct {
 	services.Storage
 
-	Inputs []*services.UpdateInput
+	LoadIDs    []string
+	LoadOutput []*services.UpdateInput
+	LoadError  error
+	Inputs     []*services.UpdateInput
+}
+
+func (fs *fakeStorage) Load(
+	_ context.Context,
+	ids []string) ([]*services.UpdateInput, error) {
+
+	fs.LoadIDs = ids
+
+	return fs.LoadOutput, fs.LoadError
 }
 
 func (fs *fakeStorage) Update(
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const (
+	maxReceive  = 10
+	receiveWait = 20
+	pollBackoff = time.Second
+)
+
+// poller feeds the queue handler outside lambda, it long polls the queue
+// and deletes the messages of every batch that was handled.
+type poller struct {
+	sqs     sqsiface.SQSAPI
+	clock   clock.Clock
+	url     string
+	timeout time.Duration
+	handle  func(context.Context, events.SQSEvent) error
+}
+
+// run polls with the given concurrency till the context is done, the batches
+// in flight by then are still worked to the end before it returns.
+func (p *poller) run(ctx context.Context, concurrency int) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				if err := p.poll(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("poll error: %v", err)
+					p.clock.Wait(ctx, pollBackoff)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (p *poller) poll(ctx context.Context) error {
+	res, err := p.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(p.url),
+		MaxNumberOfMessages: aws.Int64(maxReceive),
+		WaitTimeSeconds:     aws.Int64(receiveWait),
+		VisibilityTimeout:   aws.Int64(int64(p.timeout / time.Second)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(res.Messages) == 0 {
+		return nil
+	}
+
+	e := events.SQSEvent{
+		Records: make([]events.SQSMessage, len(res.Messages)),
+	}
+	entries := make([]*sqs.DeleteMessageBatchRequestEntry, len(res.Messages))
+
+	for i, m := range res.Messages {
+		e.Records[i] = events.SQSMessage{
+			MessageId:     aws.StringValue(m.MessageId),
+			ReceiptHandle: aws.StringValue(m.ReceiptHandle),
+			Body:          aws.StringValue(m.Body),
+		}
+		entries[i] = &sqs.DeleteMessageBatchRequestEntry{
+			Id:            aws.String(strconv.Itoa(i)),
+			ReceiptHandle: m.ReceiptHandle,
+		}
+	}
+
+	// A batch is given the time of a lambda invocation, it is not cut short
+	// by a shutdown.
+	work, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	// Messages of a failed batch are received again once they are visible.
+	if err = p.handle(work, e); err != nil {
+		return err
+	}
+
+	out, err := p.sqs.DeleteMessageBatchWithContext(
+		context.Background(),
+		&sqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(p.url),
+			Entries:  entries,
+		})
+
+	if err != nil {
+		return err
+	}
+
+	if len(out.Failed) > 0 {
+		return fmt.Errorf(
+			"%d message(s) not deleted: %s",
+			len(out.Failed),
+			aws.StringValue(out.Failed[0].Message))
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("poller", func() {
+	const url = "https://sqs.us-east-1.amazonaws.com/123/scheduler"
+
+	var (
+		fs      *fakeSQS
+		p       *poller
+		handled chan events.SQSEvent
+		release chan error
+	)
+
+	BeforeEach(func() {
+		fs = &fakeSQS{}
+		handled = make(chan events.SQSEvent, 10)
+		release = make(chan error)
+
+		p = &poller{
+			sqs:     fs,
+			clock:   clock.NewFake(time.Unix(1600000000, 0)),
+			url:     url,
+			timeout: time.Minute,
+			handle: func(ctx context.Context, e events.SQSEvent) error {
+				handled <- e
+
+				err := <-release
+
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				return err
+			},
+		}
+	})
+
+	start := func(ctx context.Context) chan struct{} {
+		done := make(chan struct{})
+
+		go func() {
+			p.run(ctx, 1)
+			close(done)
+		}()
+
+		return done
+	}
+
+	It("handles received messages and deletes them", func() {
+		fs.Push("1234", "5678")
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		start(ctx)
+
+		var e events.SQSEvent
+		Eventually(handled).Should(Receive(&e))
+
+		Expect(e.Records).To(HaveLen(2))
+		Expect(e.Records[0].Body).To(Equal("1234"))
+		Expect(e.Records[1].ReceiptHandle).To(Equal("receipt-5678"))
+
+		release <- nil
+
+		Eventually(fs.Deleted).Should(
+			ConsistOf("receipt-1234", "receipt-5678"))
+
+		input := fs.Received()[0]
+
+		Expect(*input.QueueUrl).To(Equal(url))
+		Expect(*input.MaxNumberOfMessages).To(BeEquivalentTo(maxReceive))
+		Expect(*input.VisibilityTimeout).To(BeEquivalentTo(60))
+	})
+
+	It("does not delete messages of failed batch", func() {
+		fs.Push("1234")
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		done := start(ctx)
+
+		Eventually(handled).Should(Receive())
+		cancel()
+		release <- fmt.Errorf("update error")
+
+		Eventually(done).Should(BeClosed())
+		Expect(fs.Deleted()).To(BeEmpty())
+	})
+
+	It("finishes batch in flight on shutdown", func() {
+		fs.Push("1234")
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		done := start(ctx)
+
+		Eventually(handled).Should(Receive())
+		cancel()
+		Consistently(done).ShouldNot(BeClosed())
+
+		release <- nil
+
+		Eventually(done).Should(BeClosed())
+		Expect(fs.Deleted()).To(ConsistOf("receipt-1234"))
+	})
+
+	It("stops receiving on shutdown", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		done := start(ctx)
+
+		Eventually(func() int { return len(fs.Received()) }).Should(
+			BeNumerically(">", 0))
+		cancel()
+
+		Eventually(done).Should(BeClosed())
+		Expect(handled).To(BeEmpty())
+	})
+})
+
+// fakeSQS hands out what was pushed and blocks a receive otherwise, like a
+// long poll of an empty queue.
+type fakeSQS struct {
+	sqsiface.SQSAPI
+
+	mu       sync.Mutex
+	pending  []*sqs.Message
+	received []*sqs.ReceiveMessageInput
+	deleted  []string
+}
+
+func (fs *fakeSQS) Push(bodies ...string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for _, body := range bodies {
+		fs.pending = append(fs.pending, &sqs.Message{
+			MessageId:     aws.String("message-" + body),
+			ReceiptHandle: aws.String("receipt-" + body),
+			Body:          aws.String(body),
+		})
+	}
+}
+
+func (fs *fakeSQS) Received() []*sqs.ReceiveMessageInput {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.received
+}
+
+func (fs *fakeSQS) Deleted() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.deleted
+}
+
+func (fs *fakeSQS) ReceiveMessageWithContext(
+	ctx aws.Context,
+	input *sqs.ReceiveMessageInput,
+	_ ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+
+	fs.mu.Lock()
+	fs.received = append(fs.received, input)
+	messages := fs.pending
+	fs.pending = nil
+	fs.mu.Unlock()
+
+	if len(messages) > 0 {
+		return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+	}
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (fs *fakeSQS) DeleteMessageBatchWithContext(
+	_ aws.Context,
+	input *sqs.DeleteMessageBatchInput,
+	_ ...request.Option) (*sqs.DeleteMessageBatchOutput, error) {
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for _, entry := range input.Entries {
+		fs.deleted = append(fs.deleted, *entry.ReceiptHandle)
+	}
+
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+)
+
+// defaultBlobThreshold leaves room for the body and the result next to each
+// other in the 400 KB item.
+const defaultBlobThreshold = 64 * 1024
+
+// LoadBody fills in the body of a request that was offloaded to the blob
+// store on create.
+func LoadBody(
+	ctx context.Context,
+	blobs blob.Store,
+	ri *RequestInput,
+	ui *UpdateInput) error {
+
+	if ui.BodyRef == nil {
+		return nil
+	}
+
+	if blobs == nil {
+		return fmt.Errorf("no blob store for body %s", *ui.BodyRef)
+	}
+
+	body, err := blobs.Get(ctx, *ui.BodyRef)
+
+	if err != nil {
+		return err
+	}
+
+	ri.Body = string(body)
+
+	return nil
+}
+
+func blobThreshold() int {
+	bytes, err := strconv.Atoi(os.Getenv("SCHEDULER_BLOB_THRESHOLD_BYTES"))
+
+	if err != nil || bytes < 1 {
+		return defaultBlobThreshold
+	}
+
+	return bytes
+}
+
+func resultKey(id string) string {
+	return "results/" + id
+}
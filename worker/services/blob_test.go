@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+)
+
+var _ = Describe("Blob", func() {
+	Describe("LoadBody", func() {
+		var (
+			blobs fakeBlobs
+			ri    *RequestInput
+		)
+
+		BeforeEach(func() {
+			blobs = fakeBlobs{"bodies/1234": []byte("large body")}
+			ri = &RequestInput{Body: "small body"}
+		})
+
+		It("keeps body without ref", func() {
+			Expect(LoadBody(
+				context.TODO(),
+				blobs,
+				ri,
+				&UpdateInput{ID: "1234"})).To(Succeed())
+
+			Expect(ri.Body).To(Equal("small body"))
+		})
+
+		It("loads body of ref", func() {
+			Expect(LoadBody(
+				context.TODO(),
+				blobs,
+				ri,
+				&UpdateInput{
+					ID:      "1234",
+					BodyRef: aws.String("bodies/1234"),
+				})).To(Succeed())
+
+			Expect(ri.Body).To(Equal("large body"))
+		})
+
+		It("returns error of missing body", func() {
+			Expect(LoadBody(
+				context.TODO(),
+				blobs,
+				ri,
+				&UpdateInput{
+					ID:      "5678",
+					BodyRef: aws.String("bodies/5678"),
+				})).To(MatchError(blob.ErrNotFound))
+		})
+
+		It("returns error without blob store", func() {
+			Expect(LoadBody(
+				context.TODO(),
+				nil,
+				ri,
+				&UpdateInput{
+					ID:      "1234",
+					BodyRef: aws.String("bodies/1234"),
+				})).NotTo(Succeed())
+		})
+	})
+
+	Describe("blobThreshold", func() {
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_BLOB_THRESHOLD_BYTES")
+		})
+
+		It("reads threshold from env", func() {
+			_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "1024")
+
+			Expect(blobThreshold()).To(Equal(1024))
+		})
+
+		It("defaults threshold", func() {
+			Expect(blobThreshold()).To(Equal(defaultBlobThreshold))
+		})
+	})
+})
+
+type fakeBlobs map[string][]byte
+
+func (fb fakeBlobs) Put(_ context.Context, key string, body []byte) error {
+	fb[key] = body
+
+	return nil
+}
+
+func (fb fakeBlobs) Get(_ context.Context, key string) ([]byte, error) {
+	body, ok := fb[key]
+
+	if !ok {
+		return nil, blob.ErrNotFound
+	}
+
+	return body, nil
+}
+
+func (fb fakeBlobs) Delete(_ context.Context, key string) error {
+	delete(fb, key)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type BreakerClient struct {
+	client  Client
+	breaker Breaker
+}
+
+func NewBreakerClient(client Client, breaker Breaker) *BreakerClient {
+	return &BreakerClient{client, breaker}
+}
+
+func (bc *BreakerClient) Request(
+	ctx context.Context,
+	ri *RequestInput) *ResponseOutput {
+
+	host := destinationHost(ri.URL)
+
+	if host == "" {
+		return bc.client.Request(ctx, ri)
+	}
+
+	// The breaker is advisory, when its state cannot be read the request is
+	// let through rather than holding every schedule back.
+	if openUntil, err := bc.breaker.OpenUntil(ctx, host); err == nil &&
+		openUntil > 0 {
+		return &ResponseOutput{
+			Status:        ScheduleStatusIdle,
+			Reason:        ScheduleReasonDeferred,
+			DeferredUntil: openUntil,
+		}
+	}
+
+	ro := bc.client.Request(ctx, ri)
+
+	// A request cut off by the worker itself says nothing about the
+	// destination health.
+	if ctx.Err() != nil && ro.StatusCode == 0 {
+		return ro
+	}
+
+	if ro.StatusCode == 0 || ro.StatusCode >= http.StatusInternalServerError {
+		_ = bc.breaker.Failure(ctx, host)
+	} else {
+		_ = bc.breaker.Success(ctx, host)
+	}
+
+	return ro
+}
+
+func destinationHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BreakerClient", func() {
+	const url = "https://Foo.Bar/do"
+
+	var (
+		fc fakeBreakerTargetClient
+		fb fakeBreaker
+		bc *BreakerClient
+	)
+
+	BeforeEach(func() {
+		fc = fakeBreakerTargetClient{}
+		fb = fakeBreaker{}
+		bc = NewBreakerClient(&fc, &fb)
+	})
+
+	Describe("Request", func() {
+		Context("open circuit", func() {
+			var ro *ResponseOutput
+
+			BeforeEach(func() {
+				fb.Until = 9876543
+
+				ro = bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("checks destination host", func() {
+				Expect(fb.Host).To(Equal("foo.bar"))
+			})
+
+			It("does not send request", func() {
+				Expect(fc.Called).To(BeFalse())
+			})
+
+			It("returns idle status", func() {
+				Expect(ro.Status).To(Equal(ScheduleStatusIdle))
+			})
+
+			It("returns deferred reason", func() {
+				Expect(ro.Reason).To(Equal(ScheduleReasonDeferred))
+			})
+
+			It("returns deferred until circuit closes", func() {
+				Expect(ro.DeferredUntil).To(BeEquivalentTo(9876543))
+			})
+		})
+
+		Context("closed circuit with success", func() {
+			var ro *ResponseOutput
+
+			BeforeEach(func() {
+				fc.Output = &ResponseOutput{
+					Status:     ScheduleStatusSucceeded,
+					StatusCode: http.StatusOK,
+				}
+
+				ro = bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("sends request", func() {
+				Expect(fc.Called).To(BeTrue())
+			})
+
+			It("records success", func() {
+				Expect(fb.Successes).To(Equal(1))
+				Expect(fb.Failures).To(BeZero())
+			})
+
+			It("returns response", func() {
+				Expect(ro).To(Equal(fc.Output))
+			})
+		})
+
+		Context("closed circuit with server error", func() {
+			BeforeEach(func() {
+				fc.Output = &ResponseOutput{
+					Status:     ScheduleStatusFailed,
+					StatusCode: http.StatusBadGateway,
+				}
+
+				bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("records failure", func() {
+				Expect(fb.Failures).To(Equal(1))
+			})
+		})
+
+		Context("closed circuit with client error", func() {
+			BeforeEach(func() {
+				fc.Output = &ResponseOutput{
+					Status:     ScheduleStatusFailed,
+					StatusCode: http.StatusBadRequest,
+				}
+
+				bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("does not record failure", func() {
+				Expect(fb.Failures).To(BeZero())
+				Expect(fb.Successes).To(Equal(1))
+			})
+		})
+
+		Context("closed circuit with transport error", func() {
+			BeforeEach(func() {
+				fc.Output = &ResponseOutput{Status: ScheduleStatusFailed}
+
+				bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("records failure", func() {
+				Expect(fb.Failures).To(Equal(1))
+			})
+		})
+
+		Context("request cut off by worker", func() {
+			BeforeEach(func() {
+				fc.Output = &ResponseOutput{Status: ScheduleStatusFailed}
+
+				ctx, cancel := context.WithCancel(context.TODO())
+				cancel()
+
+				bc.Request(ctx, &RequestInput{URL: url})
+			})
+
+			It("does not record anything", func() {
+				Expect(fb.Failures).To(BeZero())
+				Expect(fb.Successes).To(BeZero())
+			})
+		})
+
+		Context("breaker error", func() {
+			BeforeEach(func() {
+				fb.Until = 9876543
+				fb.Error = fmt.Errorf("breaker error")
+				fc.Output = &ResponseOutput{
+					Status:     ScheduleStatusSucceeded,
+					StatusCode: http.StatusOK,
+				}
+
+				bc.Request(context.TODO(), &RequestInput{URL: url})
+			})
+
+			It("sends request", func() {
+				Expect(fc.Called).To(BeTrue())
+			})
+		})
+	})
+})
+
+type fakeBreakerTargetClient struct {
+	Client
+
+	Called bool
+	Output *ResponseOutput
+}
+
+func (fc *fakeBreakerTargetClient) Request(
+	_ context.Context,
+	_ *RequestInput) *ResponseOutput {
+
+	fc.Called = true
+
+	return fc.Output
+}
+
+type fakeBreaker struct {
+	Breaker
+
+	Host      string
+	Until     int64
+	Error     error
+	Successes int
+	Failures  int
+}
+
+func (fb *fakeBreaker) OpenUntil(_ context.Context, host string) (int64, error) {
+	fb.Host = host
+
+	return fb.Until, fb.Error
+}
+
+func (fb *fakeBreaker) Success(_ context.Context, _ string) error {
+	fb.Successes++
+
+	return nil
+}
+
+func (fb *fakeBreaker) Failure(_ context.Context, _ string) error {
+	fb.Failures++
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitCooldown         = 60
+)
+
+type Breaker interface {
+	OpenUntil(context.Context, string) (int64, error)
+	Success(context.Context, string) error
+	Failure(context.Context, string) error
+}
+
+type CircuitBreaker struct {
+	dynamodb  dynamodbiface.DynamoDBAPI
+	clock     clock.Clock
+	threshold int64
+	cooldown  int64
+}
+
+func NewCircuitBreaker(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	clock clock.Clock,
+	threshold int64,
+	cooldown int64) *CircuitBreaker {
+
+	if threshold < 1 {
+		threshold = defaultCircuitFailureThreshold
+	}
+
+	if cooldown < 1 {
+		cooldown = defaultCircuitCooldown
+	}
+
+	return &CircuitBreaker{dynamodb, clock, threshold, cooldown}
+}
+
+func (cb *CircuitBreaker) OpenUntil(
+	ctx context.Context,
+	host string) (int64, error) {
+
+	params := &dynamodb.GetItemInput{
+		TableName: aws.String(circuitTableName()),
+		Key: map[string]*dynamodb.AttributeValue{
+			"host": {S: aws.String(host)},
+		},
+		ConsistentRead: aws.Bool(true),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+	}
+
+	res, err := cb.dynamodb.GetItemWithContext(ctx, params)
+
+	if err != nil {
+		return 0, err
+	}
+
+	attr, found := res.Item["openUntil"]
+
+	if !found || attr.N == nil {
+		return 0, nil
+	}
+
+	openUntil, err := strconv.ParseInt(*attr.N, 10, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if openUntil <= cb.clock.Now().Unix() {
+		return 0, nil
+	}
+
+	return openUntil, nil
+}
+
+func (cb *CircuitBreaker) Success(ctx context.Context, host string) error {
+	params := &dynamodb.UpdateItemInput{
+		TableName: aws.String(circuitTableName()),
+		Key: map[string]*dynamodb.AttributeValue{
+			"host": {S: aws.String(host)},
+		},
+		UpdateExpression: aws.String("SET #f = :z REMOVE #ou"),
+		ConditionExpression: aws.String(
+			"#f > :z OR attribute_exists(#ou)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f":  aws.String("failures"),
+			"#ou": aws.String("openUntil"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":z": {N: aws.String("0")},
+		},
+		ReturnItemCollectionMetrics: aws.String(
+			dynamodb.ReturnItemCollectionMetricsNone),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+		ReturnValues: aws.String(dynamodb.ReturnValueNone),
+	}
+
+	if _, err := cb.dynamodb.UpdateItemWithContext(ctx, params); err != nil {
+		if ccf, ok := err.(awserr.RequestFailure); ok &&
+			ccf.Code() == "ConditionalCheckFailedException" {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (cb *CircuitBreaker) Failure(ctx context.Context, host string) error {
+	table := circuitTableName()
+
+	params := &dynamodb.UpdateItemInput{
+		TableName: aws.String(table),
+		Key: map[string]*dynamodb.AttributeValue{
+			"host": {S: aws.String(host)},
+		},
+		UpdateExpression: aws.String("ADD #f :o"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f": aws.String("failures"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":o": {N: aws.String("1")},
+		},
+		ReturnItemCollectionMetrics: aws.String(
+			dynamodb.ReturnItemCollectionMetricsNone),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+		ReturnValues: aws.String(dynamodb.ReturnValueUpdatedNew),
+	}
+
+	res, err := cb.dynamodb.UpdateItemWithContext(ctx, params)
+
+	if err != nil {
+		return err
+	}
+
+	attr, found := res.Attributes["failures"]
+
+	if !found || attr.N == nil {
+		return nil
+	}
+
+	failures, err := strconv.ParseInt(*attr.N, 10, 64)
+
+	if err != nil {
+		return err
+	}
+
+	if failures < cb.threshold {
+		return nil
+	}
+
+	openUntil := cb.clock.Now().Unix() + cb.cooldown
+
+	params = &dynamodb.UpdateItemInput{
+		TableName: aws.String(table),
+		Key: map[string]*dynamodb.AttributeValue{
+			"host": {S: aws.String(host)},
+		},
+		UpdateExpression: aws.String("SET #f = :z, #ou = :ou"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f":  aws.String("failures"),
+			"#ou": aws.String("openUntil"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":z":  {N: aws.String("0")},
+			":ou": {N: aws.String(strconv.FormatInt(openUntil, 10))},
+		},
+		ReturnItemCollectionMetrics: aws.String(
+			dynamodb.ReturnItemCollectionMetricsNone),
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+		ReturnValues: aws.String(dynamodb.ReturnValueNone),
+	}
+
+	_, err = cb.dynamodb.UpdateItemWithContext(ctx, params)
+
+	return err
+}
+
+func circuitTableName() string {
+	return os.Getenv("SCHEDULER_CIRCUIT_TABLE_NAME")
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	const (
+		table = "scheduler_circuit_v1"
+		host  = "foo.bar"
+	)
+
+	var (
+		dynamo fakeDynamoDB
+		cb     *CircuitBreaker
+	)
+
+	now := time.Unix(1600000000, 0)
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_CIRCUIT_TABLE_NAME", table)
+
+		dynamo = fakeDynamoDB{}
+		cb = NewCircuitBreaker(&dynamo, clock.NewFake(now), 3, 30)
+	})
+
+	AfterEach(func() {
+		dynamo.ClearState()
+	})
+
+	Describe("NewCircuitBreaker", func() {
+		It("uses defaults when not configured", func() {
+			dcb := NewCircuitBreaker(&dynamo, clock.System, 0, 0)
+
+			Expect(dcb.threshold).To(
+				BeEquivalentTo(defaultCircuitFailureThreshold))
+			Expect(dcb.cooldown).To(BeEquivalentTo(defaultCircuitCooldown))
+		})
+	})
+
+	Describe("OpenUntil", func() {
+		Context("open circuit", func() {
+			var (
+				openUntil int64
+				ret       int64
+				err       error
+			)
+
+			BeforeEach(func() {
+				openUntil = now.Add(time.Minute).Unix()
+
+				dynamo.GetOutput = &dynamodb.GetItemOutput{
+					Item: map[string]*dynamodb.AttributeValue{
+						"host": {S: aws.String(host)},
+						"openUntil": {
+							N: aws.String(strconv.FormatInt(openUntil, 10)),
+						},
+					},
+				}
+
+				ret, err = cb.OpenUntil(context.TODO(), host)
+			})
+
+			It("reads table name from env", func() {
+				Expect(*dynamo.GetInput.TableName).To(Equal(table))
+			})
+
+			It("sets given host as key", func() {
+				Expect(*dynamo.GetInput.Key["host"].S).To(Equal(host))
+			})
+
+			It("returns open until", func() {
+				Expect(ret).To(Equal(openUntil))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("elapsed circuit", func() {
+			var ret int64
+
+			BeforeEach(func() {
+				openUntil := now.Add(-time.Minute).Unix()
+
+				dynamo.GetOutput = &dynamodb.GetItemOutput{
+					Item: map[string]*dynamodb.AttributeValue{
+						"openUntil": {
+							N: aws.String(strconv.FormatInt(openUntil, 10)),
+						},
+					},
+				}
+
+				ret, _ = cb.OpenUntil(context.TODO(), host)
+			})
+
+			It("returns closed", func() {
+				Expect(ret).To(BeZero())
+			})
+		})
+
+		Context("unknown host", func() {
+			var ret int64
+
+			BeforeEach(func() {
+				dynamo.GetOutput = &dynamodb.GetItemOutput{}
+
+				ret, _ = cb.OpenUntil(context.TODO(), host)
+			})
+
+			It("returns closed", func() {
+				Expect(ret).To(BeZero())
+			})
+		})
+
+		Context("get error", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.GetError = fmt.Errorf("get error")
+
+				_, err = cb.OpenUntil(context.TODO(), host)
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Success", func() {
+		Context("tripped host", func() {
+			var (
+				input *dynamodb.UpdateItemInput
+				err   error
+			)
+
+			BeforeEach(func() {
+				err = cb.Success(context.TODO(), host)
+				input = dynamo.PullUpdateInput()
+			})
+
+			It("resets failures", func() {
+				Expect(*input.UpdateExpression).To(
+					Equal("SET #f = :z REMOVE #ou"))
+			})
+
+			It("only writes when host has failures", func() {
+				Expect(*input.ConditionExpression).To(
+					Equal("#f > :z OR attribute_exists(#ou)"))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("healthy host", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.UpdateError = awserr.NewRequestFailure(
+					awserr.New(
+						"ConditionalCheckFailedException",
+						"condition failed",
+						nil),
+					400,
+					"1234")
+
+				err = cb.Success(context.TODO(), host)
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("update error", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.UpdateError = fmt.Errorf("update error")
+
+				err = cb.Success(context.TODO(), host)
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Failure", func() {
+		Context("below threshold", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.PushUpdateOutput(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]*dynamodb.AttributeValue{
+						"failures": {N: aws.String("2")},
+					},
+				})
+
+				err = cb.Failure(context.TODO(), host)
+			})
+
+			It("increments failures", func() {
+				input := dynamo.PullUpdateInput()
+
+				Expect(*input.UpdateExpression).To(Equal("ADD #f :o"))
+			})
+
+			It("does not open circuit", func() {
+				dynamo.PullUpdateInput()
+
+				Expect(dynamo.PullUpdateInput()).To(BeNil())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("reaching threshold", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.PushUpdateOutput(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]*dynamodb.AttributeValue{
+						"failures": {N: aws.String("3")},
+					},
+				})
+
+				err = cb.Failure(context.TODO(), host)
+			})
+
+			It("opens circuit for cooldown", func() {
+				dynamo.PullUpdateInput()
+				input := dynamo.PullUpdateInput()
+
+				Expect(*input.UpdateExpression).To(
+					Equal("SET #f = :z, #ou = :ou"))
+
+				openUntil, _ := strconv.ParseInt(
+					*input.ExpressionAttributeValues[":ou"].N, 10, 64)
+
+				Expect(openUntil).To(Equal(now.Unix() + 30))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("update error", func() {
+			var err error
+
+			BeforeEach(func() {
+				dynamo.UpdateError = fmt.Errorf("update error")
+
+				err = cb.Failure(context.TODO(), host)
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+})
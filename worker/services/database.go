@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"os"
+	"sort"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -10,6 +12,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/blob"
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/sharding"
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
 )
 
 type marshalStorage func(in interface{}) (
@@ -22,23 +29,146 @@ func dynamoDBMarshal(
 
 var marshalStorageStruct marshalStorage = dynamoDBMarshal
 
+const (
+	loadBatchSize  = 100
+	maxBatchWrites = 25
+
+	// maxTransactSchedules keeps a transaction of items up to 400 KB each
+	// within its 4 MB, with room for the counts.
+	maxTransactSchedules = 9
+)
+
 type Storage interface {
+	Load(context.Context, []string) ([]*UpdateInput, error)
+
 	Update(context.Context, []*UpdateInput) error
 }
 
 type Database struct {
 	dynamodb dynamodbiface.DynamoDBAPI
+	clock    clock.Clock
+	blobs    blob.Store
 }
 
-func NewDatabase(dynamodb dynamodbiface.DynamoDBAPI) *Database {
-	return &Database{dynamodb}
+// NewDatabase offloads results larger than the blob threshold to the blob
+// store when there is one, they are kept in the item otherwise.
+func NewDatabase(
+	dynamodb dynamodbiface.DynamoDBAPI,
+	clock clock.Clock,
+	blobs blob.Store) *Database {
+
+	return &Database{dynamodb, clock, blobs}
+}
+
+// Load reads the queued schedules of the given ids, the ones that are no
+// longer queued are left out.
+func (srv *Database) Load(
+	ctx context.Context,
+	ids []string) ([]*UpdateInput, error) {
+
+	table := tableName()
+	seen := make(map[string]bool)
+	var keys []map[string]*dynamodb.AttributeValue
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(id)},
+		})
+	}
+
+	var inputs []*UpdateInput
+
+	for start := 0; start < len(keys); start += loadBatchSize {
+		end := start + loadBatchSize
+
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		items, err := srv.load(ctx, table, keys[start:end])
+
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []*UpdateInput
+
+		if err = dynamodbattribute.UnmarshalListOfMaps(
+			items,
+			&batch); err != nil {
+			return nil, err
+		}
+
+		for _, input := range batch {
+			if input.Status == ScheduleStatusQueued {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+
+	sort.SliceStable(inputs, func(i, j int) bool {
+		return inputs[i].DueAt < inputs[j].DueAt
+	})
+
+	return inputs, nil
+}
+
+func (srv *Database) load(
+	ctx context.Context,
+	table string,
+	keys []map[string]*dynamodb.AttributeValue) (
+	[]map[string]*dynamodb.AttributeValue, error) {
+
+	params := &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			table: {
+				Keys:           keys,
+				ConsistentRead: aws.Bool(true),
+			},
+		},
+		ReturnConsumedCapacity: aws.String(
+			dynamodb.ReturnConsumedCapacityNone),
+	}
+
+	res, err := srv.dynamodb.BatchGetItemWithContext(ctx, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := res.Responses[table]
+
+	if uk, ok := res.UnprocessedKeys[table]; ok && len(uk.Keys) > 0 {
+		rest, err := srv.load(ctx, table, uk.Keys)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, rest...)
+	}
+
+	return items, nil
 }
 
 func (srv *Database) Update(ctx context.Context, inputs []*UpdateInput) error {
 	table := tableName()
+	shards := sharding.Count()
 	g, _ := errgroup.WithContext(ctx)
 
-	for _, chunk := range chunkBy(inputs, 25) {
+	statsTable := statsTableName()
+	size := maxBatchWrites
+
+	if statsTable != "" {
+		size = maxTransactSchedules
+	}
+
+	for _, chunk := range chunkBy(inputs, size) {
 		localInputs := chunk
 
 		g.Go(func() error {
@@ -51,7 +181,28 @@ func (srv *Database) Update(ctx context.Context, inputs []*UpdateInput) error {
 					return err
 				}
 
-				item["dummy"] = &dynamodb.AttributeValue{S: aws.String("-")}
+				if err = srv.offload(ctx, input, item); err != nil {
+					return err
+				}
+
+				shard := sharding.Of(input.ID, shards)
+
+				item["dummy"] = &dynamodb.AttributeValue{
+					S: aws.String(sharding.Key("-", shard, shards)),
+				}
+
+				if shards > 1 {
+					item["statusShard"] = &dynamodb.AttributeValue{
+						S: aws.String(
+							sharding.Key(input.Status, shard, shards)),
+					}
+				}
+
+				if ttl := expiresAt(input); ttl > 0 {
+					item["ttl"] = &dynamodb.AttributeValue{
+						N: aws.String(strconv.FormatInt(ttl, 10)),
+					}
+				}
 
 				write := &dynamodb.WriteRequest{
 					PutRequest: &dynamodb.PutRequest{
@@ -62,6 +213,10 @@ func (srv *Database) Update(ctx context.Context, inputs []*UpdateInput) error {
 				writes[index] = write
 			}
 
+			if statsTable != "" {
+				return srv.transact(ctx, table, statsTable, writes, localInputs)
+			}
+
 			return srv.update(ctx, table, writes)
 		})
 	}
@@ -69,6 +224,55 @@ func (srv *Database) Update(ctx context.Context, inputs []*UpdateInput) error {
 	return g.Wait()
 }
 
+// transact writes the items in a transaction with the counts of the
+// completed inputs, so neither is written without the other.
+func (srv *Database) transact(
+	ctx context.Context,
+	table string,
+	statsTable string,
+	writes []*dynamodb.WriteRequest,
+	inputs []*UpdateInput) error {
+
+	puts := make([]*dynamodb.TransactWriteItem, len(writes))
+
+	for index, write := range writes {
+		puts[index] = &dynamodb.TransactWriteItem{
+			Put: &dynamodb.Put{
+				TableName: aws.String(table),
+				Item:      write.PutRequest.Item,
+			},
+		}
+	}
+
+	return stats.NewDynamo(srv.dynamodb, statsTable, srv.clock).Transact(
+		ctx,
+		puts,
+		completions(inputs))
+}
+
+func (srv *Database) offload(
+	ctx context.Context,
+	input *UpdateInput,
+	item map[string]*dynamodb.AttributeValue) error {
+
+	if srv.blobs == nil ||
+		input.Result == nil ||
+		len(*input.Result) <= blobThreshold() {
+		return nil
+	}
+
+	key := resultKey(input.ID)
+
+	if err := srv.blobs.Put(ctx, key, []byte(*input.Result)); err != nil {
+		return err
+	}
+
+	delete(item, "result")
+	item["resultRef"] = &dynamodb.AttributeValue{S: aws.String(key)}
+
+	return nil
+}
+
 func (srv *Database) update(
 	ctx context.Context,
 	table string,
@@ -11,6 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,12 +28,114 @@ var _ = Describe("Database", func() {
 		db     *Database
 	)
 
+	Describe("Load", func() {
+		item := func(
+			id string,
+			status string,
+			dueAt string) map[string]*dynamodb.AttributeValue {
+
+			return map[string]*dynamodb.AttributeValue{
+				"id":     {S: aws.String(id)},
+				"status": {S: aws.String(status)},
+				"dueAt":  {N: aws.String(dueAt)},
+				"url":    {S: aws.String("https://foo.bar/do")},
+				"method": {S: aws.String("POST")},
+			}
+		}
+
+		BeforeEach(func() {
+			_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+
+			dynamo = fakeDynamoDB{}
+			db = NewDatabase(&dynamo, clock.System, nil)
+		})
+
+		Describe("success", func() {
+			var (
+				inputs []*UpdateInput
+				err    error
+			)
+
+			BeforeEach(func() {
+				dynamo.PushBatchGetOutput(&dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{
+						table: {
+							item("3", ScheduleStatusQueued, "30"),
+							item("2", ScheduleStatusSucceeded, "20"),
+						},
+					},
+					UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{
+						table: {
+							Keys: []map[string]*dynamodb.AttributeValue{
+								{"id": {S: aws.String("1")}},
+							},
+						},
+					},
+				})
+				dynamo.PushBatchGetOutput(&dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{
+						table: {item("1", ScheduleStatusQueued, "10")},
+					},
+				})
+
+				inputs, err = db.Load(
+					context.TODO(),
+					[]string{"1", "2", "3", "3"})
+			})
+
+			It("reads distinct keys consistently", func() {
+				input := dynamo.PullBatchGetInput()
+
+				Expect(input.RequestItems[table].Keys).To(HaveLen(3))
+				Expect(*input.RequestItems[table].ConsistentRead).To(BeTrue())
+			})
+
+			It("retries unprocessed keys", func() {
+				_ = dynamo.PullBatchGetInput()
+				input := dynamo.PullBatchGetInput()
+
+				Expect(input.RequestItems[table].Keys).To(HaveLen(1))
+			})
+
+			It("returns queued schedules by due at", func() {
+				Expect(inputs).To(HaveLen(2))
+				Expect(inputs[0].ID).To(Equal("1"))
+				Expect(inputs[1].ID).To(Equal("3"))
+				Expect(inputs[1].URL).To(Equal("https://foo.bar/do"))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("no ids", func() {
+			It("does not read", func() {
+				inputs, err := db.Load(context.TODO(), nil)
+
+				Expect(err).To(BeNil())
+				Expect(inputs).To(BeEmpty())
+				Expect(dynamo.PullBatchGetInput()).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			It("returns error", func() {
+				dynamo.BatchGetError = fmt.Errorf("batch get error")
+
+				_, err := db.Load(context.TODO(), []string{"1"})
+
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
 	Describe("Update", func() {
 		BeforeEach(func() {
 			_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
 
 			dynamo = fakeDynamoDB{}
-			db = NewDatabase(&dynamo)
+			db = NewDatabase(&dynamo, clock.System, nil)
 		})
 
 		Describe("success", func() {
@@ -91,6 +195,135 @@ var _ = Describe("Database", func() {
 			})
 		})
 
+		Describe("sharded", func() {
+			var batchWriteInput *dynamodb.BatchWriteItemInput
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_SHARD_COUNT", "16")
+
+				dynamo.PushBatchWriteOutput(&dynamodb.BatchWriteItemOutput{})
+
+				_ = db.Update(context.TODO(), []*UpdateInput{
+					{
+						ID:     id,
+						Status: ScheduleStatusSucceeded,
+					},
+				})
+
+				batchWriteInput = dynamo.PullBatchWriteInput()
+			})
+
+			It("sets sharded dummy", func() {
+				item := batchWriteInput.RequestItems[table][0].PutRequest.Item
+
+				Expect(*item["dummy"].S).To(Equal("-#04"))
+			})
+
+			It("sets sharded status", func() {
+				item := batchWriteInput.RequestItems[table][0].PutRequest.Item
+
+				Expect(*item["statusShard"].S).To(Equal("SUCCEEDED#04"))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_SHARD_COUNT")
+				dynamo.ClearState()
+			})
+		})
+
+		Describe("with retention", func() {
+			var batchWriteInput *dynamodb.BatchWriteItemInput
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_RETENTION_SUCCEEDED_SECONDS", "3600")
+
+				dynamo.PushBatchWriteOutput(&dynamodb.BatchWriteItemOutput{})
+
+				_ = db.Update(context.TODO(), []*UpdateInput{
+					{
+						ID:          id,
+						Status:      ScheduleStatusSucceeded,
+						CompletedAt: aws.Int64(1600000000),
+					},
+					{
+						ID:          "5678",
+						Status:      ScheduleStatusFailed,
+						CompletedAt: aws.Int64(1600000000),
+					},
+				})
+
+				batchWriteInput = dynamo.PullBatchWriteInput()
+			})
+
+			It("sets ttl of status with retention", func() {
+				item := batchWriteInput.RequestItems[table][0].PutRequest.Item
+
+				Expect(*item["ttl"].N).To(Equal("1600003600"))
+			})
+
+			It("does not set ttl of status without retention", func() {
+				item := batchWriteInput.RequestItems[table][1].PutRequest.Item
+
+				Expect(item).NotTo(HaveKey("ttl"))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_RETENTION_SUCCEEDED_SECONDS")
+				dynamo.ClearState()
+			})
+		})
+
+		Describe("with blobs", func() {
+			var (
+				blobs           fakeBlobs
+				batchWriteInput *dynamodb.BatchWriteItemInput
+			)
+
+			BeforeEach(func() {
+				_ = os.Setenv("SCHEDULER_BLOB_THRESHOLD_BYTES", "8")
+
+				blobs = fakeBlobs{}
+				dynamo.PushBatchWriteOutput(&dynamodb.BatchWriteItemOutput{})
+
+				_ = NewDatabase(&dynamo, clock.System, blobs).Update(
+					context.TODO(),
+					[]*UpdateInput{
+						{
+							ID:     id,
+							Status: ScheduleStatusSucceeded,
+							Result: aws.String("large result"),
+						},
+						{
+							ID:     "5678",
+							Status: ScheduleStatusSucceeded,
+							Result: aws.String("small"),
+						},
+					})
+
+				batchWriteInput = dynamo.PullBatchWriteInput()
+			})
+
+			It("offloads large result", func() {
+				item := batchWriteInput.RequestItems[table][0].PutRequest.Item
+
+				Expect(item).NotTo(HaveKey("result"))
+				Expect(*item["resultRef"].S).To(Equal("results/" + id))
+				Expect(string(blobs["results/"+id])).To(Equal("large result"))
+			})
+
+			It("keeps small result", func() {
+				item := batchWriteInput.RequestItems[table][1].PutRequest.Item
+
+				Expect(*item["result"].S).To(Equal("small"))
+				Expect(item).NotTo(HaveKey("resultRef"))
+			})
+
+			AfterEach(func() {
+				_ = os.Unsetenv("SCHEDULER_BLOB_THRESHOLD_BYTES")
+				dynamo.ClearState()
+			})
+		})
+
 		Describe("fail", func() {
 			Context("marshal error", func() {
 				var (
@@ -215,10 +448,101 @@ var _ = Describe("Database", func() {
 type fakeDynamoDB struct {
 	dynamodbiface.DynamoDBAPI
 
+	BatchGetError   error
 	BatchWriteError error
+	GetError        error
+	UpdateError     error
+
+	GetInput  *dynamodb.GetItemInput
+	GetOutput *dynamodb.GetItemOutput
+
+	batchGetInputs  list.List
+	batchGetOutputs list.List
 
 	batchWriteInputs  list.List
 	batchWriteOutputs list.List
+
+	updateInputs  list.List
+	updateOutputs list.List
+}
+
+func (db *fakeDynamoDB) GetItemWithContext(
+	_ aws.Context,
+	input *dynamodb.GetItemInput,
+	_ ...request.Option) (*dynamodb.GetItemOutput, error) {
+
+	db.GetInput = input
+
+	return db.GetOutput, db.GetError
+}
+
+func (db *fakeDynamoDB) UpdateItemWithContext(
+	_ aws.Context,
+	input *dynamodb.UpdateItemInput,
+	_ ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+
+	db.updateInputs.PushBack(input)
+
+	output := db.updateOutputs.Front()
+
+	if output == nil {
+		return &dynamodb.UpdateItemOutput{}, db.UpdateError
+	}
+
+	db.updateOutputs.Remove(output)
+
+	return output.Value.(*dynamodb.UpdateItemOutput), db.UpdateError
+}
+
+func (db *fakeDynamoDB) PushUpdateOutput(output *dynamodb.UpdateItemOutput) {
+	db.updateOutputs.PushBack(output)
+}
+
+func (db *fakeDynamoDB) PullUpdateInput() *dynamodb.UpdateItemInput {
+	input := db.updateInputs.Front()
+
+	if input == nil {
+		return nil
+	}
+
+	db.updateInputs.Remove(input)
+
+	return input.Value.(*dynamodb.UpdateItemInput)
+}
+
+func (db *fakeDynamoDB) BatchGetItemWithContext(
+	_ aws.Context,
+	input *dynamodb.BatchGetItemInput,
+	_ ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+
+	db.batchGetInputs.PushBack(input)
+
+	output := db.batchGetOutputs.Front()
+
+	if output == nil {
+		return nil, db.BatchGetError
+	}
+
+	db.batchGetOutputs.Remove(output)
+
+	return output.Value.(*dynamodb.BatchGetItemOutput), db.BatchGetError
+}
+
+func (db *fakeDynamoDB) PushBatchGetOutput(
+	output *dynamodb.BatchGetItemOutput) {
+	db.batchGetOutputs.PushBack(output)
+}
+
+func (db *fakeDynamoDB) PullBatchGetInput() *dynamodb.BatchGetItemInput {
+	input := db.batchGetInputs.Front()
+
+	if input == nil {
+		return nil
+	}
+
+	db.batchGetInputs.Remove(input)
+
+	return input.Value.(*dynamodb.BatchGetItemInput)
 }
 
 func (db *fakeDynamoDB) BatchWriteItemWithContext(
@@ -258,7 +582,16 @@ func (db *fakeDynamoDB) PullBatchWriteInput() *dynamodb.BatchWriteItemInput {
 }
 
 func (db *fakeDynamoDB) ClearState() {
+	db.BatchGetError = nil
 	db.BatchWriteError = nil
+	db.GetError = nil
+	db.UpdateError = nil
+	db.GetInput = nil
+	db.GetOutput = nil
+	db.batchGetInputs.Init()
+	db.batchGetOutputs.Init()
 	db.batchWriteInputs.Init()
 	db.batchWriteOutputs.Init()
+	db.updateInputs.Init()
+	db.updateOutputs.Init()
 }
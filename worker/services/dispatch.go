@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+// Dispatch holds a queued schedule until it is due, sends its request and
+// returns the input to persist the outcome with.
+func Dispatch(
+	ctx context.Context,
+	clock clock.Clock,
+	client Client,
+	ri *RequestInput,
+	ui *UpdateInput) *UpdateInput {
+
+	HoldUntil(ctx, clock, ui.DueAt)
+
+	if ctx.Err() != nil {
+		return interrupt(ui)
+	}
+
+	startedAt := clock.Now()
+	lag := startedAt.Sub(time.Unix(ui.DueAt, 0)).Milliseconds()
+
+	if ui.Deadline != nil && startedAt.Unix() > *ui.Deadline {
+		ui.Status = ScheduleStatusExpired
+		ui.CompletedAt = aws.Int64(startedAt.Unix())
+		ui.Lag = aws.Int64(lag)
+
+		return ui
+	}
+
+	ui.StartedAt = aws.Int64(startedAt.Unix())
+
+	ro := client.Request(ctx, ri)
+
+	if ro.Reason == ScheduleReasonDeferred {
+		ui.Status = ro.Status
+		ui.DueAt = ro.DeferredUntil
+		ui.StartedAt = nil
+		ui.Reason = aws.String(ro.Reason)
+
+		return ui
+	}
+
+	if ro.StatusCode == 0 && ctx.Err() != nil {
+		return interrupt(ui)
+	}
+
+	ui.Status = ro.Status
+	ui.Result = aws.String(ro.Result)
+	ui.CompletedAt = aws.Int64(clock.Now().Unix())
+	ui.Lag = aws.Int64(lag)
+
+	return ui
+}
+
+// interrupt puts a schedule that could not complete before the worker ran
+// out of time back to idle, so the collector picks it up again.
+func interrupt(ui *UpdateInput) *UpdateInput {
+	ui.Status = ScheduleStatusIdle
+	ui.StartedAt = nil
+	ui.Reason = aws.String(ScheduleReasonInterrupted)
+
+	return ui
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dispatch", func() {
+	var fc fakeBreakerTargetClient
+
+	BeforeEach(func() {
+		fc = fakeBreakerTargetClient{
+			Output: &ResponseOutput{
+				Status:     ScheduleStatusSucceeded,
+				Result:     "dummy result",
+				StatusCode: http.StatusOK,
+			},
+		}
+	})
+
+	Context("due schedule", func() {
+		var ui *UpdateInput
+
+		BeforeEach(func() {
+			ui = Dispatch(
+				context.TODO(),
+				clock.System,
+				&fc,
+				&RequestInput{URL: "https://foo.bar/do"},
+				&UpdateInput{ID: "1234", DueAt: 9876543})
+		})
+
+		It("sends request", func() {
+			Expect(fc.Called).To(BeTrue())
+		})
+
+		It("sets status and result", func() {
+			Expect(ui.Status).To(Equal(ScheduleStatusSucceeded))
+			Expect(*ui.Result).To(Equal("dummy result"))
+		})
+
+		It("sets startedAt, completedAt and lag", func() {
+			Expect(ui.StartedAt).NotTo(BeNil())
+			Expect(ui.CompletedAt).NotTo(BeNil())
+			Expect(*ui.Lag).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("future schedule", func() {
+		var (
+			fake *clock.Fake
+			done chan *UpdateInput
+		)
+
+		BeforeEach(func() {
+			fake = clock.NewFake(time.Unix(9876483, 0))
+			done = make(chan *UpdateInput, 1)
+
+			go func() {
+				done <- Dispatch(
+					context.TODO(),
+					fake,
+					&fc,
+					&RequestInput{URL: "https://foo.bar/do"},
+					&UpdateInput{ID: "1234", DueAt: 9876543})
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+		})
+
+		It("holds until clock reaches due at", func() {
+			Consistently(done, "50ms").ShouldNot(Receive())
+
+			fake.Advance(time.Minute)
+
+			var ui *UpdateInput
+
+			Eventually(done).Should(Receive(&ui))
+			Expect(*ui.StartedAt).To(BeEquivalentTo(9876543))
+			Expect(*ui.CompletedAt).To(BeEquivalentTo(9876543))
+			Expect(*ui.Lag).To(BeZero())
+		})
+	})
+
+	Context("canceled context", func() {
+		var ui *UpdateInput
+
+		BeforeEach(func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+
+			ui = Dispatch(
+				ctx,
+				clock.System,
+				&fc,
+				&RequestInput{URL: "https://foo.bar/do"},
+				&UpdateInput{ID: "1234", DueAt: 9876543})
+		})
+
+		It("does not send request", func() {
+			Expect(fc.Called).To(BeFalse())
+		})
+
+		It("puts schedule back to idle", func() {
+			Expect(ui.Status).To(Equal(ScheduleStatusIdle))
+			Expect(*ui.Reason).To(Equal(ScheduleReasonInterrupted))
+		})
+	})
+})
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+)
+
+// HoldUntil blocks till the given unix second is reached on the clock or the
+// context is done, whichever happens first.
+func HoldUntil(ctx context.Context, clock clock.Clock, at int64) {
+	clock.Wait(ctx, time.Unix(at, 0).Sub(clock.Now()))
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HoldUntil", func() {
+	Context("past time", func() {
+		It("returns immediately", func() {
+			start := time.Now()
+
+			HoldUntil(
+				context.TODO(),
+				clock.System,
+				start.Add(-time.Minute).Unix())
+
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+
+	Context("future time", func() {
+		It("returns at given second", func() {
+			at := time.Now().Add(time.Second).Unix()
+
+			HoldUntil(context.TODO(), clock.System, at)
+
+			Expect(time.Now().Unix()).To(BeNumerically(">=", at))
+		})
+	})
+
+	Context("fake clock", func() {
+		It("returns once clock reaches given second", func() {
+			fake := clock.NewFake(time.Unix(1600000000, 0))
+			done := make(chan struct{})
+
+			go func() {
+				HoldUntil(context.TODO(), fake, 1600000060)
+				close(done)
+			}()
+
+			Eventually(fake.Waiters).Should(Equal(1))
+			Consistently(done, "50ms").ShouldNot(BeClosed())
+
+			fake.Advance(time.Minute)
+
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	Context("done context", func() {
+		It("returns without waiting", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+
+			start := time.Now()
+
+			HoldUntil(ctx, clock.System, start.Add(time.Minute).Unix())
+
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+})
@@ -99,5 +99,6 @@ func (hc *HttpClient) Request(
 			Headers:    headers,
 			Body:       string(body),
 		}),
+		StatusCode: res.StatusCode,
 	}
 }
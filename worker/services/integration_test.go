@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/clock"
+	"github.com/kazimanzurrashid/aws-scheduler-go/dynamotest"
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreaker against dynamotest", func() {
+	const (
+		table = "scheduler_circuit_v1"
+		host  = "foo.bar"
+	)
+
+	var (
+		fake *clock.Fake
+		cb   *CircuitBreaker
+	)
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_CIRCUIT_TABLE_NAME", table)
+
+		fake = clock.NewFake(time.Unix(1600000000, 0))
+		cb = NewCircuitBreaker(
+			dynamotest.New(dynamotest.CircuitTable(table)),
+			fake,
+			3,
+			30)
+	})
+
+	It("keeps unknown host closed", func() {
+		Expect(cb.Success(context.TODO(), host)).To(Succeed())
+
+		Expect(cb.OpenUntil(context.TODO(), host)).To(BeZero())
+	})
+
+	It("opens after threshold failures", func() {
+		for i := 0; i < 3; i++ {
+			Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+		}
+
+		Expect(cb.OpenUntil(context.TODO(), host)).To(
+			BeEquivalentTo(1600000030))
+	})
+
+	It("closes once cooldown passes", func() {
+		for i := 0; i < 3; i++ {
+			Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+		}
+
+		fake.Advance(29 * time.Second)
+		Expect(cb.OpenUntil(context.TODO(), host)).NotTo(BeZero())
+
+		fake.Advance(time.Second)
+		Expect(cb.OpenUntil(context.TODO(), host)).To(BeZero())
+	})
+
+	It("resets failures on success", func() {
+		Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+		Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+		Expect(cb.Success(context.TODO(), host)).To(Succeed())
+		Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+
+		Expect(cb.OpenUntil(context.TODO(), host)).To(BeZero())
+	})
+
+	It("closes on success", func() {
+		for i := 0; i < 3; i++ {
+			Expect(cb.Failure(context.TODO(), host)).To(Succeed())
+		}
+
+		Expect(cb.Success(context.TODO(), host)).To(Succeed())
+
+		Expect(cb.OpenUntil(context.TODO(), host)).To(BeZero())
+	})
+})
+
+var _ = Describe("Database against dynamotest", func() {
+	const (
+		table      = "scheduler_v1"
+		statsTable = "scheduler_stats_v1"
+	)
+
+	BeforeEach(func() {
+		_ = os.Setenv("SCHEDULER_TABLE_NAME", table)
+		_ = os.Setenv("SCHEDULER_STATS_TABLE_NAME", statsTable)
+	})
+
+	AfterEach(func() {
+		_ = os.Unsetenv("SCHEDULER_STATS_TABLE_NAME")
+	})
+
+	It("counts completed schedules", func() {
+		dynamo := dynamotest.New(
+			dynamotest.SchedulerTable(table),
+			dynamotest.StatsTable(statsTable))
+
+		Expect(NewDatabase(dynamo, clock.System, nil).Update(
+			context.TODO(),
+			[]*UpdateInput{
+				{
+					ID:          "1",
+					URL:         "https://foo.bar/",
+					Status:      ScheduleStatusSucceeded,
+					CompletedAt: aws.Int64(1600000000),
+				},
+				{
+					ID:     "2",
+					URL:    "https://foo.bar/",
+					Status: ScheduleStatusIdle,
+				},
+			})).To(Succeed())
+
+		at := time.Unix(1600000000, 0)
+		counts, err := stats.NewDynamo(
+			dynamo,
+			statsTable,
+			clock.System).Read(context.TODO(), at, at)
+
+		Expect(err).To(BeNil())
+		Expect(counts).To(Equal([]stats.Count{{
+			Bucket: at.Truncate(stats.BucketSize),
+			Status: ScheduleStatusSucceeded,
+			Host:   "foo.bar",
+			Count:  1,
+		}}))
+	})
+})
@@ -2,6 +2,11 @@ package services
 
 import "github.com/aws/aws-lambda-go/events"
 
+var defaultHeaders = map[string]string{
+	"accept":       "application/json",
+	"content-type": "application/json;charset=utf-8",
+}
+
 type RequestInput struct {
 	URL     string
 	Method  string
@@ -23,10 +28,7 @@ func CreateRequestInput(
 			input.Headers[k] = v.String()
 		}
 	} else {
-		for k, v := range map[string]string{
-			"accept":       "application/json",
-			"content-type": "application/json;charset=utf-8",
-		} {
+		for k, v := range defaultHeaders {
 			input.Headers[k] = v
 		}
 	}
@@ -37,3 +39,29 @@ func CreateRequestInput(
 
 	return &input
 }
+
+// NewRequestInput creates the request of a schedule that was loaded from
+// storage rather than received on the stream.
+func NewRequestInput(ui *UpdateInput) *RequestInput {
+	input := RequestInput{
+		URL:     ui.URL,
+		Method:  ui.Method,
+		Headers: make(map[string]string),
+	}
+
+	headers := ui.Headers
+
+	if len(headers) == 0 {
+		headers = defaultHeaders
+	}
+
+	for k, v := range headers {
+		input.Headers[k] = v
+	}
+
+	if ui.Body != nil {
+		input.Body = *ui.Body
+	}
+
+	return &input
+}
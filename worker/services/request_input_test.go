@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -74,3 +75,59 @@ var _ = Describe("CreateRequestInput", func() {
 		})
 	})
 })
+
+var _ = Describe("NewRequestInput", func() {
+	Context("with header", func() {
+		var ri *RequestInput
+
+		BeforeEach(func() {
+			ri = NewRequestInput(&UpdateInput{
+				URL:    "https://foo.bar/do",
+				Method: "POST",
+				Headers: map[string]string{
+					"authorization": "token 123",
+				},
+				Body: aws.String("{ \"foo\": \"bar\" }"),
+			})
+		})
+
+		It("sets url", func() {
+			Expect(ri.URL).To(Equal("https://foo.bar/do"))
+		})
+
+		It("sets method", func() {
+			Expect(ri.Method).To(Equal("POST"))
+		})
+
+		It("sets header", func() {
+			Expect(ri.Headers).To(Equal(map[string]string{
+				"authorization": "token 123",
+			}))
+		})
+
+		It("sets body", func() {
+			Expect(ri.Body).To(Equal("{ \"foo\": \"bar\" }"))
+		})
+	})
+
+	Context("without header", func() {
+		var ri *RequestInput
+
+		BeforeEach(func() {
+			ri = NewRequestInput(&UpdateInput{
+				URL:    "https://foo.bar/do",
+				Method: "GET",
+			})
+		})
+
+		It("sets default headers", func() {
+			Expect(ri.Headers["accept"]).To(Equal("application/json"))
+			Expect(ri.Headers["content-type"]).To(
+				Equal("application/json;charset=utf-8"))
+		})
+
+		It("leaves body empty", func() {
+			Expect(ri.Body).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// WithReserve derives a context that is done the given duration before the
+// parent deadline, leaving the parent usable to persist results afterwards.
+func WithReserve(
+	ctx context.Context,
+	reserve time.Duration) (context.Context, context.CancelFunc) {
+
+	deadline, ok := ctx.Deadline()
+
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline.Add(-reserve))
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithReserve", func() {
+	Context("parent with deadline", func() {
+		var (
+			deadline     time.Time
+			ctx          context.Context
+			cancel       context.CancelFunc
+			parentCancel context.CancelFunc
+		)
+
+		BeforeEach(func() {
+			deadline = time.Now().Add(time.Minute)
+
+			var parent context.Context
+
+			parent, parentCancel = context.WithDeadline(
+				context.TODO(),
+				deadline)
+
+			ctx, cancel = WithReserve(parent, time.Second*10)
+		})
+
+		It("ends before parent deadline", func() {
+			d, ok := ctx.Deadline()
+
+			Expect(ok).To(BeTrue())
+			Expect(d).To(BeTemporally("==", deadline.Add(-time.Second*10)))
+		})
+
+		AfterEach(func() {
+			cancel()
+			parentCancel()
+		})
+	})
+
+	Context("parent without deadline", func() {
+		It("does not set deadline", func() {
+			ctx, cancel := WithReserve(context.TODO(), time.Second*10)
+			defer cancel()
+
+			_, ok := ctx.Deadline()
+
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
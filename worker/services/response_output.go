@@ -1,6 +1,9 @@
 package services
 
 type ResponseOutput struct {
-	Status string
-	Result string
+	Status        string
+	Result        string
+	Reason        string
+	StatusCode    int
+	DeferredUntil int64
 }
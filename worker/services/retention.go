@@ -0,0 +1,30 @@
+package services
+
+import (
+	"os"
+	"strconv"
+)
+
+// expiresAt is when the table deletes a schedule that reached a terminal
+// status, per the retention of SCHEDULER_RETENTION_<STATUS>_SECONDS. It is
+// zero while the schedule is kept forever.
+func expiresAt(input *UpdateInput) int64 {
+	switch input.Status {
+	case ScheduleStatusSucceeded, ScheduleStatusFailed, ScheduleStatusExpired:
+	default:
+		return 0
+	}
+
+	if input.CompletedAt == nil {
+		return 0
+	}
+
+	seconds, err := strconv.ParseInt(
+		os.Getenv("SCHEDULER_RETENTION_"+input.Status+"_SECONDS"), 10, 64)
+
+	if err != nil || seconds < 1 {
+		return 0
+	}
+
+	return *input.CompletedAt + seconds
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention", func() {
+	Describe("expiresAt", func() {
+		BeforeEach(func() {
+			_ = os.Setenv("SCHEDULER_RETENTION_SUCCEEDED_SECONDS", "86400")
+			_ = os.Setenv("SCHEDULER_RETENTION_EXPIRED_SECONDS", "60")
+		})
+
+		AfterEach(func() {
+			_ = os.Unsetenv("SCHEDULER_RETENTION_SUCCEEDED_SECONDS")
+			_ = os.Unsetenv("SCHEDULER_RETENTION_EXPIRED_SECONDS")
+		})
+
+		It("adds retention of status to completion", func() {
+			Expect(expiresAt(&UpdateInput{
+				Status:      ScheduleStatusSucceeded,
+				CompletedAt: aws.Int64(1000),
+			})).To(BeEquivalentTo(87400))
+
+			Expect(expiresAt(&UpdateInput{
+				Status:      ScheduleStatusExpired,
+				CompletedAt: aws.Int64(1000),
+			})).To(BeEquivalentTo(1060))
+		})
+
+		It("keeps status without retention forever", func() {
+			Expect(expiresAt(&UpdateInput{
+				Status:      ScheduleStatusFailed,
+				CompletedAt: aws.Int64(1000),
+			})).To(BeZero())
+		})
+
+		It("keeps status that is not terminal", func() {
+			_ = os.Setenv("SCHEDULER_RETENTION_IDLE_SECONDS", "60")
+			defer func() { _ = os.Unsetenv("SCHEDULER_RETENTION_IDLE_SECONDS") }()
+
+			Expect(expiresAt(&UpdateInput{
+				Status:      ScheduleStatusIdle,
+				CompletedAt: aws.Int64(1000),
+			})).To(BeZero())
+		})
+
+		It("keeps schedule that is not completed", func() {
+			Expect(expiresAt(&UpdateInput{
+				Status: ScheduleStatusSucceeded,
+			})).To(BeZero())
+		})
+	})
+})
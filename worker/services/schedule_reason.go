@@ -0,0 +1,6 @@
+package services
+
+const (
+	ScheduleReasonDeferred    = "DEFERRED"
+	ScheduleReasonInterrupted = "INTERRUPTED"
+)
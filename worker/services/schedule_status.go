@@ -1,7 +1,9 @@
 package services
 
 const (
+	ScheduleStatusIdle      = "IDLE"
 	ScheduleStatusQueued    = "QUEUED"
 	ScheduleStatusSucceeded = "SUCCEEDED"
 	ScheduleStatusFailed    = "FAILED"
+	ScheduleStatusExpired   = "EXPIRED"
 )
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+)
+
+type SQL struct {
+	db *sql.DB
+}
+
+func NewPostgres(db *sql.DB) *SQL {
+	return &SQL{db}
+}
+
+// NewSQLite uses the same statements as Postgres, both understand them.
+func NewSQLite(db *sql.DB) *SQL {
+	return &SQL{db}
+}
+
+// Load reads the queued schedules of the given ids, the ones that are no
+// longer queued are left out.
+func (srv *SQL) Load(
+	ctx context.Context,
+	ids []string) ([]*UpdateInput, error) {
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{ScheduleStatusQueued}
+	placeholders := make([]string, len(ids))
+
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+
+	rows, err := srv.db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT id, due_at, url, method, headers, body, deadline, created_at
+			FROM schedules
+			WHERE status = $1 AND id IN (%s)
+			ORDER BY due_at`,
+			strings.Join(placeholders, ", ")),
+		args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var inputs []*UpdateInput
+
+	for rows.Next() {
+		var (
+			input    UpdateInput
+			headers  []byte
+			body     sql.NullString
+			deadline sql.NullInt64
+		)
+
+		if err = rows.Scan(
+			&input.ID,
+			&input.DueAt,
+			&input.URL,
+			&input.Method,
+			&headers,
+			&body,
+			&deadline,
+			&input.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		input.Headers = make(map[string]string)
+
+		if len(headers) > 0 {
+			if err = json.Unmarshal(headers, &input.Headers); err != nil {
+				return nil, err
+			}
+		}
+
+		input.Body = &body.String
+
+		if deadline.Valid {
+			input.Deadline = &deadline.Int64
+		}
+
+		inputs = append(inputs, &input)
+	}
+
+	return inputs, rows.Err()
+}
+
+func (srv *SQL) Update(ctx context.Context, inputs []*UpdateInput) error {
+	tx, err := srv.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	statement, err := tx.PrepareContext(
+		ctx,
+		`UPDATE schedules SET
+			due_at = $1,
+			status = $2,
+			started_at = $3,
+			completed_at = $4,
+			result = $5,
+			reason = $6,
+			lag = $7
+		WHERE id = $8`)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = statement.Close()
+	}()
+
+	for _, input := range inputs {
+		if _, err = statement.ExecContext(
+			ctx,
+			input.DueAt,
+			input.Status,
+			input.StartedAt,
+			input.CompletedAt,
+			input.Result,
+			input.Reason,
+			input.Lag,
+			input.ID); err != nil {
+			return err
+		}
+	}
+
+	if err = stats.NewSQL(tx).Add(ctx, completions(inputs)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL", func() {
+	const id = "1234567890"
+
+	var (
+		conn *sql.DB
+		mock sqlmock.Sqlmock
+		db   *SQL
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		conn, mock, err = sqlmock.New()
+		Expect(err).To(BeNil())
+
+		db = NewPostgres(conn)
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+	})
+
+	Describe("Update", func() {
+		Describe("success", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectBegin()
+				prepare := mock.ExpectPrepare(regexp.QuoteMeta(
+					"UPDATE schedules SET"))
+				prepare.ExpectExec().
+					WithArgs(
+						int64(9876543),
+						ScheduleStatusSucceeded,
+						int64(9876544),
+						int64(9876545),
+						"dummy result",
+						nil,
+						int64(1200),
+						id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schedule_stats")).
+					WithArgs(
+						int64(9874800),
+						ScheduleStatusSucceeded,
+						"foo.bar",
+						int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				err = db.Update(context.TODO(), []*UpdateInput{
+					{
+						ID:          id,
+						DueAt:       9876543,
+						URL:         "https://foo.bar/",
+						Status:      ScheduleStatusSucceeded,
+						StartedAt:   aws.Int64(9876544),
+						CompletedAt: aws.Int64(9876545),
+						Result:      aws.String("dummy result"),
+						Lag:         aws.Int64(1200),
+					},
+				})
+			})
+
+			It("updates and counts schedules in a transaction", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("fail", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectBegin()
+				prepare := mock.ExpectPrepare(regexp.QuoteMeta(
+					"UPDATE schedules SET"))
+				prepare.ExpectExec().
+					WillReturnError(fmt.Errorf("update error"))
+				mock.ExpectRollback()
+
+				err = db.Update(context.TODO(), []*UpdateInput{{ID: id}})
+			})
+
+			It("rolls back", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
+		Describe("count fail", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectBegin()
+				prepare := mock.ExpectPrepare(regexp.QuoteMeta(
+					"UPDATE schedules SET"))
+				prepare.ExpectExec().
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schedule_stats")).
+					WillReturnError(fmt.Errorf("insert error"))
+				mock.ExpectRollback()
+
+				err = db.Update(context.TODO(), []*UpdateInput{{
+					ID:          id,
+					Status:      ScheduleStatusFailed,
+					CompletedAt: aws.Int64(9876545),
+				}})
+			})
+
+			It("rolls back", func() {
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Load", func() {
+		Describe("success", func() {
+			var (
+				uis []*UpdateInput
+				err error
+			)
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("id IN ($2, $3)")).
+					WithArgs(ScheduleStatusQueued, id, "0987654321").
+					WillReturnRows(sqlmock.NewRows([]string{
+						"id",
+						"due_at",
+						"url",
+						"method",
+						"headers",
+						"body",
+						"deadline",
+						"created_at",
+					}).AddRow(
+						id,
+						int64(9876543),
+						"https://foo.bar/do",
+						"POST",
+						[]byte(`{"authorization":"token 123"}`),
+						"{}",
+						int64(9876603),
+						int64(343334232)))
+
+				uis, err = db.Load(
+					context.TODO(),
+					[]string{id, "0987654321"})
+			})
+
+			It("returns queued schedules", func() {
+				Expect(uis).To(HaveLen(1))
+				Expect(uis[0].ID).To(Equal(id))
+				Expect(uis[0].DueAt).To(BeEquivalentTo(9876543))
+				Expect(uis[0].URL).To(Equal("https://foo.bar/do"))
+				Expect(uis[0].Method).To(Equal("POST"))
+				Expect(*uis[0].Body).To(Equal("{}"))
+				Expect(*uis[0].Deadline).To(BeEquivalentTo(9876603))
+				Expect(uis[0].CreatedAt).To(BeEquivalentTo(343334232))
+			})
+
+			It("decodes headers", func() {
+				Expect(uis[0].Headers).To(Equal(map[string]string{
+					"authorization": "token 123",
+				}))
+			})
+
+			It("does not return error", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Describe("no ids", func() {
+			It("does not query", func() {
+				uis, err := db.Load(context.TODO(), nil)
+
+				Expect(uis).To(BeEmpty())
+				Expect(err).To(BeNil())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Describe("fail", func() {
+			var err error
+
+			BeforeEach(func() {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM schedules")).
+					WillReturnError(fmt.Errorf("query error"))
+
+				_, err = db.Load(context.TODO(), []string{id})
+			})
+
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+)
+
+// completions are the schedules of the inputs that reached a final status,
+// the ones put back to idle are counted again once collected.
+func completions(inputs []*UpdateInput) []stats.Transition {
+	var transitions []stats.Transition
+
+	for _, input := range inputs {
+		if input.CompletedAt == nil {
+			continue
+		}
+
+		transitions = append(transitions, stats.Transition{
+			Status: input.Status,
+			URL:    input.URL,
+			At:     time.Unix(*input.CompletedAt, 0),
+		})
+	}
+
+	return transitions
+}
+
+func statsTableName() string {
+	return os.Getenv("SCHEDULER_STATS_TABLE_NAME")
+}
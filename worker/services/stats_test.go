@@ -0,0 +1,51 @@
+package services
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/kazimanzurrashid/aws-scheduler-go/stats"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("completions", func() {
+	It("returns completed inputs", func() {
+		transitions := completions([]*UpdateInput{
+			{
+				URL:         "https://foo.bar/",
+				Status:      ScheduleStatusSucceeded,
+				CompletedAt: aws.Int64(9876545),
+			},
+			{
+				URL:    "https://foo.bar/",
+				Status: ScheduleStatusIdle,
+			},
+			{
+				URL:         "https://baz.qux/",
+				Status:      ScheduleStatusExpired,
+				CompletedAt: aws.Int64(9876546),
+			},
+		})
+
+		Expect(transitions).To(Equal([]stats.Transition{
+			{
+				Status: ScheduleStatusSucceeded,
+				URL:    "https://foo.bar/",
+				At:     time.Unix(9876545, 0),
+			},
+			{
+				Status: ScheduleStatusExpired,
+				URL:    "https://baz.qux/",
+				At:     time.Unix(9876546, 0),
+			},
+		}))
+	})
+
+	It("returns none without completed inputs", func() {
+		Expect(completions([]*UpdateInput{{Status: ScheduleStatusIdle}})).To(
+			BeEmpty())
+	})
+})
@@ -13,7 +13,12 @@ type UpdateInput struct {
 	CompletedAt *int64            `dynamodbav:"completedAt"`
 	Status      string            `dynamodbav:"status"`
 	Result      *string           `dynamodbav:"result"`
+	Reason      *string           `dynamodbav:"reason,omitempty"`
+	Lag         *int64            `dynamodbav:"lag,omitempty"`
+	Deadline    *int64            `dynamodbav:"deadline,omitempty"`
 	CreatedAt   int64             `dynamodbav:"createdAt"`
+	BodyRef     *string           `dynamodbav:"bodyRef,omitempty"`
+	ResultRef   *string           `dynamodbav:"resultRef,omitempty"`
 }
 
 func CreateUpdateInput(
@@ -36,6 +41,14 @@ func CreateUpdateInput(
 	dueAt, _ := attributes["dueAt"].Integer()
 	createdAt, _ := attributes["createdAt"].Integer()
 
+	var deadline *int64
+
+	if attr, found := attributes["deadline"]; found && !attr.IsNull() {
+		if value, err := attr.Integer(); err == nil {
+			deadline = &value
+		}
+	}
+
 	input := UpdateInput{
 		ID:        attributes["id"].String(),
 		DueAt:     dueAt,
@@ -44,6 +57,13 @@ func CreateUpdateInput(
 		Headers:   headers,
 		Body:      &body,
 		CreatedAt: createdAt,
+		Deadline:  deadline,
+	}
+
+	if attr, found := attributes["bodyRef"]; found && !attr.IsNull() {
+		ref := attr.String()
+		input.BodyRef = &ref
+		input.Body = nil
 	}
 
 	return &input
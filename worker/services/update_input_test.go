@@ -25,6 +25,7 @@ var _ = Describe("CreateUpdateInput", func() {
 				}),
 			"body":      events.NewStringAttribute("{ \"foo\": \"bar\" }"),
 			"createdAt": events.NewNumberAttribute("343334232"),
+			"deadline":  events.NewNumberAttribute("9876603"),
 
 			"startedAt":   events.NewNumberAttribute("53454344"),
 			"completedAt": events.NewNumberAttribute("2256r5454"),
@@ -59,10 +60,23 @@ var _ = Describe("CreateUpdateInput", func() {
 		Expect(*ui.Body).To(Equal("{ \"foo\": \"bar\" }"))
 	})
 
+	It("sets bodyRef without body", func() {
+		attrs["bodyRef"] = events.NewStringAttribute("bodies/1234")
+
+		ui = CreateUpdateInput(attrs)
+
+		Expect(*ui.BodyRef).To(Equal("bodies/1234"))
+		Expect(ui.Body).To(BeNil())
+	})
+
 	It("sets createdAt", func() {
 		Expect(ui.CreatedAt).To(BeEquivalentTo(343334232))
 	})
 
+	It("sets deadline", func() {
+		Expect(*ui.Deadline).To(BeEquivalentTo(9876603))
+	})
+
 	It("never sets status", func() {
 		Expect(ui.Status).To(Equal(""))
 	})